@@ -0,0 +1,103 @@
+// cmd/replay/main.go
+//
+// replay selects events from the event log and replays them through the
+// service's admin API. It runs as a dry run unless -live is given.
+//
+//	go run ./cmd/replay -api https://localhost:8080 -types image.process.request.v1 -from 2024-01-01T00:00:00Z
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type replayRequest struct {
+	EventTypes []string   `json:"event_types,omitempty"`
+	ImageID    string     `json:"image_id,omitempty"`
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+	Limit      int        `json:"limit,omitempty"`
+	DryRun     *bool      `json:"dry_run"`
+}
+
+func main() {
+	api := flag.String("api", "http://localhost:8080", "Base URL of the main service")
+	types := flag.String("types", "", "Comma separated event types")
+	imageID := flag.String("image", "", "Only events of this image")
+	from := flag.String("from", "", "RFC3339 start (inclusive)")
+	to := flag.String("to", "", "RFC3339 end (exclusive)")
+	limit := flag.Int("limit", 0, "Maximum number of events (server default 100, max 1000)")
+	live := flag.Bool("live", false, "Actually replay; without it only reports what would be replayed")
+	userID := flag.String("user", os.Getenv("REPLAY_USER_ID"), "X-User-ID header")
+	role := flag.String("role", "admin", "X-User-Role header")
+	token := flag.String("token", os.Getenv("REPLAY_TOKEN"), "Bearer token, if the API sits behind a gateway")
+	flag.Parse()
+
+	dryRun := !*live
+	req := replayRequest{
+		ImageID: *imageID,
+		Limit:   *limit,
+		DryRun:  &dryRun,
+	}
+	if *types != "" {
+		req.EventTypes = strings.Split(*types, ",")
+	}
+	req.From = parseTime("from", *from)
+	req.To = parseTime("to", *to)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		log.Fatalf("failed to encode request: %v", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, strings.TrimRight(*api, "/")+"/api/v1/admin/events/replay", bytes.NewReader(body))
+	if err != nil {
+		log.Fatalf("failed to build request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-User-ID", *userID)
+	httpReq.Header.Set("X-User-Role", *role)
+	if *token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+*token)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		log.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatalf("failed to read response: %v", err)
+	}
+
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, out, "", "  ") == nil {
+		out = pretty.Bytes()
+	}
+	fmt.Println(string(out))
+
+	if resp.StatusCode != http.StatusOK {
+		os.Exit(1)
+	}
+}
+
+func parseTime(name, raw string) *time.Time {
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		log.Fatalf("-%s must be an RFC3339 timestamp: %v", name, err)
+	}
+	return &t
+}
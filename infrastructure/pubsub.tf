@@ -223,3 +223,28 @@ resource "google_pubsub_subscription" "image_process_dlq_sub" {
     managed_by = "terraform"
   }
 }
+
+# ----------------------------------------
+# 6. ENTITY LIFECYCLE EVENTS
+# ----------------------------------------
+locals {
+  entity_event_topics = toset([
+    "workspace-events",
+    "patient-events",
+    "image-events",
+    "annotation-events",
+    "annotation-type-events",
+  ])
+}
+
+resource "google_pubsub_topic" "entity_events" {
+  for_each = local.entity_event_topics
+  name     = "${local.pubsub_prefix}${each.key}"
+
+  labels = {
+    service    = "main-service"
+    managed_by = "terraform"
+  }
+
+  message_retention_duration = "604800s" # 7 days
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUByteCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUByteCache(10, 6)
+
+	c.Set(ctx, "img-1", "a", make([]byte, 3))
+	c.Set(ctx, "img-2", "b", make([]byte, 3))
+	c.Set(ctx, "img-2", "c", make([]byte, 3))
+
+	// Touching a makes b the least recently used
+	_, ok := c.Get(ctx, "img-1", "a")
+	assert.True(t, ok)
+	c.Set(ctx, "img-3", "d", make([]byte, 3))
+	_, ok = c.Get(ctx, "img-2", "b")
+	assert.False(t, ok)
+
+	// img-2 may hold only 6 bytes, so its own oldest entry goes first
+	c.Set(ctx, "img-2", "e", make([]byte, 4))
+	_, ok = c.Get(ctx, "img-2", "c")
+	assert.False(t, ok)
+	_, ok = c.Get(ctx, "img-1", "a")
+	assert.True(t, ok)
+
+	// Larger than an image may hold
+	c.Set(ctx, "img-4", "f", make([]byte, 7))
+	_, ok = c.Get(ctx, "img-4", "f")
+	assert.False(t, ok)
+
+	stats, err := c.GetStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), stats.Bytes)
+	assert.Equal(t, int64(3), stats.Size)
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(3), stats.Misses)
+	assert.Equal(t, int64(2), stats.Evictions)
+
+	assert.Equal(t, 1, c.DeleteImage(ctx, "img-2"))
+	stats, _ = c.GetStats(ctx)
+	assert.Equal(t, int64(6), stats.Bytes)
+}
+
+func TestTieredByteCache_DiskSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	disk, err := NewDiskByteCache(dir, 100, 100, logger)
+	assert.NoError(t, err)
+	tiered := NewTieredByteCache(NewLRUByteCache(10, 10), disk)
+	tiered.Set(ctx, "img-1", "tile-a", []byte("aaaa"))
+	tiered.Set(ctx, "img-1", "tile-b", []byte("bbbbbbbb"))
+
+	// tile-a no longer fits in memory but is still on disk
+	data, ok := tiered.Get(ctx, "img-1", "tile-a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("aaaa"), data)
+
+	reopened, err := NewDiskByteCache(dir, 100, 100, logger)
+	assert.NoError(t, err)
+	data, ok = reopened.Get(ctx, "img-1", "tile-b")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("bbbbbbbb"), data)
+
+	stats, err := reopened.GetStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Size)
+	assert.Equal(t, int64(12), stats.Bytes)
+
+	assert.Equal(t, 2, reopened.DeleteImage(ctx, "img-1"))
+	_, ok = reopened.Get(ctx, "img-1", "tile-a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/histopathai/main-service/internal/port/cache"
+)
+
+// DiskByteCache is a cache.ByteCache on local disk bounded by payload bytes.
+// Payloads are stored as <dir>/<image hash>/<key hash>; the recency index is
+// kept in memory and rebuilt from file modification times on startup.
+type DiskByteCache struct {
+	mu     sync.Mutex
+	dir    string
+	index  *lruIndex
+	stats  cacheStats
+	logger *slog.Logger
+}
+
+func NewDiskByteCache(dir string, maxBytes, maxImageBytes int64, logger *slog.Logger) (*DiskByteCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create tile cache directory: %w", err)
+	}
+
+	c := &DiskByteCache{
+		dir:    dir,
+		index:  newLRUIndex(maxBytes, maxImageBytes),
+		logger: logger.WithGroup("disk_cache"),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load indexes the files left by a previous run, oldest first, so the most
+// recently used ones survive if the budget shrank.
+func (c *DiskByteCache) load() error {
+	type file struct {
+		image string
+		name  string
+		info  os.FileInfo
+	}
+	var files []file
+
+	imageDirs, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read tile cache directory: %w", err)
+	}
+	for _, imageDir := range imageDirs {
+		if !imageDir.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(c.dir, imageDir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if strings.HasSuffix(entry.Name(), ".tmp") {
+				// Left by an interrupted write
+				_ = os.Remove(filepath.Join(c.dir, imageDir.Name(), entry.Name()))
+				continue
+			}
+			files = append(files, file{image: imageDir.Name(), name: entry.Name(), info: info})
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].info.ModTime().Before(files[j].info.ModTime()) })
+	for _, f := range files {
+		evicted, ok := c.index.add(f.name, f.image, f.info.Size())
+		c.removeFiles(evicted)
+		if !ok {
+			_ = os.Remove(filepath.Join(c.dir, f.image, f.name))
+		}
+	}
+	return nil
+}
+
+func (c *DiskByteCache) Get(ctx context.Context, imageID, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := hashName(key)
+	if !c.index.touch(name) {
+		c.stats.misses++
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, hashName(imageID), name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.index.remove(name)
+		c.stats.misses++
+		return nil, false
+	}
+	// Keeps the order across restarts
+	_ = touchFile(path)
+	c.stats.hits++
+	return data, true
+}
+
+func (c *DiskByteCache) Set(ctx context.Context, imageID, key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name, image := hashName(key), hashName(imageID)
+	evicted, ok := c.index.add(name, image, int64(len(data)))
+	c.removeFiles(evicted)
+	c.stats.evictions += int64(len(evicted))
+	if !ok {
+		return
+	}
+
+	if err := c.write(filepath.Join(c.dir, image, name), data); err != nil {
+		c.index.remove(name)
+		c.logger.Warn("Failed to write tile cache entry", slog.String("error", err.Error()))
+	}
+}
+
+func (c *DiskByteCache) DeleteImage(ctx context.Context, imageID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	image := hashName(imageID)
+	keys := c.index.removeImage(image)
+	_ = os.RemoveAll(filepath.Join(c.dir, image))
+	return len(keys)
+}
+
+func (c *DiskByteCache) GetStats(ctx context.Context) (*cache.Stats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return &cache.Stats{
+		Hits:      c.stats.hits,
+		Misses:    c.stats.misses,
+		Size:      int64(c.index.len()),
+		Evictions: c.stats.evictions,
+		Bytes:     c.index.bytes,
+	}, nil
+}
+
+// write stores data through a temporary file so readers never see partial payloads.
+func (c *DiskByteCache) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *DiskByteCache) removeFiles(entries []indexEntry) {
+	for _, e := range entries {
+		_ = os.Remove(filepath.Join(c.dir, e.imageID, e.key))
+	}
+}
+
+func touchFile(path string) error {
+	now := time.Now()
+	return os.Chtimes(path, now, now)
+}
+
+func hashName(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
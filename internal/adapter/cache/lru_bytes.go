@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/histopathai/main-service/internal/port/cache"
+)
+
+// LRUByteCache is an in-memory cache.ByteCache bounded by payload bytes
+type LRUByteCache struct {
+	mu    sync.Mutex
+	index *lruIndex
+	data  map[string][]byte
+	stats cacheStats
+}
+
+func NewLRUByteCache(maxBytes, maxImageBytes int64) *LRUByteCache {
+	return &LRUByteCache{
+		index: newLRUIndex(maxBytes, maxImageBytes),
+		data:  make(map[string][]byte),
+	}
+}
+
+func (c *LRUByteCache) Get(ctx context.Context, imageID, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.index.touch(key) {
+		c.stats.misses++
+		return nil, false
+	}
+	c.stats.hits++
+	return c.data[key], true
+}
+
+func (c *LRUByteCache) Set(ctx context.Context, imageID, key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	evicted, ok := c.index.add(key, imageID, int64(len(data)))
+	for _, e := range evicted {
+		delete(c.data, e.key)
+	}
+	c.stats.evictions += int64(len(evicted))
+	if ok {
+		c.data[key] = data
+	}
+}
+
+func (c *LRUByteCache) DeleteImage(ctx context.Context, imageID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := c.index.removeImage(imageID)
+	for _, k := range keys {
+		delete(c.data, k)
+	}
+	return len(keys)
+}
+
+func (c *LRUByteCache) GetStats(ctx context.Context) (*cache.Stats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return &cache.Stats{
+		Hits:      c.stats.hits,
+		Misses:    c.stats.misses,
+		Size:      int64(c.index.len()),
+		Evictions: c.stats.evictions,
+		Bytes:     c.index.bytes,
+	}, nil
+}
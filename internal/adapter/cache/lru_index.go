@@ -0,0 +1,132 @@
+package cache
+
+import "container/list"
+
+// lruIndex tracks entry sizes in recency order within a total and a
+// per-image byte budget. It holds no payloads; callers store and drop
+// those for the keys it admits and evicts.
+type lruIndex struct {
+	maxBytes      int64
+	maxImageBytes int64
+
+	bytes   int64
+	order   *list.List // most recent first
+	entries map[string]*list.Element
+	images  map[string]*imageUsage
+}
+
+type indexEntry struct {
+	key     string
+	imageID string
+	size    int64
+	// Position in the image's own recency list
+	imageElem *list.Element
+}
+
+type imageUsage struct {
+	bytes int64
+	order *list.List
+}
+
+func newLRUIndex(maxBytes, maxImageBytes int64) *lruIndex {
+	if maxImageBytes <= 0 || maxImageBytes > maxBytes {
+		maxImageBytes = maxBytes
+	}
+	return &lruIndex{
+		maxBytes:      maxBytes,
+		maxImageBytes: maxImageBytes,
+		order:         list.New(),
+		entries:       make(map[string]*list.Element),
+		images:        make(map[string]*imageUsage),
+	}
+}
+
+// touch marks key as most recently used and reports whether it is indexed.
+func (x *lruIndex) touch(key string) bool {
+	elem, ok := x.entries[key]
+	if !ok {
+		return false
+	}
+	x.order.MoveToFront(elem)
+	entry := elem.Value.(*indexEntry)
+	x.images[entry.imageID].order.MoveToFront(entry.imageElem)
+	return true
+}
+
+// add indexes key, replacing any previous size, and returns the entries
+// evicted to make room. ok is false when size exceeds the limits; nothing is
+// evicted then.
+func (x *lruIndex) add(key, imageID string, size int64) (evicted []indexEntry, ok bool) {
+	if size > x.maxImageBytes {
+		return nil, false
+	}
+	x.remove(key)
+
+	usage := x.images[imageID]
+	if usage == nil {
+		usage = &imageUsage{order: list.New()}
+		x.images[imageID] = usage
+	}
+	for usage.bytes+size > x.maxImageBytes {
+		oldest := usage.order.Back().Value.(*list.Element).Value.(*indexEntry)
+		evicted = append(evicted, *oldest)
+		x.remove(oldest.key)
+	}
+	for x.bytes+size > x.maxBytes {
+		oldest := x.order.Back().Value.(*indexEntry)
+		evicted = append(evicted, *oldest)
+		x.remove(oldest.key)
+	}
+
+	// remove may have dropped the image's usage along with its last entry
+	usage = x.images[imageID]
+	if usage == nil {
+		usage = &imageUsage{order: list.New()}
+		x.images[imageID] = usage
+	}
+	entry := &indexEntry{key: key, imageID: imageID, size: size}
+	elem := x.order.PushFront(entry)
+	entry.imageElem = usage.order.PushFront(elem)
+	x.entries[key] = elem
+	x.bytes += size
+	usage.bytes += size
+	return evicted, true
+}
+
+func (x *lruIndex) remove(key string) bool {
+	elem, ok := x.entries[key]
+	if !ok {
+		return false
+	}
+	entry := elem.Value.(*indexEntry)
+	usage := x.images[entry.imageID]
+	usage.order.Remove(entry.imageElem)
+	usage.bytes -= entry.size
+	if usage.order.Len() == 0 {
+		delete(x.images, entry.imageID)
+	}
+	x.order.Remove(elem)
+	delete(x.entries, key)
+	x.bytes -= entry.size
+	return true
+}
+
+// removeImage drops all of the image's keys and returns them.
+func (x *lruIndex) removeImage(imageID string) []string {
+	usage := x.images[imageID]
+	if usage == nil {
+		return nil
+	}
+	keys := make([]string, 0, usage.order.Len())
+	for e := usage.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*list.Element).Value.(*indexEntry).key)
+	}
+	for _, key := range keys {
+		x.remove(key)
+	}
+	return keys
+}
+
+func (x *lruIndex) len() int {
+	return len(x.entries)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/histopathai/main-service/internal/port/cache"
+)
+
+// TieredByteCache puts a memory cache in front of a larger disk cache. Disk
+// hits are promoted to memory; writes go to both tiers.
+type TieredByteCache struct {
+	memory cache.ByteCache
+	disk   cache.ByteCache
+
+	mu    sync.Mutex
+	stats cacheStats
+}
+
+func NewTieredByteCache(memory, disk cache.ByteCache) *TieredByteCache {
+	return &TieredByteCache{memory: memory, disk: disk}
+}
+
+func (c *TieredByteCache) Get(ctx context.Context, imageID, key string) ([]byte, bool) {
+	if data, ok := c.memory.Get(ctx, imageID, key); ok {
+		c.record(true)
+		return data, true
+	}
+	data, ok := c.disk.Get(ctx, imageID, key)
+	if ok {
+		c.memory.Set(ctx, imageID, key, data)
+	}
+	c.record(ok)
+	return data, ok
+}
+
+func (c *TieredByteCache) Set(ctx context.Context, imageID, key string, data []byte) {
+	c.memory.Set(ctx, imageID, key, data)
+	c.disk.Set(ctx, imageID, key, data)
+}
+
+func (c *TieredByteCache) DeleteImage(ctx context.Context, imageID string) int {
+	memory := c.memory.DeleteImage(ctx, imageID)
+	disk := c.disk.DeleteImage(ctx, imageID)
+	return max(memory, disk)
+}
+
+// GetStats reports hits in either tier; Size and Bytes are the disk tier's,
+// which holds every cached entry.
+func (c *TieredByteCache) GetStats(ctx context.Context) (*cache.Stats, error) {
+	disk, err := c.disk.GetStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &cache.Stats{
+		Hits:      c.stats.hits,
+		Misses:    c.stats.misses,
+		Size:      disk.Size,
+		Evictions: disk.Evictions,
+		Bytes:     disk.Bytes,
+	}, nil
+}
+
+func (c *TieredByteCache) record(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hit {
+		c.stats.hits++
+	} else {
+		c.stats.misses++
+	}
+}
@@ -0,0 +1,247 @@
+// adapter/event/pubsub/cloudevents.go
+package pubsub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+)
+
+// EventEncoding selects the wire format used when publishing events.
+type EventEncoding string
+
+const (
+	// EncodingLegacy publishes the plain DTO with event_type/event_id attributes.
+	EncodingLegacy EventEncoding = "legacy"
+	// EncodingStructured publishes a CloudEvents 1.0 JSON envelope as message data.
+	EncodingStructured EventEncoding = "structured"
+	// EncodingBinary publishes the DTO as data and CloudEvents context as ce-* attributes.
+	EncodingBinary EventEncoding = "binary"
+)
+
+func (e EventEncoding) IsValid() bool {
+	switch e {
+	case EncodingLegacy, EncodingStructured, EncodingBinary:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	cloudEventsSpecVersion     = "1.0"
+	cloudEventsTypePrefix      = "com.histopathai."
+	cloudEventsContentType     = "application/cloudevents+json"
+	cloudEventsDataContentType = "application/json"
+
+	// Pub/Sub protocol binding attribute names
+	ceAttrSpecVersion = "ce-specversion"
+	ceAttrID          = "ce-id"
+	ceAttrSource      = "ce-source"
+	ceAttrType        = "ce-type"
+	ceAttrSubject     = "ce-subject"
+	ceAttrTime        = "ce-time"
+	ceAttrDataSchema  = "ce-dataschema"
+	attrContentType   = "content-type"
+
+	// Legacy attribute names
+	attrEventType = "event_type"
+	attrEventID   = "event_id"
+	attrTimestamp = "timestamp"
+)
+
+// ErrUnknownEventFormat is returned when a message carries neither CloudEvents
+// context nor a legacy event_type attribute.
+var ErrUnknownEventFormat = errors.New("event type not found in message")
+
+// cloudEvent is the structured-mode CloudEvents 1.0 envelope.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// MessageCodec converts domain events to Pub/Sub payloads and back.
+// Encoding is chosen by configuration; decoding accepts every supported
+// format so producers can migrate independently of consumers.
+type MessageCodec struct {
+	serializer        *EventSerializer
+	encoding          EventEncoding
+	source            string
+	dataSchemaBaseURL string
+}
+
+func NewMessageCodec(serializer *EventSerializer, encoding EventEncoding, source string, dataSchemaBaseURL string) *MessageCodec {
+	if !encoding.IsValid() {
+		encoding = EncodingLegacy
+	}
+	return &MessageCodec{
+		serializer:        serializer,
+		encoding:          encoding,
+		source:            source,
+		dataSchemaBaseURL: strings.TrimSuffix(dataSchemaBaseURL, "/"),
+	}
+}
+
+// Encode returns the message data and attributes for the configured encoding.
+func (c *MessageCodec) Encode(event domainevent.Event) ([]byte, map[string]string, error) {
+	data, err := c.serializer.Serialize(event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Legacy attributes are always set so that existing subscribers and
+	// push endpoints keep routing on event_type during the migration.
+	attrs := map[string]string{
+		attrEventType: string(event.GetEventType()),
+		attrEventID:   event.GetEventID(),
+		attrTimestamp: event.GetTimestamp().Format(time.RFC3339),
+	}
+
+	switch c.encoding {
+	case EncodingStructured:
+		envelope := c.envelope(event)
+		envelope.Data = data
+		body, err := json.Marshal(envelope)
+		if err != nil {
+			return nil, nil, err
+		}
+		attrs[attrContentType] = cloudEventsContentType
+		return body, attrs, nil
+
+	case EncodingBinary:
+		envelope := c.envelope(event)
+		attrs[ceAttrSpecVersion] = envelope.SpecVersion
+		attrs[ceAttrID] = envelope.ID
+		attrs[ceAttrSource] = envelope.Source
+		attrs[ceAttrType] = envelope.Type
+		attrs[ceAttrTime] = envelope.Time
+		attrs[attrContentType] = envelope.DataContentType
+		if envelope.Subject != "" {
+			attrs[ceAttrSubject] = envelope.Subject
+		}
+		if envelope.DataSchema != "" {
+			attrs[ceAttrDataSchema] = envelope.DataSchema
+		}
+		return data, attrs, nil
+
+	default:
+		return data, attrs, nil
+	}
+}
+
+// Marshal renders the event as a structured-mode CloudEvents document,
+// independent of the configured Pub/Sub encoding. Used for webhook payloads.
+func (c *MessageCodec) Marshal(event domainevent.Event) ([]byte, error) {
+	data, err := c.serializer.Serialize(event)
+	if err != nil {
+		return nil, err
+	}
+	envelope := c.envelope(event)
+	envelope.Data = data
+	return json.Marshal(envelope)
+}
+
+// Decode detects the message format and returns the domain event.
+// It returns ErrUnknownEventFormat when no event type can be determined.
+func (c *MessageCodec) Decode(data []byte, attrs map[string]string) (domainevent.Event, error) {
+	// 1. Binary mode: context lives in ce-* attributes
+	if attrs[ceAttrSpecVersion] != "" {
+		eventType, err := eventTypeFromCloudEventType(attrs[ceAttrType])
+		if err != nil {
+			return nil, err
+		}
+		return c.serializer.Deserialize(data, eventType)
+	}
+
+	// 2. Structured mode: announced by content-type, or sniffed from the payload
+	if strings.HasPrefix(attrs[attrContentType], cloudEventsContentType) || looksLikeCloudEvent(data) {
+		var envelope cloudEvent
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return nil, fmt.Errorf("failed to parse cloudevent envelope: %w", err)
+		}
+		if envelope.SpecVersion != cloudEventsSpecVersion {
+			return nil, fmt.Errorf("unsupported cloudevents specversion: %q", envelope.SpecVersion)
+		}
+		eventType, err := eventTypeFromCloudEventType(envelope.Type)
+		if err != nil {
+			return nil, err
+		}
+		return c.serializer.Deserialize(envelope.Data, eventType)
+	}
+
+	// 3. Legacy mode: event type in attributes
+	eventType := attrs[attrEventType]
+	if eventType == "" {
+		return nil, ErrUnknownEventFormat
+	}
+	return c.serializer.Deserialize(data, domainevent.EventType(eventType))
+}
+
+func (c *MessageCodec) envelope(event domainevent.Event) cloudEvent {
+	envelope := cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              event.GetEventID(),
+		Source:          c.source,
+		Type:            CloudEventType(event.GetEventType()),
+		Subject:         EventSubject(event),
+		Time:            event.GetTimestamp().UTC().Format(time.RFC3339Nano),
+		DataContentType: cloudEventsDataContentType,
+	}
+	if c.dataSchemaBaseURL != "" {
+		envelope.DataSchema = fmt.Sprintf("%s/%s.json", c.dataSchemaBaseURL, event.GetEventType())
+	}
+	return envelope
+}
+
+// CloudEventType maps a domain event type to its reverse-DNS CloudEvents type.
+// The version suffix of the domain type is preserved, e.g.
+// "image.process.request.v1" -> "com.histopathai.image.process.request.v1".
+func CloudEventType(eventType domainevent.EventType) string {
+	return cloudEventsTypePrefix + string(eventType)
+}
+
+func eventTypeFromCloudEventType(ceType string) (domainevent.EventType, error) {
+	if !strings.HasPrefix(ceType, cloudEventsTypePrefix) {
+		return "", fmt.Errorf("unsupported cloudevents type: %q", ceType)
+	}
+	return domainevent.EventType(strings.TrimPrefix(ceType, cloudEventsTypePrefix)), nil
+}
+
+// EventSubject returns the ID of the entity the event is about.
+func EventSubject(event domainevent.Event) string {
+	switch e := event.(type) {
+	case *domainevent.NewFileExistEvent:
+		return e.Content.ID
+	case *domainevent.DeleteFileEvent:
+		return e.Content.ID
+	case *domainevent.ImageProcessReqEvent:
+		return e.Content.Parent.ID
+	case *domainevent.ImageProcessCompleteEvent:
+		return e.ImageID
+	case *domainevent.EntityEvent:
+		return e.EntityID
+	default:
+		return ""
+	}
+}
+
+func looksLikeCloudEvent(data []byte) bool {
+	var probe struct {
+		SpecVersion string `json:"specversion"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return probe.SpecVersion != ""
+}
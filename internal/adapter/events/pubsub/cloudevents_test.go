@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProcessReqEvent() *domainevent.ImageProcessReqEvent {
+	return &domainevent.ImageProcessReqEvent{
+		BaseEvent: domainevent.BaseEvent{
+			EventID:   "evt-1",
+			EventType: domainevent.ImageProcessReqEventType,
+			Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Content: model.Content{
+			Entity: vobj.Entity{
+				ID:         "content-1",
+				Name:       "slide.svs",
+				EntityType: vobj.EntityTypeContent,
+				Parent:     vobj.ParentRef{ID: "img-1", Type: vobj.ParentTypeImage},
+			},
+			Provider:    vobj.ContentProviderGCS,
+			Path:        "img-1-slide.svs",
+			ContentType: vobj.ContentTypeImageSVS,
+			Size:        42,
+		},
+		ProcessingVersion: vobj.ProcessingV2,
+	}
+}
+
+func TestMessageCodec_Structured_RoundTrip(t *testing.T) {
+	codec := NewMessageCodec(NewEventSerializer(), EncodingStructured, "//test/main-service", "https://schemas.test/events/")
+
+	data, attrs, err := codec.Encode(newTestProcessReqEvent())
+	assert.NoError(t, err)
+	assert.Equal(t, cloudEventsContentType, attrs[attrContentType])
+
+	var envelope map[string]any
+	assert.NoError(t, json.Unmarshal(data, &envelope))
+	assert.Equal(t, "1.0", envelope["specversion"])
+	assert.Equal(t, "com.histopathai.image.process.request.v1", envelope["type"])
+	assert.Equal(t, "//test/main-service", envelope["source"])
+	assert.Equal(t, "img-1", envelope["subject"])
+	assert.Equal(t, "https://schemas.test/events/image.process.request.v1.json", envelope["dataschema"])
+
+	decoded, err := codec.Decode(data, map[string]string{attrContentType: cloudEventsContentType})
+	assert.NoError(t, err)
+	reqEvent, ok := decoded.(*domainevent.ImageProcessReqEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "evt-1", reqEvent.EventID)
+	assert.Equal(t, "img-1", reqEvent.Content.Parent.ID)
+	assert.Equal(t, vobj.ProcessingV2, reqEvent.ProcessingVersion)
+}
+
+func TestMessageCodec_Binary_RoundTrip(t *testing.T) {
+	codec := NewMessageCodec(NewEventSerializer(), EncodingBinary, "//test/main-service", "")
+
+	data, attrs, err := codec.Encode(newTestProcessReqEvent())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", attrs[ceAttrSpecVersion])
+	assert.Equal(t, "com.histopathai.image.process.request.v1", attrs[ceAttrType])
+	assert.Equal(t, "img-1", attrs[ceAttrSubject])
+	assert.Equal(t, "evt-1", attrs[ceAttrID])
+	assert.NotContains(t, attrs, ceAttrDataSchema)
+
+	// Only CloudEvents context, no legacy attributes
+	decoded, err := codec.Decode(data, map[string]string{
+		ceAttrSpecVersion: attrs[ceAttrSpecVersion],
+		ceAttrType:        attrs[ceAttrType],
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "evt-1", decoded.GetEventID())
+}
+
+func TestMessageCodec_DecodesLegacyFormat(t *testing.T) {
+	legacy := NewMessageCodec(NewEventSerializer(), EncodingLegacy, "", "")
+	data, attrs, err := legacy.Encode(newTestProcessReqEvent())
+	assert.NoError(t, err)
+	assert.Equal(t, string(domainevent.ImageProcessReqEventType), attrs[attrEventType])
+	assert.NotContains(t, attrs, ceAttrSpecVersion)
+
+	// A consumer configured for CloudEvents still accepts legacy messages
+	consumer := NewMessageCodec(NewEventSerializer(), EncodingStructured, "", "")
+	decoded, err := consumer.Decode(data, attrs)
+	assert.NoError(t, err)
+	assert.Equal(t, "evt-1", decoded.GetEventID())
+
+	_, err = consumer.Decode(data, map[string]string{})
+	assert.ErrorIs(t, err, ErrUnknownEventFormat)
+}
@@ -3,35 +3,40 @@ package pubsub
 
 import (
 	"context"
-	"time"
 
 	"cloud.google.com/go/pubsub"
 	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	portevent "github.com/histopathai/main-service/internal/port/event"
+	"github.com/histopathai/main-service/pkg/config"
 )
 
 type PubSubPublisher struct {
 	client        *pubsub.Client
-	serializer    *EventSerializer
+	codec         *MessageCodec
 	topicResolver portevent.TopicResolver
 }
 
-func NewPubSubPublisher(ctx context.Context, projectID string, topicMapping map[domainevent.EventType]string) (*PubSubPublisher, error) {
+func NewPubSubPublisher(ctx context.Context, projectID string, topicMapping map[domainevent.EventType]string, eventsCfg config.EventsConfig) (*PubSubPublisher, error) {
 	client, err := pubsub.NewClient(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &PubSubPublisher{
-		client:        client,
-		serializer:    NewEventSerializer(),
+		client: client,
+		codec: NewMessageCodec(
+			NewEventSerializer(),
+			EventEncoding(eventsCfg.Encoding),
+			eventsCfg.Source,
+			eventsCfg.DataSchemaBaseURL,
+		),
 		topicResolver: NewTopicResolver(topicMapping),
 	}, nil
 }
 
 func (p *PubSubPublisher) Publish(ctx context.Context, event domainevent.Event) error {
-	// 1. Serialize event
-	data, err := p.serializer.Serialize(event)
+	// 1. Encode event
+	data, attributes, err := p.codec.Encode(event)
 	if err != nil {
 		return err
 	}
@@ -43,12 +48,8 @@ func (p *PubSubPublisher) Publish(ctx context.Context, event domainevent.Event)
 
 	// 3. Prepare message
 	msg := &pubsub.Message{
-		Data: data,
-		Attributes: map[string]string{
-			"event_type": string(event.GetEventType()),
-			"event_id":   event.GetEventID(),
-			"timestamp":  event.GetTimestamp().Format(time.RFC3339),
-		},
+		Data:       data,
+		Attributes: attributes,
 	}
 
 	// 4. Publish
@@ -45,6 +45,8 @@ func (s *EventSerializer) Serialize(event domainevent.Event) ([]byte, error) {
 			Timestamp:         e.Timestamp.Format(time.RFC3339),
 			Content:           contentToDTO(e.Content),
 			ProcessingVersion: string(e.ProcessingVersion),
+			ProcessingProfile: string(e.ProcessingProfile),
+			Priority:          string(e.Priority),
 		}
 
 	case *domainevent.ImageProcessCompleteEvent:
@@ -63,15 +65,21 @@ func (s *EventSerializer) Serialize(event domainevent.Event) ([]byte, error) {
 		}
 
 		dto = imageProcessCompleteDTO{
-			EventID:       e.EventID,
-			EventType:     string(e.EventType),
-			Timestamp:     e.Timestamp.Format(time.RFC3339),
-			Contents:      dtoContents,
-			Success:       e.Success,
-			Result:        result,
-			FailureReason: e.FailureReason,
-			ImageID:       e.ImageID,
+			EventID:           e.EventID,
+			EventType:         string(e.EventType),
+			Timestamp:         e.Timestamp.Format(time.RFC3339),
+			Contents:          dtoContents,
+			Success:           e.Success,
+			Result:            result,
+			FailureReason:     e.FailureReason,
+			ImageID:           e.ImageID,
+			RequestEventID:    e.RequestEventID,
+			ProcessingVersion: e.ProcessingVersion.String(),
 		}
+
+	case *domainevent.EntityEvent:
+		dto = entityEventToDTO(e)
+
 	default:
 		return nil, fmt.Errorf("unsupported event type: %T", event)
 	}
@@ -113,6 +121,13 @@ func (s *EventSerializer) Deserialize(data []byte, eventType domainevent.EventTy
 		return s.processingCompletedDTOToDomain(dto)
 
 	default:
+		if domainevent.IsEntityEventType(eventType) {
+			var dto entityEventDTO
+			if err := json.Unmarshal(data, &dto); err != nil {
+				return nil, err
+			}
+			return s.entityEventDTOToDomain(dto)
+		}
 		return nil, fmt.Errorf("unsupported event type: %s", eventType)
 	}
 }
@@ -138,6 +153,8 @@ type imageProcessDTO struct {
 	ID                string     `json:"id"`
 	Content           contentDTO `json:"content"`
 	ProcessingVersion string     `json:"processing_version"`
+	ProcessingProfile string     `json:"processing_profile,omitempty"`
+	Priority          string     `json:"priority,omitempty"`
 }
 
 type retryMetadataDTO struct {
@@ -149,16 +166,31 @@ type retryMetadataDTO struct {
 }
 
 type imageProcessCompleteDTO struct {
-	EventID       string               `json:"event_id"`
-	EventType     string               `json:"event_type"`
-	Timestamp     string               `json:"timestamp"`
-	ImageID       string               `json:"image_id"`
-	Contents      []contentDTO         `json:"contents"`
-	Success       bool                 `json:"success"`
-	Result        *processingResultDTO `json:"result,omitempty"`
-	FailureReason string               `json:"failure_reason,omitempty"`
-	Retryable     bool                 `json:"retryable"`
-	RetryMetadata *retryMetadataDTO    `json:"retry_metadata,omitempty"`
+	EventID           string               `json:"event_id"`
+	EventType         string               `json:"event_type"`
+	Timestamp         string               `json:"timestamp"`
+	ImageID           string               `json:"image_id"`
+	RequestEventID    string               `json:"request_event_id,omitempty"`
+	ProcessingVersion string               `json:"processing_version,omitempty"`
+	Contents          []contentDTO         `json:"contents"`
+	Success           bool                 `json:"success"`
+	Result            *processingResultDTO `json:"result,omitempty"`
+	FailureReason     string               `json:"failure_reason,omitempty"`
+	Retryable         bool                 `json:"retryable"`
+	RetryMetadata     *retryMetadataDTO    `json:"retry_metadata,omitempty"`
+}
+
+type entityEventDTO struct {
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Timestamp      string     `json:"timestamp"`
+	Action         string     `json:"action"`
+	EntityType     string     `json:"entity_type"`
+	EntityID       string     `json:"entity_id"`
+	WsID           string     `json:"ws_id,omitempty"`
+	Parent         *parentDTO `json:"parent,omitempty"`
+	PreviousParent *parentDTO `json:"previous_parent,omitempty"`
+	ChangedFields  []string   `json:"changed_fields,omitempty"`
 }
 
 type processingResultDTO struct {
@@ -291,6 +323,8 @@ func (s *EventSerializer) processingRequestedDTOToDomain(dto imageProcessDTO) (*
 		},
 		Content:           dtoToContent(dto.Content),
 		ProcessingVersion: vobj.ProcessingVersion(dto.ProcessingVersion),
+		ProcessingProfile: vobj.ProcessingProfile(dto.ProcessingProfile),
+		Priority:          vobj.ProcessingPriority(dto.Priority),
 	}, nil
 }
 
@@ -315,7 +349,9 @@ func (s *EventSerializer) processingCompletedDTOToDomain(dto imageProcessComplet
 			EventType: domainevent.EventType(dto.EventType),
 			Timestamp: timestamp,
 		},
-		ImageID: dto.ImageID,
+		ImageID:           dto.ImageID,
+		RequestEventID:    dto.RequestEventID,
+		ProcessingVersion: vobj.ProcessingVersion(dto.ProcessingVersion),
 		Contents: func() []model.Content {
 			var contents []model.Content
 			for _, c := range dto.Contents {
@@ -329,6 +365,53 @@ func (s *EventSerializer) processingCompletedDTOToDomain(dto imageProcessComplet
 	}, nil
 }
 
+func entityEventToDTO(e *domainevent.EntityEvent) entityEventDTO {
+	dto := entityEventDTO{
+		EventID:       e.EventID,
+		EventType:     string(e.EventType),
+		Timestamp:     e.Timestamp.Format(time.RFC3339),
+		Action:        string(e.Action),
+		EntityType:    string(e.EntityType),
+		EntityID:      e.EntityID,
+		WsID:          e.WsID,
+		ChangedFields: e.ChangedFields,
+	}
+	if e.Parent.ID != "" {
+		dto.Parent = &parentDTO{ID: e.Parent.ID, Type: string(e.Parent.Type)}
+	}
+	if e.PreviousParent != nil {
+		dto.PreviousParent = &parentDTO{ID: e.PreviousParent.ID, Type: string(e.PreviousParent.Type)}
+	}
+	return dto
+}
+
+func (s *EventSerializer) entityEventDTOToDomain(dto entityEventDTO) (*domainevent.EntityEvent, error) {
+	timestamp, err := time.Parse(time.RFC3339, dto.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &domainevent.EntityEvent{
+		BaseEvent: domainevent.BaseEvent{
+			EventID:   dto.EventID,
+			EventType: domainevent.EventType(dto.EventType),
+			Timestamp: timestamp,
+		},
+		Action:        domainevent.EntityAction(dto.Action),
+		EntityType:    vobj.EntityType(dto.EntityType),
+		EntityID:      dto.EntityID,
+		WsID:          dto.WsID,
+		ChangedFields: dto.ChangedFields,
+	}
+	if dto.Parent != nil {
+		e.Parent = vobj.ParentRef{ID: dto.Parent.ID, Type: vobj.ParentType(dto.Parent.Type)}
+	}
+	if dto.PreviousParent != nil {
+		e.PreviousParent = &vobj.ParentRef{ID: dto.PreviousParent.ID, Type: vobj.ParentType(dto.PreviousParent.Type)}
+	}
+	return e, nil
+}
+
 func (s *EventSerializer) parseGCSNotificationToNewFileExistEvent(data []byte) (*domainevent.NewFileExistEvent, error) {
 	// GCS notification yapısı
 	var gcsNotif struct {
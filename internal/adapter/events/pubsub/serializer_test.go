@@ -66,7 +66,9 @@ func TestEventSerializer_ImageProcessCompleteEvent_RoundTrip(t *testing.T) {
 			EventType: domainevent.ImageProcessCompleteEventType,
 			Timestamp: time.Now().Truncate(time.Second), // Truncate for JSON precision
 		},
-		ImageID: "proc-123",
+		ImageID:           "proc-123",
+		RequestEventID:    "req-123",
+		ProcessingVersion: vobj.ProcessingV2,
 		Contents: []model.Content{
 			{
 				Entity: vobj.Entity{
@@ -101,6 +103,8 @@ func TestEventSerializer_ImageProcessCompleteEvent_RoundTrip(t *testing.T) {
 
 	assert.Equal(t, originalEvent.EventID, resultEvent.EventID)
 	assert.Equal(t, originalEvent.ImageID, resultEvent.ImageID)
+	assert.Equal(t, originalEvent.RequestEventID, resultEvent.RequestEventID)
+	assert.Equal(t, originalEvent.ProcessingVersion, resultEvent.ProcessingVersion)
 	assert.Equal(t, originalEvent.Success, resultEvent.Success)
 	assert.Equal(t, len(originalEvent.Contents), len(resultEvent.Contents))
 
@@ -109,3 +113,35 @@ func TestEventSerializer_ImageProcessCompleteEvent_RoundTrip(t *testing.T) {
 	assert.Equal(t, originalEvent.Contents[0].Name, resultEvent.Contents[0].Name)
 	// assert.Equal(t, originalEvent.Contents[0].EntityType, resultEvent.Contents[0].EntityType) // Check if EntityType was preserved
 }
+
+func TestEventSerializer_EntityEvent_RoundTrip(t *testing.T) {
+	serializer := NewEventSerializer()
+
+	originalEvent := &domainevent.EntityEvent{
+		BaseEvent: domainevent.BaseEvent{
+			EventID:   "evt-456",
+			EventType: domainevent.PatientTransferredEventType,
+			Timestamp: time.Now().Truncate(time.Second),
+		},
+		Action:         domainevent.EntityTransferred,
+		EntityType:     vobj.EntityTypePatient,
+		EntityID:       "patient-1",
+		WsID:           "ws-2",
+		Parent:         vobj.ParentRef{ID: "ws-2", Type: vobj.ParentTypeWorkspace},
+		PreviousParent: &vobj.ParentRef{ID: "ws-1", Type: vobj.ParentTypeWorkspace},
+	}
+
+	data, err := serializer.Serialize(originalEvent)
+	assert.NoError(t, err)
+
+	deserializedEvent, err := serializer.Deserialize(data, domainevent.PatientTransferredEventType)
+	assert.NoError(t, err)
+
+	resultEvent, ok := deserializedEvent.(*domainevent.EntityEvent)
+	assert.True(t, ok)
+	assert.Equal(t, originalEvent.EntityID, resultEvent.EntityID)
+	assert.Equal(t, originalEvent.WsID, resultEvent.WsID)
+	assert.Equal(t, originalEvent.Parent, resultEvent.Parent)
+	assert.Equal(t, *originalEvent.PreviousParent, *resultEvent.PreviousParent)
+	assert.True(t, originalEvent.Timestamp.Equal(resultEvent.Timestamp))
+}
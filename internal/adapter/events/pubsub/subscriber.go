@@ -4,6 +4,7 @@ package pubsub
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -21,7 +22,7 @@ import (
 type PubSubSubscriber struct {
 	client         *pubsub.Client
 	subscriptionID string
-	serializer     *EventSerializer
+	codec          *MessageCodec
 	handler        portevent.EventHandler
 	logger         *slog.Logger
 	cache          portcache.Cache
@@ -43,10 +44,11 @@ func NewPubSubSubscriber(
 	return &PubSubSubscriber{
 		client:         client,
 		subscriptionID: subscriptionID,
-		serializer:     NewEventSerializer(),
-		handler:        handler,
-		logger:         logger,
-		cache:          cache,
+		// Decoding accepts every format, so the encoding option does not matter here
+		codec:   NewMessageCodec(NewEventSerializer(), EncodingLegacy, "", ""),
+		handler: handler,
+		logger:  logger,
+		cache:   cache,
 	}, nil
 }
 
@@ -70,29 +72,18 @@ func (s *PubSubSubscriber) Subscribe(ctx context.Context, handler portevent.Even
 			return
 		}
 
-		// 1. Get event type from attributes
-		eventTypeStr, ok := msg.Attributes["event_type"]
-		if !ok {
-			// if event type is not found, ignore and remove the message
-			s.logger.Warn("Event type not found", "message_id", msg.ID)
-			msg.Ack()
-			return
-		}
-		if eventTypeStr == "" {
-			// if event type is empty, ignore and remove the message
-			s.logger.Warn("Event type is empty", "message_id", msg.ID)
-			msg.Ack()
-			return
-		}
-		eventType := domainevent.EventType(eventTypeStr)
-
-		// 2. Deserialize
-		if eventType == domainevent.ImageProcessCompleteEventType {
-			s.logger.Info("Received raw message", "data", string(msg.Data), "event_type", eventType)
+		// 1. Decode (CloudEvents binary/structured or legacy)
+		if msg.Attributes[attrEventType] == string(domainevent.ImageProcessCompleteEventType) {
+			s.logger.Info("Received raw message", "data", string(msg.Data), "event_type", msg.Attributes[attrEventType])
 		}
-		event, err := s.serializer.Deserialize(msg.Data, eventType)
+		event, err := s.codec.Decode(msg.Data, msg.Attributes)
 		if err != nil {
-			s.logger.Error("Failed to deserialize event", "error", err, "message_id", msg.ID)
+			if errors.Is(err, ErrUnknownEventFormat) {
+				// if event type is not found, ignore and remove the message
+				s.logger.Warn("Event type not found", "message_id", msg.ID)
+			} else {
+				s.logger.Error("Failed to deserialize event", "error", err, "message_id", msg.ID)
+			}
 			msg.Ack()
 			return
 		}
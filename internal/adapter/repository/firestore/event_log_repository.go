@@ -0,0 +1,124 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	eventLogEventID    = "event_id"
+	eventLogEventType  = "event_type"
+	eventLogDirection  = "direction"
+	eventLogChannel    = "channel"
+	eventLogImageID    = "image_id"
+	eventLogPayload    = "payload"
+	eventLogOccurredAt = "occurred_at"
+	eventLogRecordedAt = "recorded_at"
+)
+
+type EventLogRepositoryImpl struct {
+	client     *firestore.Client
+	collection string
+}
+
+func NewEventLogRepositoryImpl(client *firestore.Client, collection string) *EventLogRepositoryImpl {
+	return &EventLogRepositoryImpl{
+		client:     client,
+		collection: collection,
+	}
+}
+
+// Append writes the entry under "<direction>_<event_id>", so a redelivered
+// message overwrites its earlier record instead of duplicating it.
+func (r *EventLogRepositoryImpl) Append(ctx context.Context, entry *model.EventLogEntry) error {
+	if entry == nil || entry.EventID == "" {
+		return ErrInvalidInput
+	}
+
+	entry.ID = string(entry.Direction) + "_" + entry.EventID
+	entry.RecordedAt = time.Now()
+
+	data := map[string]interface{}{
+		eventLogEventID:    entry.EventID,
+		eventLogEventType:  entry.EventType,
+		eventLogDirection:  string(entry.Direction),
+		eventLogChannel:    entry.Channel,
+		eventLogImageID:    entry.ImageID,
+		eventLogPayload:    string(entry.Payload),
+		eventLogOccurredAt: entry.OccurredAt,
+		eventLogRecordedAt: entry.RecordedAt,
+	}
+
+	_, err := r.client.Collection(r.collection).Doc(entry.ID).Set(ctx, data)
+	return mapFirestoreError(err)
+}
+
+func (r *EventLogRepositoryImpl) Find(ctx context.Context, filter port.EventLogFilter) ([]*model.EventLogEntry, error) {
+	q := r.client.Collection(r.collection).Query
+
+	switch len(filter.EventTypes) {
+	case 0:
+	case 1:
+		q = q.Where(eventLogEventType, "==", filter.EventTypes[0])
+	default:
+		q = q.Where(eventLogEventType, "in", filter.EventTypes)
+	}
+	if filter.ImageID != "" {
+		q = q.Where(eventLogImageID, "==", filter.ImageID)
+	}
+	if filter.From != nil {
+		q = q.Where(eventLogOccurredAt, ">=", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where(eventLogOccurredAt, "<", *filter.To)
+	}
+	q = q.OrderBy(eventLogOccurredAt, firestore.Asc)
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	entries := []*model.EventLogEntry{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			if isCollectionNotFoundError(err) {
+				return entries, nil
+			}
+			return nil, mapFirestoreError(err)
+		}
+		entries = append(entries, eventLogFromFirestoreDoc(doc))
+	}
+
+	return entries, nil
+}
+
+func eventLogFromFirestoreDoc(doc *firestore.DocumentSnapshot) *model.EventLogEntry {
+	data := doc.Data()
+	e := &model.EventLogEntry{ID: doc.Ref.ID}
+
+	e.EventID, _ = data[eventLogEventID].(string)
+	e.EventType, _ = data[eventLogEventType].(string)
+	if v, ok := data[eventLogDirection].(string); ok {
+		e.Direction = model.EventDirection(v)
+	}
+	e.Channel, _ = data[eventLogChannel].(string)
+	e.ImageID, _ = data[eventLogImageID].(string)
+	if v, ok := data[eventLogPayload].(string); ok {
+		e.Payload = []byte(v)
+	}
+	e.OccurredAt, _ = data[eventLogOccurredAt].(time.Time)
+	e.RecordedAt, _ = data[eventLogRecordedAt].(time.Time)
+
+	return e
+}
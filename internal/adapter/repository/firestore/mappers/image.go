@@ -74,6 +74,12 @@ func (im *ImageMapper) ToFirestoreMap(entity *model.Image) map[string]interface{
 	if entity.ZipTilesContentID != nil {
 		m[fields.ImageZipTilesContentID.FirestoreName()] = *entity.ZipTilesContentID
 	}
+	if entity.LabelContentID != nil {
+		m[fields.ImageLabelContentID.FirestoreName()] = *entity.LabelContentID
+	}
+	if entity.MacroContentID != nil {
+		m[fields.ImageMacroContentID.FirestoreName()] = *entity.MacroContentID
+	}
 
 	// Processing info
 	processingMap := make(map[string]interface{})
@@ -89,6 +95,9 @@ func (im *ImageMapper) ToFirestoreMap(entity *model.Image) map[string]interface{
 	if entity.Processing.ActiveEventID != "" {
 		processingMap["active_event_id"] = entity.Processing.ActiveEventID
 	}
+	if entity.Processing.Profile != "" {
+		processingMap["profile"] = entity.Processing.Profile.String()
+	}
 	m["processing"] = processingMap
 
 	return m
@@ -161,6 +170,12 @@ func (im *ImageMapper) FromFirestoreDoc(doc *firestore.DocumentSnapshot) (*model
 	if v, ok := data[fields.ImageZipTilesContentID.FirestoreName()].(string); ok {
 		image.ZipTilesContentID = &v
 	}
+	if v, ok := data[fields.ImageLabelContentID.FirestoreName()].(string); ok {
+		image.LabelContentID = &v
+	}
+	if v, ok := data[fields.ImageMacroContentID.FirestoreName()].(string); ok {
+		image.MacroContentID = &v
+	}
 
 	// Processing info
 	if procInfo, ok := data["processing"].(map[string]interface{}); ok {
@@ -194,11 +209,24 @@ func (im *ImageMapper) FromFirestoreDoc(doc *firestore.DocumentSnapshot) (*model
 		if activeEventID, ok := procInfo["active_event_id"].(string); ok {
 			image.Processing.ActiveEventID = activeEventID
 		}
+		if profile, ok := procInfo["profile"].(string); ok {
+			image.Processing.Profile = vobj.ProcessingProfile(profile)
+		}
 	}
 
 	return image, nil
 }
 
+var derivedContentIDFields = map[string]fields.ImageField{
+	fields.ImageThumbnailContentID.DomainName(): fields.ImageThumbnailContentID,
+	fields.ImageDziContentID.DomainName():       fields.ImageDziContentID,
+	fields.ImageIndexmapContentID.DomainName():  fields.ImageIndexmapContentID,
+	fields.ImageTilesContentID.DomainName():     fields.ImageTilesContentID,
+	fields.ImageZipTilesContentID.DomainName():  fields.ImageZipTilesContentID,
+	fields.ImageLabelContentID.DomainName():     fields.ImageLabelContentID,
+	fields.ImageMacroContentID.DomainName():     fields.ImageMacroContentID,
+}
+
 func (im *ImageMapper) MapUpdates(updates map[string]interface{}) (map[string]interface{}, error) {
 	mappedUpdates, err := im.EntityMapper.MapUpdates(updates)
 	if err != nil {
@@ -206,6 +234,14 @@ func (im *ImageMapper) MapUpdates(updates map[string]interface{}) (map[string]in
 	}
 
 	for k, v := range updates {
+		// A nil content ID removes the reference (e.g. before reprocessing)
+		if field, ok := derivedContentIDFields[k]; ok {
+			if id, isPtr := v.(*string); v == nil || (isPtr && id == nil) {
+				mappedUpdates[field.FirestoreName()] = firestore.Delete
+				continue
+			}
+		}
+
 		switch k {
 		case fields.ImageWidth.DomainName():
 			if width, ok := v.(*int); ok {
@@ -304,6 +340,24 @@ func (im *ImageMapper) MapUpdates(updates map[string]interface{}) (map[string]in
 				return nil, errors.NewValidationError("invalid type for ziptiles_content_id field", nil)
 			}
 
+		case fields.ImageLabelContentID.DomainName():
+			if id, ok := v.(*string); ok {
+				mappedUpdates[fields.ImageLabelContentID.FirestoreName()] = *id
+			} else if idStr, ok := v.(string); ok {
+				mappedUpdates[fields.ImageLabelContentID.FirestoreName()] = idStr
+			} else {
+				return nil, errors.NewValidationError("invalid type for label_content_id field", nil)
+			}
+
+		case fields.ImageMacroContentID.DomainName():
+			if id, ok := v.(*string); ok {
+				mappedUpdates[fields.ImageMacroContentID.FirestoreName()] = *id
+			} else if idStr, ok := v.(string); ok {
+				mappedUpdates[fields.ImageMacroContentID.FirestoreName()] = idStr
+			} else {
+				return nil, errors.NewValidationError("invalid type for macro_content_id field", nil)
+			}
+
 		// Helper to properly get or create nested map
 		case fields.ImageProcessingStatus.DomainName():
 			procMap, ok := mappedUpdates["processing"].(map[string]interface{})
@@ -387,6 +441,20 @@ func (im *ImageMapper) MapUpdates(updates map[string]interface{}) (map[string]in
 				return nil, errors.NewValidationError("invalid type for processing.active_event_id field", nil)
 			}
 
+		case fields.ImageProcessingProfile.DomainName():
+			procMap, ok := mappedUpdates["processing"].(map[string]interface{})
+			if !ok {
+				procMap = make(map[string]interface{})
+				mappedUpdates["processing"] = procMap
+			}
+			if profile, ok := v.(vobj.ProcessingProfile); ok {
+				procMap["profile"] = profile.String()
+			} else if profileStr, ok := v.(string); ok {
+				procMap["profile"] = profileStr
+			} else {
+				return nil, errors.NewValidationError("invalid type for processing.profile field", nil)
+			}
+
 		case fields.ImageWsID.DomainName():
 			if wsID, ok := v.(string); ok {
 				mappedUpdates[fields.ImageWsID.FirestoreName()] = wsID
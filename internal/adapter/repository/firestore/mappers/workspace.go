@@ -28,6 +28,7 @@ func (wm *WorkspaceMapper) ToFirestoreMap(entity *model.Workspace) map[string]in
 	m[fields.WorkspaceOrganization.FirestoreName()] = entity.Organization
 	m[fields.WorkspaceDescription.FirestoreName()] = entity.Description
 	m[fields.WorkspaceLicense.FirestoreName()] = entity.License
+	m[fields.WorkspaceDeIdentified.FirestoreName()] = entity.DeIdentified
 
 	if entity.ResourceURL != nil {
 		m[fields.WorkspaceResourceURL.FirestoreName()] = *entity.ResourceURL
@@ -38,6 +39,9 @@ func (wm *WorkspaceMapper) ToFirestoreMap(entity *model.Workspace) map[string]in
 	if len(entity.AnnotationTypes) > 0 {
 		m[fields.WorkspaceAnnotationTypes.FirestoreName()] = entity.AnnotationTypes
 	}
+	if len(entity.Members) > 0 {
+		m[fields.WorkspaceMembers.FirestoreName()] = entity.Members
+	}
 
 	return m
 }
@@ -73,6 +77,9 @@ func (wm *WorkspaceMapper) FromFirestoreDoc(doc *firestore.DocumentSnapshot) (*m
 	if resourceURL, ok := data[fields.WorkspaceResourceURL.FirestoreName()].(string); ok {
 		workspace.ResourceURL = &resourceURL
 	}
+	if deIdentified, ok := data[fields.WorkspaceDeIdentified.FirestoreName()].(bool); ok {
+		workspace.DeIdentified = deIdentified
+	}
 	// Firestore stores integers as int64
 	if releaseYear64, ok := data[fields.WorkspaceReleaseYear.FirestoreName()].(int64); ok {
 		releaseYear := int(releaseYear64)
@@ -87,6 +94,15 @@ func (wm *WorkspaceMapper) FromFirestoreDoc(doc *firestore.DocumentSnapshot) (*m
 		}
 		workspace.AnnotationTypes = annotationTypes
 	}
+	if membersRaw, ok := data[fields.WorkspaceMembers.FirestoreName()].([]interface{}); ok {
+		members := make([]string, 0, len(membersRaw))
+		for _, m := range membersRaw {
+			if memberStr, ok := m.(string); ok {
+				members = append(members, memberStr)
+			}
+		}
+		workspace.Members = members
+	}
 
 	return workspace, nil
 }
@@ -153,6 +169,20 @@ func (wm *WorkspaceMapper) MapUpdates(updates map[string]interface{}) (map[strin
 			} else {
 				return nil, errors.NewValidationError("invalid annotation_types field", nil)
 			}
+
+		case fields.WorkspaceMembers.DomainName():
+			if members, ok := v.([]string); ok {
+				mappedUpdates[fields.WorkspaceMembers.FirestoreName()] = members
+			} else {
+				return nil, errors.NewValidationError("invalid members field", nil)
+			}
+
+		case fields.WorkspaceDeIdentified.DomainName():
+			if deIdentified, ok := v.(bool); ok {
+				mappedUpdates[fields.WorkspaceDeIdentified.FirestoreName()] = deIdentified
+			} else {
+				return nil, errors.NewValidationError("invalid de_identified field", nil)
+			}
 		}
 	}
 
@@ -0,0 +1,353 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	outboxEventType          = "event_type"
+	outboxTimestamp          = "timestamp"
+	outboxAction             = "action"
+	outboxEntityType         = "entity_type"
+	outboxEntityID           = "entity_id"
+	outboxWsID               = "ws_id"
+	outboxParentID           = "parent_id"
+	outboxParentType         = "parent_type"
+	outboxPreviousParentID   = "previous_parent_id"
+	outboxPreviousParentType = "previous_parent_type"
+	outboxChangedFields      = "changed_fields"
+
+	// Image processing requests
+	outboxContent            = "content"
+	outboxContentID          = "id"
+	outboxContentName        = "name"
+	outboxContentCreatorID   = "creator_id"
+	outboxContentProvider    = "provider"
+	outboxContentPath        = "path"
+	outboxContentContentType = "content_type"
+	outboxContentSize        = "size"
+	outboxProcessingVersion  = "processing_version"
+	outboxProcessingProfile  = "processing_profile"
+	outboxPriority           = "priority"
+
+	// Relay bookkeeping
+	outboxStatus         = "status"
+	outboxAttempts       = "attempts"
+	outboxNextAttemptAt  = "next_attempt_at"
+	outboxLastError      = "last_error"
+	outboxPublishedAt    = "published_at"
+	outboxDeadLetteredAt = "dead_lettered_at"
+)
+
+// Relay states of an outbox event
+const (
+	outboxStatusPending      = "pending"
+	outboxStatusPublished    = "published"
+	outboxStatusDeadLettered = "dead_lettered"
+)
+
+type OutboxRepositoryImpl struct {
+	client     *firestore.Client
+	collection string
+}
+
+func NewOutboxRepositoryImpl(client *firestore.Client, collection string) *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{
+		client:     client,
+		collection: collection,
+	}
+}
+
+func (r *OutboxRepositoryImpl) Add(ctx context.Context, event domainevent.Event) error {
+	data, err := outboxToFirestoreMap(event)
+	if err != nil {
+		return err
+	}
+
+	docRef := r.client.Collection(r.collection).Doc(event.GetEventID())
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Create(docRef, data)
+	} else {
+		_, err = docRef.Create(ctx, data)
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *OutboxRepositoryImpl) Get(ctx context.Context, eventID string) (domainevent.Event, error) {
+	doc, err := r.client.Collection(r.collection).Doc(eventID).Get(ctx)
+	if err != nil {
+		return nil, mapFirestoreError(err)
+	}
+	return outboxFromFirestoreDoc(doc)
+}
+
+func (r *OutboxRepositoryImpl) ListByWorkspace(ctx context.Context, wsID string, after time.Time, afterID string, limit int) ([]domainevent.Event, error) {
+	q := r.client.Collection(r.collection).
+		Where(outboxWsID, "==", wsID).
+		OrderBy(outboxTimestamp, firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+
+	if afterID == "" {
+		q = q.Where(outboxTimestamp, ">=", after)
+	} else {
+		q = q.StartAfter(after, afterID)
+	}
+
+	return r.list(ctx, q.Limit(limit))
+}
+
+func (r *OutboxRepositoryImpl) FetchPending(ctx context.Context, now time.Time, limit int) ([]port.PendingEvent, error) {
+	q := r.client.Collection(r.collection).
+		Where(outboxStatus, "==", outboxStatusPending).
+		Where(outboxNextAttemptAt, "<=", now).
+		OrderBy(outboxNextAttemptAt, firestore.Asc).
+		Limit(limit)
+
+	docs, err := r.documents(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]port.PendingEvent, 0, len(docs))
+	for _, doc := range docs {
+		event, err := outboxFromFirestoreDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		attempts, _ := doc.Data()[outboxAttempts].(int64)
+		pending = append(pending, port.PendingEvent{Event: event, Attempts: int(attempts)})
+	}
+
+	return pending, nil
+}
+
+func (r *OutboxRepositoryImpl) list(ctx context.Context, q firestore.Query) ([]domainevent.Event, error) {
+	docs, err := r.documents(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]domainevent.Event, 0, len(docs))
+	for _, doc := range docs {
+		event, err := outboxFromFirestoreDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (r *OutboxRepositoryImpl) documents(ctx context.Context, q firestore.Query) ([]*firestore.DocumentSnapshot, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	docs := []*firestore.DocumentSnapshot{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			if isCollectionNotFoundError(err) {
+				return docs, nil
+			}
+			return nil, mapFirestoreError(err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+func (r *OutboxRepositoryImpl) MarkPublished(ctx context.Context, eventID string) error {
+	return r.update(ctx, eventID, map[string]interface{}{
+		outboxStatus:      outboxStatusPublished,
+		outboxPublishedAt: time.Now(),
+	})
+}
+
+func (r *OutboxRepositoryImpl) RecordFailure(ctx context.Context, eventID string, attempts int, cause string, nextAttemptAt time.Time) error {
+	return r.update(ctx, eventID, map[string]interface{}{
+		outboxAttempts:      attempts,
+		outboxLastError:     cause,
+		outboxNextAttemptAt: nextAttemptAt,
+	})
+}
+
+func (r *OutboxRepositoryImpl) MarkDeadLettered(ctx context.Context, eventID string, attempts int, cause string) error {
+	return r.update(ctx, eventID, map[string]interface{}{
+		outboxStatus:         outboxStatusDeadLettered,
+		outboxAttempts:       attempts,
+		outboxLastError:      cause,
+		outboxDeadLetteredAt: time.Now(),
+	})
+}
+
+func (r *OutboxRepositoryImpl) update(ctx context.Context, eventID string, updates map[string]interface{}) error {
+	docRef := r.client.Collection(r.collection).Doc(eventID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Set(docRef, updates, firestore.MergeAll)
+	} else {
+		_, err = docRef.Set(ctx, updates, firestore.MergeAll)
+	}
+
+	return mapFirestoreError(err)
+}
+
+func outboxToFirestoreMap(event domainevent.Event) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	switch e := event.(type) {
+	case *domainevent.EntityEvent:
+		m = entityEventToFirestoreMap(e)
+	case *domainevent.ImageProcessReqEvent:
+		m = processRequestToFirestoreMap(e)
+	default:
+		return nil, fmt.Errorf("%w: unsupported outbox event %T", ErrInvalidInput, event)
+	}
+
+	m[outboxEventType] = string(event.GetEventType())
+	m[outboxTimestamp] = event.GetTimestamp()
+	m[outboxStatus] = outboxStatusPending
+	m[outboxAttempts] = 0
+	m[outboxNextAttemptAt] = event.GetTimestamp()
+
+	return m, nil
+}
+
+func entityEventToFirestoreMap(e *domainevent.EntityEvent) map[string]interface{} {
+	m := map[string]interface{}{
+		outboxAction:     string(e.Action),
+		outboxEntityType: e.EntityType.String(),
+		outboxEntityID:   e.EntityID,
+		outboxWsID:       e.WsID,
+		outboxParentID:   e.Parent.ID,
+		outboxParentType: e.Parent.Type.String(),
+	}
+	if e.PreviousParent != nil {
+		m[outboxPreviousParentID] = e.PreviousParent.ID
+		m[outboxPreviousParentType] = e.PreviousParent.Type.String()
+	}
+	if len(e.ChangedFields) > 0 {
+		m[outboxChangedFields] = e.ChangedFields
+	}
+	return m
+}
+
+func processRequestToFirestoreMap(e *domainevent.ImageProcessReqEvent) map[string]interface{} {
+	return map[string]interface{}{
+		outboxContent: map[string]interface{}{
+			outboxContentID:          e.Content.ID,
+			outboxContentName:        e.Content.Name,
+			outboxContentCreatorID:   e.Content.CreatorID,
+			outboxParentID:           e.Content.Parent.ID,
+			outboxParentType:         e.Content.Parent.Type.String(),
+			outboxContentProvider:    e.Content.Provider.String(),
+			outboxContentPath:        e.Content.Path,
+			outboxContentContentType: e.Content.ContentType.String(),
+			outboxContentSize:        e.Content.Size,
+		},
+		outboxProcessingVersion: string(e.ProcessingVersion),
+		outboxProcessingProfile: string(e.ProcessingProfile),
+		outboxPriority:          string(e.Priority),
+	}
+}
+
+func outboxFromFirestoreDoc(doc *firestore.DocumentSnapshot) (domainevent.Event, error) {
+	data := doc.Data()
+	if data == nil {
+		return nil, ErrInvalidInput
+	}
+
+	base := domainevent.BaseEvent{EventID: doc.Ref.ID}
+	if v, ok := data[outboxEventType].(string); ok {
+		base.EventType = domainevent.EventType(v)
+	}
+	if v, ok := data[outboxTimestamp].(time.Time); ok {
+		base.Timestamp = v
+	}
+
+	if base.EventType == domainevent.ImageProcessReqEventType {
+		return processRequestFromFirestoreMap(base, data), nil
+	}
+	return entityEventFromFirestoreMap(base, data), nil
+}
+
+func entityEventFromFirestoreMap(base domainevent.BaseEvent, data map[string]interface{}) *domainevent.EntityEvent {
+	e := &domainevent.EntityEvent{BaseEvent: base}
+
+	if v, ok := data[outboxAction].(string); ok {
+		e.Action = domainevent.EntityAction(v)
+	}
+	if v, ok := data[outboxEntityType].(string); ok {
+		e.EntityType = vobj.EntityType(v)
+	}
+	if v, ok := data[outboxEntityID].(string); ok {
+		e.EntityID = v
+	}
+	if v, ok := data[outboxWsID].(string); ok {
+		e.WsID = v
+	}
+	parentID, _ := data[outboxParentID].(string)
+	parentType, _ := data[outboxParentType].(string)
+	e.Parent = vobj.ParentRef{ID: parentID, Type: vobj.ParentType(parentType)}
+
+	if prevID, ok := data[outboxPreviousParentID].(string); ok {
+		prevType, _ := data[outboxPreviousParentType].(string)
+		e.PreviousParent = &vobj.ParentRef{ID: prevID, Type: vobj.ParentType(prevType)}
+	}
+	if v, ok := data[outboxChangedFields].([]interface{}); ok {
+		for _, f := range v {
+			if s, ok := f.(string); ok {
+				e.ChangedFields = append(e.ChangedFields, s)
+			}
+		}
+	}
+
+	return e
+}
+
+func processRequestFromFirestoreMap(base domainevent.BaseEvent, data map[string]interface{}) *domainevent.ImageProcessReqEvent {
+	e := &domainevent.ImageProcessReqEvent{BaseEvent: base}
+
+	if v, ok := data[outboxProcessingVersion].(string); ok {
+		e.ProcessingVersion = vobj.ProcessingVersion(v)
+	}
+	if v, ok := data[outboxProcessingProfile].(string); ok {
+		e.ProcessingProfile = vobj.ProcessingProfile(v)
+	}
+	if v, ok := data[outboxPriority].(string); ok {
+		e.Priority = vobj.ProcessingPriority(v)
+	}
+
+	content, _ := data[outboxContent].(map[string]interface{})
+	e.Content.EntityType = vobj.EntityTypeContent
+	e.Content.ID, _ = content[outboxContentID].(string)
+	e.Content.Name, _ = content[outboxContentName].(string)
+	e.Content.CreatorID, _ = content[outboxContentCreatorID].(string)
+	parentID, _ := content[outboxParentID].(string)
+	parentType, _ := content[outboxParentType].(string)
+	e.Content.Parent = vobj.ParentRef{ID: parentID, Type: vobj.ParentType(parentType)}
+	if v, ok := content[outboxContentProvider].(string); ok {
+		e.Content.Provider = vobj.ContentProvider(v)
+	}
+	e.Content.Path, _ = content[outboxContentPath].(string)
+	if v, ok := content[outboxContentContentType].(string); ok {
+		e.Content.ContentType = vobj.ContentType(v)
+	}
+	e.Content.Size, _ = content[outboxContentSize].(int64)
+
+	return e
+}
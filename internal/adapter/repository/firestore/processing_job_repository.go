@@ -0,0 +1,232 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	processingJobImageID           = "image_id"
+	processingJobWsID              = "ws_id"
+	processingJobFormat            = "format"
+	processingJobEventID           = "event_id"
+	processingJobProcessingVersion = "processing_version"
+	processingJobProcessingProfile = "processing_profile"
+	processingJobBackend           = "backend"
+	processingJobExecutionName     = "execution_name"
+	processingJobTier              = "tier"
+	processingJobAttempt           = "attempt"
+	processingJobStatus            = "status"
+	processingJobFailureReason     = "failure_reason"
+	processingJobOutputSizes       = "output_sizes"
+	processingJobQueuedAt          = "queued_at"
+	processingJobStartedAt         = "started_at"
+	processingJobFinishedAt        = "finished_at"
+	processingJobUpdatedAt         = "updated_at"
+)
+
+type ProcessingJobRepositoryImpl struct {
+	client     *firestore.Client
+	collection string
+}
+
+func NewProcessingJobRepositoryImpl(client *firestore.Client, collection string) *ProcessingJobRepositoryImpl {
+	return &ProcessingJobRepositoryImpl{
+		client:     client,
+		collection: collection,
+	}
+}
+
+func (r *ProcessingJobRepositoryImpl) Create(ctx context.Context, job *model.ProcessingJob) error {
+	if job == nil {
+		return ErrInvalidInput
+	}
+
+	if job.ID == "" {
+		job.ID = r.client.Collection(r.collection).NewDoc().ID
+	}
+	now := time.Now()
+	if job.StartedAt.IsZero() {
+		job.StartedAt = now
+	}
+	job.UpdatedAt = now
+
+	docRef := r.client.Collection(r.collection).Doc(job.ID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Create(docRef, processingJobToFirestoreMap(job))
+	} else {
+		_, err = docRef.Create(ctx, processingJobToFirestoreMap(job))
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *ProcessingJobRepositoryImpl) Update(ctx context.Context, job *model.ProcessingJob) error {
+	if job == nil || job.ID == "" {
+		return ErrInvalidInput
+	}
+
+	job.UpdatedAt = time.Now()
+	docRef := r.client.Collection(r.collection).Doc(job.ID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Set(docRef, processingJobToFirestoreMap(job))
+	} else {
+		_, err = docRef.Set(ctx, processingJobToFirestoreMap(job))
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *ProcessingJobRepositoryImpl) ListRunning(ctx context.Context, limit int) ([]*model.ProcessingJob, error) {
+	q := r.client.Collection(r.collection).
+		Where(processingJobStatus, "==", string(model.ProcessingJobRunning)).
+		OrderBy(processingJobStartedAt, firestore.Asc).
+		Limit(limit)
+
+	return r.list(ctx, q)
+}
+
+func (r *ProcessingJobRepositoryImpl) ListByImage(ctx context.Context, imageID string, limit int) ([]*model.ProcessingJob, error) {
+	q := r.client.Collection(r.collection).
+		Where(processingJobImageID, "==", imageID).
+		OrderBy(processingJobStartedAt, firestore.Desc).
+		Limit(limit)
+
+	return r.list(ctx, q)
+}
+
+func (r *ProcessingJobRepositoryImpl) CountRunning(ctx context.Context) (int, error) {
+	return count(ctx, r.client.Collection(r.collection).
+		Where(processingJobStatus, "==", string(model.ProcessingJobRunning)))
+}
+
+func (r *ProcessingJobRepositoryImpl) Find(ctx context.Context, filter port.ProcessingJobFilter) ([]*model.ProcessingJob, error) {
+	q := r.client.Collection(r.collection).Query
+	if filter.WsID != "" {
+		q = q.Where(processingJobWsID, "==", filter.WsID)
+	}
+	if filter.Format != "" {
+		q = q.Where(processingJobFormat, "==", filter.Format)
+	}
+	if filter.From != nil {
+		q = q.Where(processingJobStartedAt, ">=", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where(processingJobStartedAt, "<", *filter.To)
+	}
+	q = q.OrderBy(processingJobStartedAt, firestore.Desc)
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	return r.list(ctx, q)
+}
+
+func (r *ProcessingJobRepositoryImpl) list(ctx context.Context, q firestore.Query) ([]*model.ProcessingJob, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	jobs := []*model.ProcessingJob{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			if isCollectionNotFoundError(err) {
+				return jobs, nil
+			}
+			return nil, mapFirestoreError(err)
+		}
+		jobs = append(jobs, processingJobFromFirestoreDoc(doc))
+	}
+
+	return jobs, nil
+}
+
+func processingJobToFirestoreMap(j *model.ProcessingJob) map[string]interface{} {
+	m := map[string]interface{}{
+		processingJobImageID:           j.ImageID,
+		processingJobWsID:              j.WsID,
+		processingJobFormat:            j.Format,
+		processingJobEventID:           j.EventID,
+		processingJobProcessingVersion: j.ProcessingVersion.String(),
+		processingJobProcessingProfile: j.ProcessingProfile.String(),
+		processingJobBackend:           j.Backend,
+		processingJobExecutionName:     j.ExecutionName,
+		processingJobTier:              j.Tier,
+		processingJobAttempt:           j.Attempt,
+		processingJobStatus:            string(j.Status),
+		processingJobFailureReason:     j.FailureReason,
+		processingJobStartedAt:         j.StartedAt,
+		processingJobUpdatedAt:         j.UpdatedAt,
+	}
+	if len(j.OutputSizes) > 0 {
+		sizes := make(map[string]interface{}, len(j.OutputSizes))
+		for output, size := range j.OutputSizes {
+			sizes[string(output)] = size
+		}
+		m[processingJobOutputSizes] = sizes
+	}
+	if j.QueuedAt != nil {
+		m[processingJobQueuedAt] = *j.QueuedAt
+	}
+	if j.FinishedAt != nil {
+		m[processingJobFinishedAt] = *j.FinishedAt
+	}
+	return m
+}
+
+func processingJobFromFirestoreDoc(doc *firestore.DocumentSnapshot) *model.ProcessingJob {
+	data := doc.Data()
+	j := &model.ProcessingJob{ID: doc.Ref.ID}
+
+	j.ImageID, _ = data[processingJobImageID].(string)
+	j.WsID, _ = data[processingJobWsID].(string)
+	j.Format, _ = data[processingJobFormat].(string)
+	j.EventID, _ = data[processingJobEventID].(string)
+	if v, ok := data[processingJobProcessingVersion].(string); ok {
+		j.ProcessingVersion = vobj.ProcessingVersion(v)
+	}
+	if v, ok := data[processingJobProcessingProfile].(string); ok {
+		j.ProcessingProfile = vobj.ProcessingProfile(v)
+	}
+	j.Backend, _ = data[processingJobBackend].(string)
+	j.ExecutionName, _ = data[processingJobExecutionName].(string)
+	j.Tier, _ = data[processingJobTier].(string)
+	if v, ok := data[processingJobAttempt].(int64); ok {
+		j.Attempt = int(v)
+	}
+	if v, ok := data[processingJobStatus].(string); ok {
+		j.Status = model.ProcessingJobStatus(v)
+	}
+	j.FailureReason, _ = data[processingJobFailureReason].(string)
+	if v, ok := data[processingJobOutputSizes].(map[string]interface{}); ok {
+		j.OutputSizes = make(map[vobj.ProcessingOutput]int64, len(v))
+		for output, size := range v {
+			if n, ok := size.(int64); ok {
+				j.OutputSizes[vobj.ProcessingOutput(output)] = n
+			}
+		}
+	}
+	if v, ok := data[processingJobQueuedAt].(time.Time); ok {
+		j.QueuedAt = &v
+	}
+	j.StartedAt, _ = data[processingJobStartedAt].(time.Time)
+	if v, ok := data[processingJobFinishedAt].(time.Time); ok {
+		j.FinishedAt = &v
+	}
+	j.UpdatedAt, _ = data[processingJobUpdatedAt].(time.Time)
+
+	return j
+}
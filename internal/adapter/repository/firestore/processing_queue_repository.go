@@ -0,0 +1,215 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	queueWsID              = "ws_id"
+	queueEventID           = "event_id"
+	queueContentID         = "content_id"
+	queueProcessingVersion = "processing_version"
+	queueProcessingProfile = "processing_profile"
+	// Stored as vobj.ProcessingPriority.Rank so it sorts
+	queuePriority   = "priority"
+	queueRound      = "round"
+	queueEnqueuedAt = "enqueued_at"
+)
+
+// ProcessingQueueRepositoryImpl keeps one document per queued image, with the
+// image ID as document ID. Serving order needs a composite index on
+// (priority desc, round asc, enqueued_at asc).
+type ProcessingQueueRepositoryImpl struct {
+	client     *firestore.Client
+	collection string
+}
+
+func NewProcessingQueueRepositoryImpl(client *firestore.Client, collection string) *ProcessingQueueRepositoryImpl {
+	return &ProcessingQueueRepositoryImpl{
+		client:     client,
+		collection: collection,
+	}
+}
+
+func (r *ProcessingQueueRepositoryImpl) Save(ctx context.Context, entry *model.ProcessingQueueEntry) error {
+	if entry == nil || entry.ImageID == "" {
+		return ErrInvalidInput
+	}
+
+	if entry.EnqueuedAt.IsZero() {
+		entry.EnqueuedAt = time.Now()
+	}
+	docRef := r.client.Collection(r.collection).Doc(entry.ImageID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Set(docRef, queueEntryToFirestoreMap(entry))
+	} else {
+		_, err = docRef.Set(ctx, queueEntryToFirestoreMap(entry))
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *ProcessingQueueRepositoryImpl) Read(ctx context.Context, imageID string) (*model.ProcessingQueueEntry, error) {
+	docRef := r.client.Collection(r.collection).Doc(imageID)
+
+	var doc *firestore.DocumentSnapshot
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		doc, err = tx.Get(docRef)
+	} else {
+		doc, err = docRef.Get(ctx)
+	}
+	if err != nil {
+		return nil, mapFirestoreError(err)
+	}
+
+	return queueEntryFromFirestoreDoc(doc), nil
+}
+
+func (r *ProcessingQueueRepositoryImpl) Delete(ctx context.Context, imageID string) error {
+	docRef := r.client.Collection(r.collection).Doc(imageID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Delete(docRef)
+	} else {
+		_, err = docRef.Delete(ctx)
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *ProcessingQueueRepositoryImpl) ListNext(ctx context.Context, limit int) ([]*model.ProcessingQueueEntry, error) {
+	q := r.client.Collection(r.collection).
+		OrderBy(queuePriority, firestore.Desc).
+		OrderBy(queueRound, firestore.Asc).
+		OrderBy(queueEnqueuedAt, firestore.Asc).
+		Limit(limit)
+
+	return r.list(ctx, q)
+}
+
+func (r *ProcessingQueueRepositoryImpl) RoundRange(ctx context.Context, priority vobj.ProcessingPriority, wsID string) (int64, int64, bool, error) {
+	atPriority := r.client.Collection(r.collection).Where(queuePriority, "==", priority.Rank())
+
+	first, err := r.list(ctx, atPriority.OrderBy(queueRound, firestore.Asc).Limit(1))
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if len(first) == 0 {
+		return 0, -1, false, nil
+	}
+
+	last, err := r.list(ctx, atPriority.Where(queueWsID, "==", wsID).OrderBy(queueRound, firestore.Desc).Limit(1))
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if len(last) == 0 {
+		return first[0].Round, -1, true, nil
+	}
+	return first[0].Round, last[0].Round, true, nil
+}
+
+func (r *ProcessingQueueRepositoryImpl) CountAhead(ctx context.Context, entry *model.ProcessingQueueEntry) (int, error) {
+	col := r.client.Collection(r.collection)
+	rank := entry.Priority.Rank()
+
+	queries := []firestore.Query{
+		col.Where(queuePriority, ">", rank),
+		col.Where(queuePriority, "==", rank).Where(queueRound, "<", entry.Round),
+		col.Where(queuePriority, "==", rank).Where(queueRound, "==", entry.Round).Where(queueEnqueuedAt, "<", entry.EnqueuedAt),
+	}
+
+	total := 0
+	for _, q := range queries {
+		n, err := count(ctx, q)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (r *ProcessingQueueRepositoryImpl) list(ctx context.Context, q firestore.Query) ([]*model.ProcessingQueueEntry, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	entries := []*model.ProcessingQueueEntry{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			if isCollectionNotFoundError(err) {
+				return entries, nil
+			}
+			return nil, mapFirestoreError(err)
+		}
+		entries = append(entries, queueEntryFromFirestoreDoc(doc))
+	}
+
+	return entries, nil
+}
+
+// count runs an aggregation query outside of any transaction.
+func count(ctx context.Context, q firestore.Query) (int, error) {
+	results, err := q.NewAggregationQuery().WithCount("count").Get(ctx)
+	if err != nil {
+		if isCollectionNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, mapFirestoreError(err)
+	}
+
+	if val, found := results["count"]; found {
+		if n, ok := val.(int64); ok {
+			return int(n), nil
+		}
+	}
+	return 0, nil
+}
+
+func queueEntryToFirestoreMap(e *model.ProcessingQueueEntry) map[string]interface{} {
+	return map[string]interface{}{
+		queueWsID:              e.WsID,
+		queueEventID:           e.EventID,
+		queueContentID:         e.ContentID,
+		queueProcessingVersion: e.ProcessingVersion.String(),
+		queueProcessingProfile: e.ProcessingProfile.String(),
+		queuePriority:          e.Priority.Rank(),
+		queueRound:             e.Round,
+		queueEnqueuedAt:        e.EnqueuedAt,
+	}
+}
+
+func queueEntryFromFirestoreDoc(doc *firestore.DocumentSnapshot) *model.ProcessingQueueEntry {
+	data := doc.Data()
+	e := &model.ProcessingQueueEntry{ImageID: doc.Ref.ID}
+
+	e.WsID, _ = data[queueWsID].(string)
+	e.EventID, _ = data[queueEventID].(string)
+	e.ContentID, _ = data[queueContentID].(string)
+	if v, ok := data[queueProcessingVersion].(string); ok {
+		e.ProcessingVersion = vobj.ProcessingVersion(v)
+	}
+	if v, ok := data[queueProcessingProfile].(string); ok {
+		e.ProcessingProfile = vobj.ProcessingProfile(v)
+	}
+	if v, ok := data[queuePriority].(int64); ok {
+		e.Priority = vobj.ProcessingPriorityFromRank(int(v))
+	}
+	e.Round, _ = data[queueRound].(int64)
+	e.EnqueuedAt, _ = data[queueEnqueuedAt].(time.Time)
+
+	return e
+}
@@ -27,6 +27,11 @@ type FirestoreUnitOfWorkFactory struct {
 	annotationRepo     port.AnnotationRepository
 	annotationTypeRepo port.AnnotationTypeRepository
 	contentRepo        port.ContentRepository
+	outboxRepo         port.OutboxRepository
+	webhookRepo        port.WebhookRepository
+	deliveryRepo       port.WebhookDeliveryRepository
+	processingJobRepo  port.ProcessingJobRepository
+	queueRepo          port.ProcessingQueueRepository
 }
 
 func NewFirestoreUnitOfWorkFactory(client *firestore.Client) *FirestoreUnitOfWorkFactory {
@@ -38,6 +43,11 @@ func NewFirestoreUnitOfWorkFactory(client *firestore.Client) *FirestoreUnitOfWor
 		annotationRepo:     NewGenericRepositoryImpl(client, "annotations", mappers.NewAnnotationMapper()),
 		annotationTypeRepo: NewGenericRepositoryImpl(client, "annotation_types", mappers.NewAnnotationTypeMapper()),
 		contentRepo:        NewGenericRepositoryImpl(client, "contents", mappers.NewContentMapper()),
+		outboxRepo:         NewOutboxRepositoryImpl(client, "outbox"),
+		webhookRepo:        NewWebhookRepositoryImpl(client, "webhooks"),
+		deliveryRepo:       NewWebhookDeliveryRepositoryImpl(client, "webhook_deliveries"),
+		processingJobRepo:  NewProcessingJobRepositoryImpl(client, "processing_jobs"),
+		queueRepo:          NewProcessingQueueRepositoryImpl(client, "processing_queue"),
 	}
 }
 
@@ -71,3 +81,23 @@ func (f *FirestoreUnitOfWorkFactory) GetAnnotationTypeRepo() port.AnnotationType
 func (f *FirestoreUnitOfWorkFactory) GetContentRepo() port.ContentRepository {
 	return f.contentRepo
 }
+
+func (f *FirestoreUnitOfWorkFactory) GetOutboxRepo() port.OutboxRepository {
+	return f.outboxRepo
+}
+
+func (f *FirestoreUnitOfWorkFactory) GetWebhookRepo() port.WebhookRepository {
+	return f.webhookRepo
+}
+
+func (f *FirestoreUnitOfWorkFactory) GetWebhookDeliveryRepo() port.WebhookDeliveryRepository {
+	return f.deliveryRepo
+}
+
+func (f *FirestoreUnitOfWorkFactory) GetProcessingJobRepo() port.ProcessingJobRepository {
+	return f.processingJobRepo
+}
+
+func (f *FirestoreUnitOfWorkFactory) GetProcessingQueueRepo() port.ProcessingQueueRepository {
+	return f.queueRepo
+}
@@ -0,0 +1,179 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	deliverySubscriptionID = "subscription_id"
+	deliveryWsID           = "ws_id"
+	deliveryEventID        = "event_id"
+	deliveryEventType      = "event_type"
+	deliveryPayload        = "payload"
+	deliveryStatus         = "status"
+	deliveryAttempts       = "attempts"
+	deliveryNextAttemptAt  = "next_attempt_at"
+	deliveryLastStatusCode = "last_status_code"
+	deliveryLastError      = "last_error"
+	deliveryDeliveredAt    = "delivered_at"
+	deliveryRedeliveryOf   = "redelivery_of"
+	deliveryCreatedAt      = "created_at"
+	deliveryUpdatedAt      = "updated_at"
+)
+
+type WebhookDeliveryRepositoryImpl struct {
+	client     *firestore.Client
+	collection string
+}
+
+func NewWebhookDeliveryRepositoryImpl(client *firestore.Client, collection string) *WebhookDeliveryRepositoryImpl {
+	return &WebhookDeliveryRepositoryImpl{
+		client:     client,
+		collection: collection,
+	}
+}
+
+func (r *WebhookDeliveryRepositoryImpl) Save(ctx context.Context, delivery *model.WebhookDelivery) error {
+	if delivery == nil {
+		return ErrInvalidInput
+	}
+
+	if delivery.ID == "" {
+		delivery.ID = r.client.Collection(r.collection).NewDoc().ID
+	}
+	now := time.Now()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = now
+	}
+	delivery.UpdatedAt = now
+
+	docRef := r.client.Collection(r.collection).Doc(delivery.ID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Set(docRef, deliveryToFirestoreMap(delivery))
+	} else {
+		_, err = docRef.Set(ctx, deliveryToFirestoreMap(delivery))
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *WebhookDeliveryRepositoryImpl) Read(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	docRef := r.client.Collection(r.collection).Doc(id)
+
+	var doc *firestore.DocumentSnapshot
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		doc, err = tx.Get(docRef)
+	} else {
+		doc, err = docRef.Get(ctx)
+	}
+	if err != nil {
+		return nil, mapFirestoreError(err)
+	}
+
+	return deliveryFromFirestoreDoc(doc), nil
+}
+
+func (r *WebhookDeliveryRepositoryImpl) FetchDue(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	q := r.client.Collection(r.collection).
+		Where(deliveryStatus, "==", string(model.WebhookDeliveryPending)).
+		Where(deliveryNextAttemptAt, "<=", now).
+		OrderBy(deliveryNextAttemptAt, firestore.Asc).
+		Limit(limit)
+
+	return r.list(ctx, q)
+}
+
+func (r *WebhookDeliveryRepositoryImpl) ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	q := r.client.Collection(r.collection).
+		Where(deliverySubscriptionID, "==", subscriptionID).
+		OrderBy(deliveryCreatedAt, firestore.Desc).
+		Limit(limit)
+
+	return r.list(ctx, q)
+}
+
+func (r *WebhookDeliveryRepositoryImpl) list(ctx context.Context, q firestore.Query) ([]*model.WebhookDelivery, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	deliveries := []*model.WebhookDelivery{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			if isCollectionNotFoundError(err) {
+				return deliveries, nil
+			}
+			return nil, mapFirestoreError(err)
+		}
+		deliveries = append(deliveries, deliveryFromFirestoreDoc(doc))
+	}
+
+	return deliveries, nil
+}
+
+func deliveryToFirestoreMap(d *model.WebhookDelivery) map[string]interface{} {
+	m := map[string]interface{}{
+		deliverySubscriptionID: d.SubscriptionID,
+		deliveryWsID:           d.WsID,
+		deliveryEventID:        d.EventID,
+		deliveryEventType:      d.EventType,
+		deliveryPayload:        string(d.Payload),
+		deliveryStatus:         string(d.Status),
+		deliveryAttempts:       d.Attempts,
+		deliveryNextAttemptAt:  d.NextAttemptAt,
+		deliveryLastStatusCode: d.LastStatusCode,
+		deliveryLastError:      d.LastError,
+		deliveryCreatedAt:      d.CreatedAt,
+		deliveryUpdatedAt:      d.UpdatedAt,
+	}
+	if d.DeliveredAt != nil {
+		m[deliveryDeliveredAt] = *d.DeliveredAt
+	}
+	if d.RedeliveryOf != "" {
+		m[deliveryRedeliveryOf] = d.RedeliveryOf
+	}
+	return m
+}
+
+func deliveryFromFirestoreDoc(doc *firestore.DocumentSnapshot) *model.WebhookDelivery {
+	data := doc.Data()
+	d := &model.WebhookDelivery{ID: doc.Ref.ID}
+
+	d.SubscriptionID, _ = data[deliverySubscriptionID].(string)
+	d.WsID, _ = data[deliveryWsID].(string)
+	d.EventID, _ = data[deliveryEventID].(string)
+	d.EventType, _ = data[deliveryEventType].(string)
+	if v, ok := data[deliveryPayload].(string); ok {
+		d.Payload = []byte(v)
+	}
+	if v, ok := data[deliveryStatus].(string); ok {
+		d.Status = model.WebhookDeliveryStatus(v)
+	}
+	if v, ok := data[deliveryAttempts].(int64); ok {
+		d.Attempts = int(v)
+	}
+	d.NextAttemptAt, _ = data[deliveryNextAttemptAt].(time.Time)
+	if v, ok := data[deliveryLastStatusCode].(int64); ok {
+		d.LastStatusCode = int(v)
+	}
+	d.LastError, _ = data[deliveryLastError].(string)
+	if v, ok := data[deliveryDeliveredAt].(time.Time); ok {
+		d.DeliveredAt = &v
+	}
+	d.RedeliveryOf, _ = data[deliveryRedeliveryOf].(string)
+	d.CreatedAt, _ = data[deliveryCreatedAt].(time.Time)
+	d.UpdatedAt, _ = data[deliveryUpdatedAt].(time.Time)
+
+	return d
+}
@@ -0,0 +1,174 @@
+package firestore
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	webhookWsID       = "ws_id"
+	webhookCreatorID  = "creator_id"
+	webhookURL        = "url"
+	webhookSecret     = "secret"
+	webhookActive     = "active"
+	webhookEventTypes = "event_types"
+	webhookCreatedAt  = "created_at"
+	webhookUpdatedAt  = "updated_at"
+)
+
+type WebhookRepositoryImpl struct {
+	client     *firestore.Client
+	collection string
+}
+
+func NewWebhookRepositoryImpl(client *firestore.Client, collection string) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{
+		client:     client,
+		collection: collection,
+	}
+}
+
+func (r *WebhookRepositoryImpl) Create(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	if subscription == nil {
+		return nil, ErrInvalidInput
+	}
+
+	if subscription.ID == "" {
+		subscription.ID = r.client.Collection(r.collection).NewDoc().ID
+	}
+	now := time.Now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+
+	docRef := r.client.Collection(r.collection).Doc(subscription.ID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Create(docRef, webhookToFirestoreMap(subscription))
+	} else {
+		_, err = docRef.Create(ctx, webhookToFirestoreMap(subscription))
+	}
+	if err != nil {
+		return nil, mapFirestoreError(err)
+	}
+
+	return subscription, nil
+}
+
+func (r *WebhookRepositoryImpl) Read(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	docRef := r.client.Collection(r.collection).Doc(id)
+
+	var doc *firestore.DocumentSnapshot
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		doc, err = tx.Get(docRef)
+	} else {
+		doc, err = docRef.Get(ctx)
+	}
+	if err != nil {
+		return nil, mapFirestoreError(err)
+	}
+
+	return webhookFromFirestoreDoc(doc), nil
+}
+
+func (r *WebhookRepositoryImpl) Update(ctx context.Context, subscription *model.WebhookSubscription) error {
+	if subscription == nil || subscription.ID == "" {
+		return ErrInvalidInput
+	}
+
+	subscription.UpdatedAt = time.Now()
+	docRef := r.client.Collection(r.collection).Doc(subscription.ID)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Set(docRef, webhookToFirestoreMap(subscription))
+	} else {
+		_, err = docRef.Set(ctx, webhookToFirestoreMap(subscription))
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *WebhookRepositoryImpl) Delete(ctx context.Context, id string) error {
+	docRef := r.client.Collection(r.collection).Doc(id)
+
+	var err error
+	if tx := fromCtx(ctx); tx != nil {
+		err = tx.Delete(docRef)
+	} else {
+		_, err = docRef.Delete(ctx)
+	}
+
+	return mapFirestoreError(err)
+}
+
+func (r *WebhookRepositoryImpl) ListByWorkspace(ctx context.Context, wsID string, activeOnly bool) ([]*model.WebhookSubscription, error) {
+	q := r.client.Collection(r.collection).Where(webhookWsID, "==", wsID)
+	if activeOnly {
+		q = q.Where(webhookActive, "==", true)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	subscriptions := []*model.WebhookSubscription{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			if isCollectionNotFoundError(err) {
+				return subscriptions, nil
+			}
+			return nil, mapFirestoreError(err)
+		}
+		subscriptions = append(subscriptions, webhookFromFirestoreDoc(doc))
+	}
+
+	return subscriptions, nil
+}
+
+func webhookToFirestoreMap(s *model.WebhookSubscription) map[string]interface{} {
+	m := map[string]interface{}{
+		webhookWsID:      s.WsID,
+		webhookCreatorID: s.CreatorID,
+		webhookURL:       s.URL,
+		webhookSecret:    s.Secret,
+		webhookActive:    s.Active,
+		webhookCreatedAt: s.CreatedAt,
+		webhookUpdatedAt: s.UpdatedAt,
+	}
+	if len(s.EventTypes) > 0 {
+		m[webhookEventTypes] = s.EventTypes
+	}
+	return m
+}
+
+func webhookFromFirestoreDoc(doc *firestore.DocumentSnapshot) *model.WebhookSubscription {
+	data := doc.Data()
+	s := &model.WebhookSubscription{ID: doc.Ref.ID}
+
+	s.WsID, _ = data[webhookWsID].(string)
+	s.CreatorID, _ = data[webhookCreatorID].(string)
+	s.URL, _ = data[webhookURL].(string)
+	s.Secret, _ = data[webhookSecret].(string)
+	s.Active, _ = data[webhookActive].(bool)
+	s.CreatedAt, _ = data[webhookCreatedAt].(time.Time)
+	s.UpdatedAt, _ = data[webhookUpdatedAt].(time.Time)
+
+	if v, ok := data[webhookEventTypes].([]interface{}); ok {
+		for _, t := range v {
+			if str, ok := t.(string); ok {
+				s.EventTypes = append(s.EventTypes, str)
+			}
+		}
+	}
+
+	return s
+}
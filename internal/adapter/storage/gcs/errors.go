@@ -8,6 +8,8 @@ import (
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
+
+	apperrors "github.com/histopathai/main-service/internal/shared/errors"
 )
 
 var (
@@ -23,15 +25,16 @@ func mapGCSError(err error, context string) error {
 		return nil
 	}
 
+	// Missing objects are the caller's concern, e.g. a tile that does not exist
 	if errors.Is(err, storage.ErrObjectNotExist) {
-		return ErrNotFound
+		return &apperrors.Err{Type: apperrors.ErrorTypeNotFound, Message: "object not found", Err: ErrNotFound}
 	}
 
 	var gErr *googleapi.Error
 	if errors.As(err, &gErr) {
 		switch gErr.Code {
 		case http.StatusNotFound:
-			return fmt.Errorf("%s: %w: %v", context, ErrNotFound, err)
+			return &apperrors.Err{Type: apperrors.ErrorTypeNotFound, Message: "object not found", Err: fmt.Errorf("%s: %w: %v", context, ErrNotFound, err)}
 		case http.StatusForbidden:
 			return fmt.Errorf("%s: %w: %v", context, ErrForbidden, err)
 		case http.StatusUnauthorized:
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -9,7 +10,10 @@ import (
 	runpb "cloud.google.com/go/run/apiv2/runpb"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
 	"github.com/histopathai/main-service/pkg/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -18,10 +22,11 @@ const (
 )
 
 type CloudRunWorker struct {
-	client    *run.JobsClient
-	config    config.WorkerConfig
-	gcpConfig config.GCPConfig
-	logger    *slog.Logger
+	client     *run.JobsClient
+	executions *run.ExecutionsClient
+	config     config.WorkerConfig
+	gcpConfig  config.GCPConfig
+	logger     *slog.Logger
 }
 
 func NewCloudRunWorker(ctx context.Context, cfg config.WorkerConfig, gcpCfg config.GCPConfig, logger *slog.Logger) (*CloudRunWorker, error) {
@@ -29,16 +34,22 @@ func NewCloudRunWorker(ctx context.Context, cfg config.WorkerConfig, gcpCfg conf
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Cloud Run Jobs client: %w", err)
 	}
+	executions, err := run.NewExecutionsClient(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to create Cloud Run Executions client: %w", err)
+	}
 
 	return &CloudRunWorker{
-		client:    client,
-		config:    cfg,
-		gcpConfig: gcpCfg,
-		logger:    logger,
+		client:     client,
+		executions: executions,
+		config:     cfg,
+		gcpConfig:  gcpCfg,
+		logger:     logger,
 	}, nil
 }
 
-func (w *CloudRunWorker) ProcessImage(ctx context.Context, content model.Content, processingVersion vobj.ProcessingVersion) error {
+func (w *CloudRunWorker) ProcessImage(ctx context.Context, content model.Content, processingVersion vobj.ProcessingVersion, profile vobj.ProcessingProfile) (*port.WorkerExecution, error) {
 	// Config already contains full job path: projects/{project}/locations/{region}/jobs/{job-name}
 	fullJobName := w.determineJobName(content.Size)
 
@@ -51,6 +62,7 @@ func (w *CloudRunWorker) ProcessImage(ctx context.Context, content model.Content
 		slog.Int64("size_bytes", content.Size),
 		slog.String("job_name", fullJobName),
 		slog.String("version", processingVersion.String()),
+		slog.String("profile", profile.String()),
 	)
 
 	req := &runpb.RunJobRequest{
@@ -75,6 +87,15 @@ func (w *CloudRunWorker) ProcessImage(ctx context.Context, content model.Content
 							Name:   "INPUT_PROCESSING_VERSION",
 							Values: &runpb.EnvVar_Value{Value: processingVersion.String()},
 						},
+						{
+							Name:   "INPUT_PROCESSING_PROFILE",
+							Values: &runpb.EnvVar_Value{Value: profile.String()},
+						},
+						{
+							// Echoed back as request_event_id in the completion event
+							Name:   "INPUT_REQUEST_EVENT_ID",
+							Values: &runpb.EnvVar_Value{Value: port.ProcessingEventIDFrom(ctx)},
+						},
 					},
 				},
 			},
@@ -83,28 +104,108 @@ func (w *CloudRunWorker) ProcessImage(ctx context.Context, content model.Content
 
 	op, err := w.client.RunJob(ctx, req)
 	if err != nil {
-		return fmt.Errorf("failed to run job (%s): %w", fullJobName, err)
+		return nil, fmt.Errorf("failed to run job (%s): %w", fullJobName, err)
+	}
+
+	// The operation's metadata is the execution it created
+	execution, err := op.Metadata()
+	if err != nil || execution.GetName() == "" {
+		return nil, fmt.Errorf("job (%s) started without an execution name: %v", fullJobName, err)
 	}
 
 	w.logger.Info("Cloud Run job triggered successfully",
 		slog.String("operation", op.Name()),
+		slog.String("execution", execution.GetName()),
 		slog.String("target_job", fullJobName))
 
-	return nil
+	return &port.WorkerExecution{
+		Backend: "cloudrun",
+		Name:    execution.GetName(),
+		Tier:    string(tierForSize(content.Size)),
+	}, nil
+}
+
+func (w *CloudRunWorker) GetExecutionStatus(ctx context.Context, executionName string) (*port.ExecutionStatus, error) {
+	execution, err := w.executions.GetExecution(ctx, &runpb.GetExecutionRequest{Name: executionName})
+	if status.Code(err) == codes.NotFound {
+		return &port.ExecutionStatus{State: port.ExecutionNotFound}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution (%s): %w", executionName, err)
+	}
+
+	return cloudRunExecutionStatus(execution), nil
+}
+
+func cloudRunExecutionStatus(execution *runpb.Execution) *port.ExecutionStatus {
+	if execution.GetCompletionTime() == nil {
+		return &port.ExecutionStatus{State: port.ExecutionRunning}
+	}
+	if execution.GetFailedCount() == 0 && execution.GetCancelledCount() == 0 &&
+		execution.GetSucceededCount() >= execution.GetTaskCount() {
+		return &port.ExecutionStatus{State: port.ExecutionSucceeded}
+	}
+
+	reason := fmt.Sprintf("%d of %d tasks failed, %d cancelled",
+		execution.GetFailedCount(), execution.GetTaskCount(), execution.GetCancelledCount())
+	for _, cond := range execution.GetConditions() {
+		if cond.GetType() == "Completed" && cond.GetMessage() != "" {
+			reason = cond.GetMessage()
+		}
+	}
+	return &port.ExecutionStatus{State: port.ExecutionFailed, Reason: reason}
+}
+
+// CancelExecution requests cancellation without waiting for it to take effect.
+func (w *CloudRunWorker) CancelExecution(ctx context.Context, executionName string) error {
+	_, err := w.executions.CancelExecution(ctx, &runpb.CancelExecutionRequest{Name: executionName})
+	switch status.Code(err) {
+	case codes.OK, codes.NotFound, codes.FailedPrecondition:
+		// FailedPrecondition: the execution already finished
+		return nil
+	default:
+		return fmt.Errorf("failed to cancel execution (%s): %w", executionName, err)
+	}
+}
+
+// Stop closes the Cloud Run clients; started executions keep running.
+func (w *CloudRunWorker) Stop() error {
+	return errors.Join(w.client.Close(), w.executions.Close())
 }
 
 func (w *CloudRunWorker) determineJobName(size int64) string {
 	if size <= 0 {
 		w.logger.Warn("File size is 0 or unknown, defaulting to SMALL worker")
+	}
+
+	switch tierForSize(size) {
+	case TierMedium:
+		return w.config.JobMedium
+	case TierLarge:
+		return w.config.JobLarge
+	default:
 		return w.config.JobSmall
 	}
+}
+
+// WorkerTier sizes the job running a slide; every backend maps it to its own
+// resources.
+type WorkerTier string
+
+const (
+	TierSmall  WorkerTier = "small"
+	TierMedium WorkerTier = "medium"
+	TierLarge  WorkerTier = "large"
+)
 
+func tierForSize(size int64) WorkerTier {
 	switch {
 	case size < Size128MB:
-		return w.config.JobSmall
+		// Includes unknown (0) sizes
+		return TierSmall
 	case size < Size1GB:
-		return w.config.JobMedium
+		return TierMedium
 	default:
-		return w.config.JobLarge
+		return TierLarge
 	}
 }
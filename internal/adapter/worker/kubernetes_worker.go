@@ -0,0 +1,473 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+	"github.com/histopathai/main-service/pkg/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	LabelImageID   = "histopath.ai/image-id"
+	LabelEventID   = "histopath.ai/event-id"
+	LabelTier      = "histopath.ai/tier"
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+
+	managedByValue = "histopath-main-service"
+	// Delay before re-opening a failed or expired watch
+	watchRetryDelay = 5 * time.Second
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// KubernetesWorker runs each slide as a batch/v1 Job built from a template,
+// with resource requests picked by slide size. Like the Cloud Run jobs, the
+// container publishes its own result; the worker watches the Job and reports
+// a failure when the Job fails or disappears without finishing.
+type KubernetesWorker struct {
+	client    kubernetes.Interface
+	config    config.KubernetesWorkerConfig
+	gcpConfig config.GCPConfig
+	template  *batchv1.Job
+	tiers     map[WorkerTier]corev1.ResourceRequirements
+	publisher portevent.EventPublisher
+	logger    *slog.Logger
+
+	// Names of jobs deleted by CancelExecution, whose deletion is not a failure
+	cancelled sync.Map
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewKubernetesClientset uses the kubeconfig at path, or the in-cluster
+// configuration when path is empty.
+func NewKubernetesClientset(path string) (kubernetes.Interface, error) {
+	var restConfig *rest.Config
+	var err error
+	if path == "" {
+		restConfig, err = rest.InClusterConfig()
+	} else {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+func NewKubernetesWorker(
+	client kubernetes.Interface,
+	cfg config.KubernetesWorkerConfig,
+	gcpCfg config.GCPConfig,
+	publisher portevent.EventPublisher,
+	logger *slog.Logger,
+) (*KubernetesWorker, error) {
+	template, err := loadJobTemplate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers := make(map[WorkerTier]corev1.ResourceRequirements, 3)
+	for tier, quantities := range map[WorkerTier]config.KubernetesResourceTier{
+		TierSmall:  cfg.Small,
+		TierMedium: cfg.Medium,
+		TierLarge:  cfg.Large,
+	} {
+		requirements, err := resourceRequirements(quantities)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s tier resources: %w", tier, err)
+		}
+		tiers[tier] = requirements
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &KubernetesWorker{
+		client:    client,
+		config:    cfg,
+		gcpConfig: gcpCfg,
+		template:  template,
+		tiers:     tiers,
+		publisher: publisher,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+	}, nil
+}
+
+func (w *KubernetesWorker) ProcessImage(ctx context.Context, content model.Content, processingVersion vobj.ProcessingVersion, profile vobj.ProcessingProfile) (*port.WorkerExecution, error) {
+	imageID := content.Parent.ID
+	eventID := port.ProcessingEventIDFrom(ctx)
+	tier := tierForSize(content.Size)
+
+	job := w.buildJob(content, processingVersion, profile, eventID, tier)
+
+	created, err := w.client.BatchV1().Jobs(w.config.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job for image %s: %w", imageID, err)
+	}
+
+	w.logger.Info("Kubernetes job created",
+		slog.String("image_id", imageID),
+		slog.String("event_id", eventID),
+		slog.String("job", created.Name),
+		slog.String("tier", string(tier)),
+		slog.Int64("size_bytes", content.Size),
+		slog.String("version", processingVersion.String()),
+		slog.String("profile", profile.String()),
+	)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.watchJob(watchedJob{name: created.Name, imageID: imageID, eventID: eventID, version: processingVersion})
+	}()
+
+	return &port.WorkerExecution{
+		Backend: "kubernetes",
+		Name:    created.Name,
+		Tier:    string(tier),
+	}, nil
+}
+
+func (w *KubernetesWorker) GetExecutionStatus(ctx context.Context, executionName string) (*port.ExecutionStatus, error) {
+	job, err := w.client.BatchV1().Jobs(w.config.Namespace).Get(ctx, executionName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &port.ExecutionStatus{State: port.ExecutionNotFound}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", executionName, err)
+	}
+	return kubernetesJobStatus(job), nil
+}
+
+// CancelExecution deletes the job and its pods.
+func (w *KubernetesWorker) CancelExecution(ctx context.Context, executionName string) error {
+	w.cancelled.Store(executionName, struct{}{})
+
+	propagation := metav1.DeletePropagationBackground
+	err := w.client.BatchV1().Jobs(w.config.Namespace).Delete(ctx, executionName, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		w.cancelled.Delete(executionName)
+		return fmt.Errorf("failed to delete job %s: %w", executionName, err)
+	}
+	return nil
+}
+
+func kubernetesJobStatus(job *batchv1.Job) *port.ExecutionStatus {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return &port.ExecutionStatus{State: port.ExecutionSucceeded}
+		case batchv1.JobFailed:
+			reason := fmt.Sprintf("kubernetes job %s failed: %s", job.Name, cond.Reason)
+			if cond.Message != "" {
+				reason += ": " + cond.Message
+			}
+			return &port.ExecutionStatus{State: port.ExecutionFailed, Reason: reason}
+		}
+	}
+	return &port.ExecutionStatus{State: port.ExecutionRunning}
+}
+
+// Stop ends the job watches; the Jobs themselves keep running.
+func (w *KubernetesWorker) Stop() error {
+	w.cancel()
+	w.wg.Wait()
+	return nil
+}
+
+func (w *KubernetesWorker) buildJob(content model.Content, processingVersion vobj.ProcessingVersion, profile vobj.ProcessingProfile, eventID string, tier WorkerTier) *batchv1.Job {
+	imageID := content.Parent.ID
+	job := w.template.DeepCopy()
+
+	job.Name = jobName(imageID)
+	job.Namespace = w.config.Namespace
+
+	labels := map[string]string{
+		LabelImageID:   labelValue(imageID),
+		LabelTier:      string(tier),
+		LabelManagedBy: managedByValue,
+	}
+	if eventID != "" {
+		labels[LabelEventID] = labelValue(eventID)
+	}
+	job.Labels = mergeLabels(job.Labels, labels)
+	job.Spec.Template.Labels = mergeLabels(job.Spec.Template.Labels, labels)
+
+	// Template values win over the configured defaults
+	if job.Spec.ActiveDeadlineSeconds == nil {
+		job.Spec.ActiveDeadlineSeconds = ptr.To(int64(w.config.ActiveDeadline.Seconds()))
+	}
+	if job.Spec.TTLSecondsAfterFinished == nil && w.config.TTLAfterFinished > 0 {
+		job.Spec.TTLSecondsAfterFinished = ptr.To(int32(w.config.TTLAfterFinished.Seconds()))
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "INPUT_IMAGE_ID", Value: imageID},
+		{Name: "INPUT_ORIGIN_PATH", Value: content.Path},
+		{Name: "INPUT_BUCKET_NAME", Value: w.gcpConfig.OriginalBucketName},
+		{Name: "INPUT_PROCESSING_VERSION", Value: processingVersion.String()},
+		{Name: "INPUT_PROCESSING_PROFILE", Value: profile.String()},
+		{Name: "INPUT_REQUEST_EVENT_ID", Value: eventID},
+	}
+	containers := job.Spec.Template.Spec.Containers
+	for i := range containers {
+		containers[i].Env = append(containers[i].Env, env...)
+	}
+	// The first container is the tiler
+	containers[0].Resources = w.tiers[tier]
+
+	return job
+}
+
+// watchedJob identifies a started job and the request it serves.
+type watchedJob struct {
+	name    string
+	imageID string
+	eventID string
+	version vobj.ProcessingVersion
+}
+
+func (w *KubernetesWorker) watchJob(ref watchedJob) {
+	name := ref.name
+	logger := w.logger.With(slog.String("image_id", ref.imageID), slog.String("job", name))
+	jobs := w.client.BatchV1().Jobs(w.config.Namespace)
+
+	for {
+		watcher, err := jobs.Watch(w.ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+		})
+		if err != nil {
+			if w.ctx.Err() != nil {
+				return
+			}
+			logger.Warn("Failed to watch job, retrying", slog.String("error", err.Error()))
+			if !w.sleep(watchRetryDelay) {
+				return
+			}
+			continue
+		}
+
+		// The job may have finished before the watch was opened
+		finished := false
+		current, err := jobs.Get(w.ctx, name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			finished = w.handleJobState(current, ref, logger)
+		case apierrors.IsNotFound(err):
+			w.handleJobGone(ref, "disappeared", logger)
+			finished = true
+		case w.ctx.Err() == nil:
+			logger.Warn("Failed to read job", slog.String("error", err.Error()))
+		}
+
+		if !finished {
+			finished = w.consumeWatch(watcher, ref, logger)
+		}
+		watcher.Stop()
+
+		// Otherwise the watch expired server-side; re-open it
+		if finished || w.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// consumeWatch follows the job until it finishes (true) or the watch closes (false).
+func (w *KubernetesWorker) consumeWatch(watcher watch.Interface, ref watchedJob, logger *slog.Logger) bool {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return false
+		case ev, ok := <-watcher.ResultChan():
+			if !ok {
+				return false
+			}
+			job, isJob := ev.Object.(*batchv1.Job)
+			if !isJob || job.Name != ref.name {
+				continue
+			}
+			if ev.Type == watch.Deleted {
+				w.handleJobGone(ref, "was deleted", logger)
+				return true
+			}
+			if w.handleJobState(job, ref, logger) {
+				return true
+			}
+		}
+	}
+}
+
+// handleJobGone publishes a failure for a job removed before finishing,
+// unless it was removed by CancelExecution.
+func (w *KubernetesWorker) handleJobGone(ref watchedJob, how string, logger *slog.Logger) {
+	if _, cancelled := w.cancelled.LoadAndDelete(ref.name); cancelled {
+		logger.Info("Kubernetes job cancelled")
+		return
+	}
+	w.publishFailure(ref, fmt.Sprintf("kubernetes job %s %s before finishing", ref.name, how), logger)
+}
+
+// handleJobState reports whether the job has finished, publishing a failure
+// result for failed jobs.
+func (w *KubernetesWorker) handleJobState(job *batchv1.Job, ref watchedJob, logger *slog.Logger) bool {
+	status := kubernetesJobStatus(job)
+	switch status.State {
+	case port.ExecutionSucceeded:
+		logger.Info("Kubernetes job completed")
+		return true
+	case port.ExecutionFailed:
+		w.publishFailure(ref, status.Reason, logger)
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *KubernetesWorker) publishFailure(ref watchedJob, reason string, logger *slog.Logger) {
+	logger.Error("Kubernetes job failed", slog.String("reason", reason))
+
+	event := &domainevent.ImageProcessCompleteEvent{
+		BaseEvent: domainevent.BaseEvent{
+			EventID:   uuid.New().String(),
+			EventType: domainevent.ImageProcessCompleteEventType,
+			Timestamp: time.Now(),
+		},
+		ImageID:           ref.imageID,
+		RequestEventID:    ref.eventID,
+		ProcessingVersion: ref.version,
+		FailureReason:     reason,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := w.publisher.Publish(ctx, event); err != nil {
+		logger.Error("Failed to publish processing failure", slog.String("error", err.Error()))
+	}
+}
+
+func (w *KubernetesWorker) sleep(d time.Duration) bool {
+	select {
+	case <-w.ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func loadJobTemplate(cfg config.KubernetesWorkerConfig) (*batchv1.Job, error) {
+	if cfg.JobTemplate == "" {
+		return &batchv1.Job{
+			Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.To(int32(0)),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers:    []corev1.Container{{Name: "tiler", Image: cfg.Image}},
+					},
+				},
+			},
+		}, nil
+	}
+
+	data, err := os.ReadFile(cfg.JobTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job template: %w", err)
+	}
+	job := &batchv1.Job{}
+	if err := yaml.UnmarshalStrict(data, job); err != nil {
+		return nil, fmt.Errorf("failed to parse job template: %w", err)
+	}
+	if len(job.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("job template has no containers")
+	}
+	if cfg.Image != "" && job.Spec.Template.Spec.Containers[0].Image == "" {
+		job.Spec.Template.Spec.Containers[0].Image = cfg.Image
+	}
+	return job, nil
+}
+
+func resourceRequirements(tier config.KubernetesResourceTier) (corev1.ResourceRequirements, error) {
+	cpu, err := resource.ParseQuantity(tier.CPU)
+	if err != nil {
+		return corev1.ResourceRequirements{}, fmt.Errorf("cpu: %w", err)
+	}
+	memory, err := resource.ParseQuantity(tier.Memory)
+	if err != nil {
+		return corev1.ResourceRequirements{}, fmt.Errorf("memory: %w", err)
+	}
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    cpu,
+			corev1.ResourceMemory: memory,
+		},
+		// Tiling memory use is predictable; going over it means a runaway
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: memory,
+		},
+	}, nil
+}
+
+// jobName builds a unique DNS-1123 name that still shows the image.
+func jobName(imageID string) string {
+	slug := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(imageID), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	if slug == "" {
+		return "tile-" + utilrand.String(8)
+	}
+	return "tile-" + slug + "-" + utilrand.String(5)
+}
+
+func labelValue(v string) string {
+	v = invalidLabelChars.ReplaceAllString(v, "-")
+	if len(v) > 63 {
+		v = v[:63]
+	}
+	return strings.Trim(v, "-_.")
+}
+
+func mergeLabels(base, extra map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestKubernetesWorker(t *testing.T) (*KubernetesWorker, *fake.Clientset, chanPublisher) {
+	client := fake.NewClientset()
+	published := make(chanPublisher, 4)
+	w, err := NewKubernetesWorker(
+		client,
+		config.KubernetesWorkerConfig{
+			Namespace:      "tiling",
+			Image:          "registry.local/tiler:1",
+			ActiveDeadline: time.Hour,
+			Small:          config.KubernetesResourceTier{CPU: "1", Memory: "2Gi"},
+			Medium:         config.KubernetesResourceTier{CPU: "2", Memory: "8Gi"},
+			Large:          config.KubernetesResourceTier{CPU: "4", Memory: "32Gi"},
+		},
+		config.GCPConfig{OriginalBucketName: "origin-bucket"},
+		published,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = w.Stop() })
+	return w, client, published
+}
+
+func createdJob(t *testing.T, client *fake.Clientset) *batchv1.Job {
+	jobs, err := client.BatchV1().Jobs("tiling").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+	return &jobs.Items[0]
+}
+
+func TestKubernetesWorker_CreatesTieredLabelledJob(t *testing.T) {
+	w, client, _ := newTestKubernetesWorker(t)
+
+	content := model.Content{
+		Entity: vobj.Entity{Parent: vobj.ParentRef{ID: "img-1", Type: vobj.ParentTypeImage}},
+		Path:   "uploads/img-1.svs",
+		Size:   512 * 1024 * 1024,
+	}
+	ctx := port.WithProcessingEventID(context.Background(), "evt-1")
+	execution, err := w.ProcessImage(ctx, content, vobj.ProcessingV2, vobj.ProfilePyramid)
+	require.NoError(t, err)
+
+	job := createdJob(t, client)
+	assert.Equal(t, job.Name, execution.Name)
+	assert.Equal(t, "medium", execution.Tier)
+	assert.Equal(t, "img-1", job.Labels[LabelImageID])
+	assert.Equal(t, "evt-1", job.Labels[LabelEventID])
+	assert.Equal(t, "medium", job.Labels[LabelTier])
+	assert.Equal(t, "evt-1", job.Spec.Template.Labels[LabelEventID])
+	assert.Equal(t, int64(3600), *job.Spec.ActiveDeadlineSeconds)
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "registry.local/tiler:1", container.Image)
+	assert.Equal(t, "2", container.Resources.Requests.Cpu().String())
+	assert.Equal(t, "8Gi", container.Resources.Requests.Memory().String())
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "INPUT_ORIGIN_PATH", Value: "uploads/img-1.svs"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "INPUT_BUCKET_NAME", Value: "origin-bucket"})
+}
+
+func TestKubernetesWorker_PublishesFailureWhenJobFails(t *testing.T) {
+	w, client, published := newTestKubernetesWorker(t)
+
+	content := model.Content{Entity: vobj.Entity{Parent: vobj.ParentRef{ID: "img-2", Type: vobj.ParentTypeImage}}}
+	execution, err := w.ProcessImage(port.WithProcessingEventID(context.Background(), "evt-2"), content, vobj.ProcessingV1, vobj.ProfilePyramid)
+	require.NoError(t, err)
+
+	job := createdJob(t, client)
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type:    batchv1.JobFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  "DeadlineExceeded",
+		Message: "Job was active longer than specified deadline",
+	})
+	_, err = client.BatchV1().Jobs("tiling").UpdateStatus(context.Background(), job, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	event := waitCompletion(t, published)
+	assert.False(t, event.Success)
+	assert.Equal(t, "img-2", event.ImageID)
+	assert.Equal(t, "evt-2", event.RequestEventID)
+	assert.Contains(t, event.FailureReason, "DeadlineExceeded")
+
+	status, err := w.GetExecutionStatus(context.Background(), execution.Name)
+	require.NoError(t, err)
+	assert.Equal(t, port.ExecutionFailed, status.State)
+
+	status, err = w.GetExecutionStatus(context.Background(), "tile-missing")
+	require.NoError(t, err)
+	assert.Equal(t, port.ExecutionNotFound, status.State)
+}
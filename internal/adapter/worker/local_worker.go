@@ -0,0 +1,361 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+	"github.com/histopathai/main-service/pkg/config"
+)
+
+const (
+	// Bytes of job output kept for the failure reason
+	failureTailSize = 2048
+	publishTimeout  = 30 * time.Second
+	// How long finished local executions can still be looked up
+	localExecutionRetention = time.Hour
+)
+
+// LocalWorker runs the tiling command as a subprocess on this machine, with
+// the same INPUT_* environment the Cloud Run jobs receive. When a job ends it
+// publishes the ImageProcessCompleteEvent itself, so the rest of the pipeline
+// is unchanged.
+//
+// The command may write a result message (the JSON published on the
+// image-processing-results topic) to OUTPUT_RESULT_FILE. If it exits cleanly
+// without one, a bare success is published.
+type LocalWorker struct {
+	command    []string
+	config     config.LocalWorkerConfig
+	gcpConfig  config.GCPConfig
+	publisher  portevent.EventPublisher
+	serializer portevent.EventSerializer
+	logger     *slog.Logger
+
+	slots      chan struct{}
+	mu         sync.Mutex
+	executions map[string]*localExecution
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewLocalWorker(
+	cfg config.LocalWorkerConfig,
+	gcpCfg config.GCPConfig,
+	publisher portevent.EventPublisher,
+	serializer portevent.EventSerializer,
+	logger *slog.Logger,
+) (*LocalWorker, error) {
+	command := strings.Fields(cfg.Command)
+	if len(command) == 0 {
+		return nil, fmt.Errorf("local worker command is empty")
+	}
+	if err := os.MkdirAll(cfg.LogDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local worker log dir: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &LocalWorker{
+		command:    command,
+		config:     cfg,
+		gcpConfig:  gcpCfg,
+		publisher:  publisher,
+		serializer: serializer,
+		logger:     logger,
+		slots:      make(chan struct{}, cfg.Concurrency),
+		executions: make(map[string]*localExecution),
+		ctx:        ctx,
+		cancel:     cancel,
+	}, nil
+}
+
+type localExecution struct {
+	status     port.ExecutionStatus
+	finishedAt time.Time
+	cancel     context.CancelFunc
+}
+
+// ProcessImage queues the job and returns; at most Concurrency jobs run at once.
+func (w *LocalWorker) ProcessImage(ctx context.Context, content model.Content, processingVersion vobj.ProcessingVersion, profile vobj.ProcessingProfile) (*port.WorkerExecution, error) {
+	if w.ctx.Err() != nil {
+		return nil, fmt.Errorf("local worker is stopped")
+	}
+
+	name := fmt.Sprintf("%s-%d", content.Parent.ID, time.Now().UnixNano())
+	requestEventID := port.ProcessingEventIDFrom(ctx)
+	execCtx, cancel := context.WithCancel(w.ctx)
+	w.mu.Lock()
+	w.executions[name] = &localExecution{status: port.ExecutionStatus{State: port.ExecutionRunning}, cancel: cancel}
+	w.mu.Unlock()
+
+	w.logger.Info("Local processing job queued",
+		slog.String("image_id", content.Parent.ID),
+		slog.String("execution", name),
+		slog.Int64("size_bytes", content.Size),
+		slog.String("version", processingVersion.String()),
+		slog.String("profile", profile.String()),
+	)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer cancel()
+
+		select {
+		case w.slots <- struct{}{}:
+		case <-execCtx.Done():
+			w.setExecution(name, port.ExecutionStatus{State: port.ExecutionFailed, Reason: w.stoppedReason()})
+			return
+		}
+		defer func() { <-w.slots }()
+
+		w.run(execCtx, name, requestEventID, content, processingVersion, profile)
+	}()
+
+	return &port.WorkerExecution{
+		Backend: "local",
+		Name:    name,
+		Tier:    string(tierForSize(content.Size)),
+	}, nil
+}
+
+// GetExecutionStatus only knows executions started by this process; after a
+// restart earlier ones are reported as not found.
+func (w *LocalWorker) GetExecutionStatus(ctx context.Context, executionName string) (*port.ExecutionStatus, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	execution, ok := w.executions[executionName]
+	if !ok {
+		return &port.ExecutionStatus{State: port.ExecutionNotFound}, nil
+	}
+	status := execution.status
+	return &status, nil
+}
+
+// CancelExecution kills the job's process, or drops it if still queued. The
+// cancelled job publishes no result.
+func (w *LocalWorker) CancelExecution(ctx context.Context, executionName string) error {
+	w.mu.Lock()
+	execution, ok := w.executions[executionName]
+	w.mu.Unlock()
+
+	if ok && execution.cancel != nil {
+		execution.cancel()
+	}
+	return nil
+}
+
+func (w *LocalWorker) stoppedReason() string {
+	if w.ctx.Err() != nil {
+		return "worker shut down"
+	}
+	return "cancelled"
+}
+
+func (w *LocalWorker) setExecution(name string, status port.ExecutionStatus) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	execution, ok := w.executions[name]
+	if !ok {
+		execution = &localExecution{}
+		w.executions[name] = execution
+	}
+	execution.status = status
+	if status.State != port.ExecutionRunning {
+		execution.finishedAt = now
+		execution.cancel = nil
+	}
+
+	for n, e := range w.executions {
+		if !e.finishedAt.IsZero() && now.Sub(e.finishedAt) > localExecutionRetention {
+			delete(w.executions, n)
+		}
+	}
+}
+
+// Stop kills running jobs and waits for them to report.
+func (w *LocalWorker) Stop() error {
+	w.cancel()
+	w.wg.Wait()
+	return nil
+}
+
+func (w *LocalWorker) run(execCtx context.Context, jobName string, requestEventID string, content model.Content, processingVersion vobj.ProcessingVersion, profile vobj.ProcessingProfile) {
+	imageID := content.Parent.ID
+	logPath := filepath.Join(w.config.LogDir, jobName+".log")
+	resultPath := filepath.Join(w.config.LogDir, jobName+".result.json")
+	defer os.Remove(resultPath)
+
+	logger := w.logger.With(slog.String("image_id", imageID), slog.String("log_file", logPath))
+	started := time.Now()
+
+	jobCtx, cancel := context.WithTimeout(execCtx, w.config.Timeout)
+	defer cancel()
+
+	runErr := w.exec(jobCtx, requestEventID, content, processingVersion, profile, logPath, resultPath)
+
+	var event *domainevent.ImageProcessCompleteEvent
+	switch {
+	case runErr == nil:
+		var err error
+		event, err = w.readResult(resultPath, imageID, processingVersion)
+		if err != nil {
+			event = w.failure(imageID, processingVersion, fmt.Sprintf("invalid result file: %v", err))
+		}
+	case errors.Is(jobCtx.Err(), context.DeadlineExceeded):
+		event = w.failure(imageID, processingVersion, fmt.Sprintf("timed out after %s", w.config.Timeout))
+	case execCtx.Err() != nil:
+		event = w.failure(imageID, processingVersion, w.stoppedReason())
+	default:
+		event = w.failure(imageID, processingVersion, runErr.Error())
+	}
+	event.RequestEventID = requestEventID
+
+	if event.Success {
+		w.setExecution(jobName, port.ExecutionStatus{State: port.ExecutionSucceeded})
+	} else {
+		w.setExecution(jobName, port.ExecutionStatus{State: port.ExecutionFailed, Reason: event.FailureReason})
+	}
+
+	logger.Info("Local processing job finished",
+		slog.Bool("success", event.Success),
+		slog.Duration("duration", time.Since(started)),
+		slog.String("failure_reason", event.FailureReason))
+
+	// Whoever cancelled the job already moved the image on
+	if execCtx.Err() != nil && w.ctx.Err() == nil {
+		return
+	}
+
+	ctx, cancelPublish := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancelPublish()
+	if err := w.publisher.Publish(ctx, event); err != nil {
+		logger.Error("Failed to publish processing result", slog.String("error", err.Error()))
+	}
+}
+
+func (w *LocalWorker) exec(ctx context.Context, requestEventID string, content model.Content, processingVersion vobj.ProcessingVersion, profile vobj.ProcessingProfile, logPath, resultPath string) error {
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to create job log: %w", err)
+	}
+	defer logFile.Close()
+
+	tail := &tailBuffer{limit: failureTailSize}
+	output := io.MultiWriter(logFile, tail)
+
+	cmd := exec.CommandContext(ctx, w.command[0], w.command[1:]...)
+	cmd.Env = append(os.Environ(),
+		"INPUT_IMAGE_ID="+content.Parent.ID,
+		"INPUT_ORIGIN_PATH="+content.Path,
+		"INPUT_BUCKET_NAME="+w.gcpConfig.OriginalBucketName,
+		"INPUT_PROCESSING_VERSION="+processingVersion.String(),
+		"INPUT_PROCESSING_PROFILE="+profile.String(),
+		"INPUT_REQUEST_EVENT_ID="+requestEventID,
+		"OUTPUT_RESULT_FILE="+resultPath,
+	)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// Don't hang on children that keep the output pipes open after a kill
+	cmd.WaitDelay = 10 * time.Second
+
+	if err := cmd.Run(); err != nil {
+		if out := strings.TrimSpace(tail.String()); out != "" {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+func (w *LocalWorker) readResult(path string, imageID string, processingVersion vobj.ProcessingVersion) (*domainevent.ImageProcessCompleteEvent, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		event := w.newCompleteEvent(imageID, processingVersion)
+		event.Success = true
+		return event, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := w.serializer.Deserialize(data, domainevent.ImageProcessCompleteEventType)
+	if err != nil {
+		return nil, err
+	}
+	event, ok := decoded.(*domainevent.ImageProcessCompleteEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected event %T", decoded)
+	}
+
+	// The job only knows what it produced; identity comes from the request
+	if event.EventID == "" {
+		event.EventID = uuid.New().String()
+	}
+	event.EventType = domainevent.ImageProcessCompleteEventType
+	event.ImageID = imageID
+	if event.ProcessingVersion == "" {
+		event.ProcessingVersion = processingVersion
+	}
+	return event, nil
+}
+
+func (w *LocalWorker) failure(imageID string, processingVersion vobj.ProcessingVersion, reason string) *domainevent.ImageProcessCompleteEvent {
+	event := w.newCompleteEvent(imageID, processingVersion)
+	event.FailureReason = reason
+	return event
+}
+
+func (w *LocalWorker) newCompleteEvent(imageID string, processingVersion vobj.ProcessingVersion) *domainevent.ImageProcessCompleteEvent {
+	return &domainevent.ImageProcessCompleteEvent{
+		BaseEvent: domainevent.BaseEvent{
+			EventID:   uuid.New().String(),
+			EventType: domainevent.ImageProcessCompleteEventType,
+			Timestamp: time.Now(),
+		},
+		ImageID:           imageID,
+		ProcessingVersion: processingVersion,
+	}
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	buf   []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.limit {
+		t.buf = t.buf[len(t.buf)-t.limit:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
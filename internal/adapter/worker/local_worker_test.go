@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/adapter/events/pubsub"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chanPublisher chan domainevent.Event
+
+func (p chanPublisher) Publish(ctx context.Context, event domainevent.Event) error {
+	p <- event
+	return nil
+}
+
+func newTestLocalWorker(t *testing.T, script string, timeout time.Duration) (*LocalWorker, chanPublisher, string) {
+	dir := t.TempDir()
+	scriptPath := filepath.Join(dir, "tile.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\n"+script), 0o755))
+
+	logDir := filepath.Join(dir, "logs")
+	published := make(chanPublisher, 4)
+	w, err := NewLocalWorker(
+		config.LocalWorkerConfig{Command: scriptPath, Concurrency: 1, Timeout: timeout, LogDir: logDir},
+		config.GCPConfig{OriginalBucketName: "origin-bucket"},
+		published,
+		pubsub.NewEventSerializer(),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = w.Stop() })
+	return w, published, logDir
+}
+
+func testOrigin() model.Content {
+	return model.Content{
+		Entity: vobj.Entity{Parent: vobj.ParentRef{ID: "img-1", Type: vobj.ParentTypeImage}},
+		Path:   "uploads/img-1.svs",
+	}
+}
+
+func waitCompletion(t *testing.T, published chanPublisher) *domainevent.ImageProcessCompleteEvent {
+	select {
+	case e := <-published:
+		return e.(*domainevent.ImageProcessCompleteEvent)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no completion event published")
+		return nil
+	}
+}
+
+func TestLocalWorker_PublishesResultFile(t *testing.T) {
+	w, published, logDir := newTestLocalWorker(t, `
+echo "tiling $INPUT_ORIGIN_PATH from $INPUT_BUCKET_NAME"
+cat > "$OUTPUT_RESULT_FILE" <<EOF
+{"event_type":"image.process.complete.v1","timestamp":"2024-01-01T12:00:00Z","image_id":"$INPUT_IMAGE_ID","success":true,"result":{"width":100,"height":50,"size":42}}
+EOF
+`, time.Minute)
+
+	ctx := port.WithProcessingEventID(context.Background(), "evt-1")
+	execution, err := w.ProcessImage(ctx, testOrigin(), vobj.ProcessingV2, vobj.ProfilePyramid)
+	require.NoError(t, err)
+	assert.Equal(t, "local", execution.Backend)
+
+	event := waitCompletion(t, published)
+	assert.True(t, event.Success)
+	assert.Equal(t, "img-1", event.ImageID)
+	assert.Equal(t, "evt-1", event.RequestEventID)
+	assert.NotEmpty(t, event.EventID)
+	assert.Equal(t, 100, event.Result.Width)
+
+	status, err := w.GetExecutionStatus(context.Background(), execution.Name)
+	require.NoError(t, err)
+	assert.Equal(t, port.ExecutionSucceeded, status.State)
+
+	logs, _ := filepath.Glob(filepath.Join(logDir, "img-1-*.log"))
+	require.Len(t, logs, 1)
+	data, _ := os.ReadFile(logs[0])
+	assert.Contains(t, string(data), "tiling uploads/img-1.svs from origin-bucket")
+}
+
+func TestLocalWorker_ReportsFailures(t *testing.T) {
+	t.Run("non-zero exit", func(t *testing.T) {
+		w, published, _ := newTestLocalWorker(t, "echo 'openslide: unsupported format' >&2\nexit 3\n", time.Minute)
+		_, err := w.ProcessImage(context.Background(), testOrigin(), vobj.ProcessingV2, vobj.ProfilePyramid)
+		require.NoError(t, err)
+
+		event := waitCompletion(t, published)
+		assert.False(t, event.Success)
+		assert.Contains(t, event.FailureReason, "exit status 3")
+		assert.Contains(t, event.FailureReason, "unsupported format")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		w, published, _ := newTestLocalWorker(t, "exec sleep 5\n", 100*time.Millisecond)
+		_, err := w.ProcessImage(context.Background(), testOrigin(), vobj.ProcessingV2, vobj.ProfilePyramid)
+		require.NoError(t, err)
+
+		event := waitCompletion(t, published)
+		assert.False(t, event.Success)
+		assert.Contains(t, event.FailureReason, "timed out")
+	})
+}
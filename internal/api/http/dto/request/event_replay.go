@@ -0,0 +1,13 @@
+package request
+
+import "time"
+
+type ReplayEventsRequest struct {
+	EventTypes []string   `json:"event_types,omitempty" example:"image.process.request.v1"`
+	ImageID    string     `json:"image_id,omitempty" example:"img-123"`
+	From       *time.Time `json:"from,omitempty" example:"2024-01-01T00:00:00Z"`
+	To         *time.Time `json:"to,omitempty" example:"2024-01-02T00:00:00Z"`
+	Limit      int        `json:"limit,omitempty" example:"100"`
+	// Defaults to true: events are only replayed when explicitly set to false
+	DryRun *bool `json:"dry_run,omitempty" example:"true"`
+}
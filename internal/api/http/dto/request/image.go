@@ -31,3 +31,24 @@ type UpdateImageRequest struct {
 	Height        *int                  `json:"height,omitempty" binding:"omitempty,gte=0"`
 	Magnification *MagnificationRequest `json:"magnification,omitempty"`
 }
+
+type ReprocessImageRequest struct {
+	// Defaults to the image's current processing version
+	ProcessingVersion string `json:"processing_version,omitempty" binding:"omitempty,oneof=v1 v2" example:"v2"`
+	// Defaults to normal
+	Priority string `json:"priority,omitempty" binding:"omitempty,oneof=high normal low" example:"high"`
+}
+
+// ImageRegionRequest selects a rectangle in full resolution pixels. Output size
+// comes from output_width and/or output_height, or from magnification.
+type ImageRegionRequest struct {
+	X             int     `form:"x" binding:"gte=0" example:"1024"`
+	Y             int     `form:"y" binding:"gte=0" example:"2048"`
+	Width         int     `form:"width" binding:"required,gt=0" example:"4000"`
+	Height        int     `form:"height" binding:"required,gt=0" example:"3000"`
+	OutputWidth   int     `form:"output_width" binding:"omitempty,gt=0" example:"1000"`
+	OutputHeight  int     `form:"output_height" binding:"omitempty,gt=0" example:"750"`
+	Magnification float64 `form:"magnification" binding:"omitempty,gt=0,excluded_with=OutputWidth OutputHeight" example:"10"`
+	Format        string  `form:"format" binding:"omitempty,oneof=jpeg png" example:"jpeg"`
+	Quality       int     `form:"quality" binding:"omitempty,gte=1,lte=100" example:"90"`
+}
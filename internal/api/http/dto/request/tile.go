@@ -0,0 +1,13 @@
+package request
+
+// TilePrefetchRequest describes the viewport a viewer shows, in pixels of
+// the given Deep Zoom level.
+type TilePrefetchRequest struct {
+	Level  *int `json:"level" binding:"required,min=0" example:"12"`
+	X      int  `json:"x" binding:"min=0" example:"2048"`
+	Y      int  `json:"y" binding:"min=0" example:"1024"`
+	Width  int  `json:"width" binding:"required,min=1" example:"1920"`
+	Height int  `json:"height" binding:"required,min=1" example:"1080"`
+	// Rings of tiles around the viewport; defaults to 1
+	Margin *int `json:"margin,omitempty" binding:"omitempty,min=0,max=3" example:"1"`
+}
@@ -0,0 +1,14 @@
+package request
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url" example:"https://lab.example.org/hooks/histopath"`
+	EventTypes []string `json:"event_types,omitempty" example:"image.processed.v1,annotation.*"`
+	Secret     *string  `json:"secret,omitempty" example:"whsec_2f1c..."`
+}
+
+type UpdateWebhookRequest struct {
+	URL        *string  `json:"url,omitempty" binding:"omitempty,url" example:"https://lab.example.org/hooks/histopath"`
+	EventTypes []string `json:"event_types,omitempty" example:"image.processed.v1"`
+	Secret     *string  `json:"secret,omitempty" example:"whsec_2f1c..."`
+	Active     *bool    `json:"active,omitempty" example:"true"`
+}
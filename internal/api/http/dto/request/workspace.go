@@ -9,6 +9,9 @@ type CreateWorkspaceRequest struct {
 	ResourceURL     *string  `json:"resource_url,omitempty" binding:"omitempty,url" example:"https://example.com/dataset"`
 	ReleaseYear     *int     `json:"release_year,omitempty" binding:"omitempty,gte=1900,lte=2100" example:"2023"`
 	AnnotationTypes []string `json:"annotation_types,omitempty" binding:"omitempty,dive" example:"['550e8400-e29b-41d4-a716-446655440000']"`
+	Members         []string `json:"members,omitempty" binding:"omitempty,dive" example:"['user-456']"`
+	// Redacts slide labels and macro images
+	DeIdentified bool `json:"de_identified,omitempty" example:"true"`
 }
 
 type UpdateWorkspaceRequest struct {
@@ -21,4 +24,7 @@ type UpdateWorkspaceRequest struct {
 	ResourceURL     *string  `json:"resource_url,omitempty" binding:"omitempty,url" example:"https://example.com/dataset"`
 	ReleaseYear     *int     `json:"release_year,omitempty" binding:"omitempty,gte=1900,lte=2100" example:"2023"`
 	AnnotationTypes []string `json:"annotation_types,omitempty" binding:"omitempty,dive" example:"['550e8400-e29b-41d4-a716-446655440000']"`
+	// Replaces the member list; an empty list removes every member
+	Members      []string `json:"members,omitempty" binding:"omitempty,dive" example:"['user-456']"`
+	DeIdentified *bool    `json:"de_identified,omitempty" example:"true"`
 }
@@ -0,0 +1,79 @@
+package response
+
+import (
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+)
+
+type EventLogEntryResponse struct {
+	EventID    string    `json:"event_id" example:"evt-456"`
+	EventType  string    `json:"event_type" example:"image.process.request.v1"`
+	Direction  string    `json:"direction" example:"consumed"`
+	Channel    string    `json:"channel,omitempty" example:"image-processing-request-sub"`
+	ImageID    string    `json:"image_id,omitempty" example:"img-123"`
+	OccurredAt time.Time `json:"occurred_at" example:"2024-01-01T12:00:00Z"`
+	RecordedAt time.Time `json:"recorded_at" example:"2024-01-01T12:00:01Z"`
+}
+
+func NewEventLogEntryResponse(e *model.EventLogEntry) *EventLogEntryResponse {
+	return &EventLogEntryResponse{
+		EventID:    e.EventID,
+		EventType:  e.EventType,
+		Direction:  string(e.Direction),
+		Channel:    e.Channel,
+		ImageID:    e.ImageID,
+		OccurredAt: e.OccurredAt,
+		RecordedAt: e.RecordedAt,
+	}
+}
+
+type ReplayItemResponse struct {
+	EventID    string    `json:"event_id" example:"evt-456"`
+	EventType  string    `json:"event_type" example:"image.process.request.v1"`
+	ImageID    string    `json:"image_id,omitempty" example:"img-123"`
+	OccurredAt time.Time `json:"occurred_at" example:"2024-01-01T12:00:00Z"`
+	Status     string    `json:"status" example:"would_replay"`
+	Error      string    `json:"error,omitempty" example:"image entity not found"`
+}
+
+type ReplayResultResponse struct {
+	DryRun   bool                 `json:"dry_run" example:"true"`
+	Matched  int                  `json:"matched" example:"3"`
+	Replayed int                  `json:"replayed" example:"0"`
+	Failed   int                  `json:"failed" example:"0"`
+	Skipped  int                  `json:"skipped" example:"1"`
+	Items    []ReplayItemResponse `json:"items"`
+}
+
+func NewReplayResultResponse(r *port.ReplayResult) *ReplayResultResponse {
+	resp := &ReplayResultResponse{
+		DryRun:   r.DryRun,
+		Matched:  r.Matched,
+		Replayed: r.Replayed,
+		Failed:   r.Failed,
+		Skipped:  r.Skipped,
+		Items:    make([]ReplayItemResponse, len(r.Items)),
+	}
+	for i, item := range r.Items {
+		resp.Items[i] = ReplayItemResponse{
+			EventID:    item.EventID,
+			EventType:  item.EventType,
+			ImageID:    item.ImageID,
+			OccurredAt: item.OccurredAt,
+			Status:     string(item.Status),
+			Error:      item.Error,
+		}
+	}
+	return resp
+}
+
+// Swagger docs
+type EventLogListResponseDoc struct {
+	Data []EventLogEntryResponse `json:"data"`
+}
+
+type ReplayResultDataResponse struct {
+	Data ReplayResultResponse `json:"data"`
+}
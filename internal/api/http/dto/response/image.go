@@ -5,6 +5,7 @@ import (
 
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
 	"github.com/histopathai/main-service/internal/shared/query"
 )
 
@@ -28,6 +29,7 @@ func newMagnificationResponse(mag *vobj.OpticalMagnification) *MagnificationResp
 type ProcessingInfoResponse struct {
 	Status          string     `json:"status" example:"processed"`
 	Version         string     `json:"version" example:"v1"`
+	Profile         string     `json:"profile,omitempty" example:"pyramid"`
 	FailureReason   *string    `json:"failure_reason,omitempty"`
 	RetryCount      int        `json:"retry_count" example:"0"`
 	LastProcessedAt *time.Time `json:"last_processed_at,omitempty" example:"2024-01-01T12:00:00Z"`
@@ -42,6 +44,7 @@ func newProcessingInfoResponse(pi *vobj.ProcessingInfo) ProcessingInfoResponse {
 	return ProcessingInfoResponse{
 		Status:          pi.Status.String(),
 		Version:         pi.Version.String(),
+		Profile:         pi.Profile.String(),
 		FailureReason:   pi.FailureReason,
 		RetryCount:      pi.RetryCount,
 		LastProcessedAt: lastProcessedAt,
@@ -64,8 +67,13 @@ type ImageResponse struct {
 	// Magnification
 	Magnification *MagnificationResponse `json:"magnification,omitempty"`
 
+	// Associated images served under /proxy/{id}/, e.g. label.jpg
+	AssociatedImages []string `json:"associated_images,omitempty" example:"label,macro"`
+
 	// Processing
 	Status string `json:"status" example:"processed"`
+	// 1-based position in the processing queue; only on single image reads of queued images
+	QueuePosition *int `json:"queue_position,omitempty" example:"3"`
 	// Timestamps
 	CreatedAt time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2024-01-02T12:00:00Z"`
@@ -86,9 +94,22 @@ func NewImageResponse(img *model.Image) *ImageResponse {
 		Status:        img.Processing.Status.String(),
 		CreatedAt:     img.CreatedAt,
 		UpdatedAt:     img.UpdatedAt,
+
+		AssociatedImages: associatedImages(img),
 	}
 }
 
+func associatedImages(img *model.Image) []string {
+	var names []string
+	if img.LabelContentID != nil {
+		names = append(names, "label")
+	}
+	if img.MacroContentID != nil {
+		names = append(names, "macro")
+	}
+	return names
+}
+
 func NewImageListResponse(result *query.Result[*model.Image]) *ListResponse[ImageResponse] {
 	data := make([]ImageResponse, len(result.Data))
 	for i, img := range result.Data {
@@ -117,6 +138,43 @@ type UploadImageResponse struct {
 	Data UploadImagePayload `json:"data"`
 }
 
+type ExecutionCancellationResponse struct {
+	Backend   string `json:"backend,omitempty" example:"cloudrun"`
+	Name      string `json:"name,omitempty" example:"projects/p/locations/r/jobs/tiler-small/executions/tiler-small-abc12"`
+	Cancelled bool   `json:"cancelled" example:"true"`
+	Error     string `json:"error,omitempty" example:"backend does not support cancellation"`
+}
+
+type ProcessingActionResponse struct {
+	ImageID           string                          `json:"image_id" example:"img-123"`
+	Status            string                          `json:"status" example:"processing"`
+	ActiveEventID     string                          `json:"active_event_id" example:"evt-789"`
+	RemovedContentIDs []string                        `json:"removed_content_ids"`
+	Executions        []ExecutionCancellationResponse `json:"executions"`
+}
+
+func NewProcessingActionResponse(r *port.ProcessingActionResult) *ProcessingActionResponse {
+	resp := &ProcessingActionResponse{
+		ImageID:           r.ImageID,
+		Status:            r.Status.String(),
+		ActiveEventID:     r.ActiveEventID,
+		RemovedContentIDs: r.RemovedContentIDs,
+		Executions:        make([]ExecutionCancellationResponse, len(r.Executions)),
+	}
+	if resp.RemovedContentIDs == nil {
+		resp.RemovedContentIDs = []string{}
+	}
+	for i, e := range r.Executions {
+		resp.Executions[i] = ExecutionCancellationResponse{
+			Backend:   e.Backend,
+			Name:      e.Name,
+			Cancelled: e.Cancelled,
+			Error:     e.Error,
+		}
+	}
+	return resp
+}
+
 // Swagger docs
 type ImageDataResponse struct {
 	Data ImageResponse `json:"data"`
@@ -126,3 +184,7 @@ type ImageListResponseDoc struct {
 	Data       []ImageResponse     `json:"data"`
 	Pagination *PaginationResponse `json:"pagination,omitempty"`
 }
+
+type ProcessingActionDataResponse struct {
+	Data ProcessingActionResponse `json:"data"`
+}
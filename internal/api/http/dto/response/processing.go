@@ -0,0 +1,139 @@
+package response
+
+import (
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+)
+
+type ProcessingAttemptResponse struct {
+	JobID         string           `json:"job_id" example:"job-123"`
+	EventID       string           `json:"event_id" example:"evt-789"`
+	Attempt       int              `json:"attempt" example:"1"`
+	Version       string           `json:"version" example:"v2"`
+	Profile       string           `json:"profile,omitempty" example:"pyramid"`
+	Tier          string           `json:"tier,omitempty" example:"large"`
+	Backend       string           `json:"backend,omitempty" example:"cloud-run-jobs"`
+	Outcome       string           `json:"outcome" example:"succeeded"`
+	FailureReason string           `json:"failure_reason,omitempty"`
+	OutputSizes   map[string]int64 `json:"output_sizes,omitempty"`
+
+	QueuedAt         *time.Time `json:"queued_at,omitempty" example:"2024-01-01T12:00:00Z"`
+	StartedAt        time.Time  `json:"started_at" example:"2024-01-01T12:00:05Z"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty" example:"2024-01-01T12:03:05Z"`
+	QueueWaitSeconds *float64   `json:"queue_wait_seconds,omitempty" example:"5"`
+	DurationSeconds  *float64   `json:"duration_seconds,omitempty" example:"180"`
+}
+
+func NewProcessingAttemptResponse(j *model.ProcessingJob) *ProcessingAttemptResponse {
+	resp := &ProcessingAttemptResponse{
+		JobID:         j.ID,
+		EventID:       j.EventID,
+		Attempt:       j.Attempt,
+		Version:       j.ProcessingVersion.String(),
+		Profile:       j.ProcessingProfile.String(),
+		Tier:          j.Tier,
+		Backend:       j.Backend,
+		Outcome:       string(j.Status),
+		FailureReason: j.FailureReason,
+		QueuedAt:      j.QueuedAt,
+		StartedAt:     j.StartedAt,
+		FinishedAt:    j.FinishedAt,
+	}
+	if len(j.OutputSizes) > 0 {
+		resp.OutputSizes = make(map[string]int64, len(j.OutputSizes))
+		for output, size := range j.OutputSizes {
+			resp.OutputSizes[string(output)] = size
+		}
+	}
+	if wait, ok := j.QueueWait(); ok {
+		seconds := wait.Seconds()
+		resp.QueueWaitSeconds = &seconds
+	}
+	if duration, ok := j.Duration(); ok {
+		seconds := duration.Seconds()
+		resp.DurationSeconds = &seconds
+	}
+	return resp
+}
+
+type DurationStatsResponse struct {
+	Count       int     `json:"count" example:"42"`
+	MeanSeconds float64 `json:"mean_seconds" example:"95.5"`
+	P50Seconds  float64 `json:"p50_seconds" example:"80"`
+	P95Seconds  float64 `json:"p95_seconds" example:"240"`
+	MaxSeconds  float64 `json:"max_seconds" example:"600"`
+}
+
+func newDurationStatsResponse(s port.DurationStats) DurationStatsResponse {
+	return DurationStatsResponse{
+		Count:       s.Count,
+		MeanSeconds: s.Mean.Seconds(),
+		P50Seconds:  s.P50.Seconds(),
+		P95Seconds:  s.P95.Seconds(),
+		MaxSeconds:  s.Max.Seconds(),
+	}
+}
+
+type ProcessingStatsGroupResponse struct {
+	Key         string                `json:"key,omitempty" example:"ws-123"`
+	Attempts    int                   `json:"attempts" example:"50"`
+	Succeeded   int                   `json:"succeeded" example:"45"`
+	Failed      int                   `json:"failed" example:"3"`
+	Cancelled   int                   `json:"cancelled" example:"1"`
+	Running     int                   `json:"running" example:"1"`
+	QueueWait   DurationStatsResponse `json:"queue_wait"`
+	Duration    DurationStatsResponse `json:"duration"`
+	OutputBytes int64                 `json:"output_bytes" example:"1073741824"`
+}
+
+func newProcessingStatsGroupResponse(g port.ProcessingStatsGroup) ProcessingStatsGroupResponse {
+	return ProcessingStatsGroupResponse{
+		Key:         g.Key,
+		Attempts:    g.Attempts,
+		Succeeded:   g.Succeeded,
+		Failed:      g.Failed,
+		Cancelled:   g.Cancelled,
+		Running:     g.Running,
+		QueueWait:   newDurationStatsResponse(g.QueueWait),
+		Duration:    newDurationStatsResponse(g.Duration),
+		OutputBytes: g.OutputBytes,
+	}
+}
+
+type ProcessingStatsResponse struct {
+	From        time.Time                      `json:"from" example:"2024-01-01T00:00:00Z"`
+	To          time.Time                      `json:"to" example:"2024-01-08T00:00:00Z"`
+	Truncated   bool                           `json:"truncated" example:"false"`
+	Total       ProcessingStatsGroupResponse   `json:"total"`
+	ByWorkspace []ProcessingStatsGroupResponse `json:"by_workspace"`
+	ByFormat    []ProcessingStatsGroupResponse `json:"by_format"`
+}
+
+func NewProcessingStatsResponse(s *port.ProcessingStats) *ProcessingStatsResponse {
+	resp := &ProcessingStatsResponse{
+		From:        s.From,
+		To:          s.To,
+		Truncated:   s.Truncated,
+		Total:       newProcessingStatsGroupResponse(s.Total),
+		ByWorkspace: make([]ProcessingStatsGroupResponse, len(s.ByWorkspace)),
+		ByFormat:    make([]ProcessingStatsGroupResponse, len(s.ByFormat)),
+	}
+	for i, g := range s.ByWorkspace {
+		resp.ByWorkspace[i] = newProcessingStatsGroupResponse(g)
+	}
+	for i, g := range s.ByFormat {
+		resp.ByFormat[i] = newProcessingStatsGroupResponse(g)
+	}
+	return resp
+}
+
+// Swagger docs
+type ProcessingHistoryListResponseDoc struct {
+	Data []ProcessingAttemptResponse `json:"data"`
+}
+
+type ProcessingStatsDataResponse struct {
+	Data ProcessingStatsResponse `json:"data"`
+}
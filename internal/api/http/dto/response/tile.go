@@ -0,0 +1,71 @@
+package response
+
+import (
+	"time"
+
+	"github.com/histopathai/main-service/internal/port/cache"
+)
+
+type CacheStatsResponse struct {
+	Hits      int64   `json:"hits" example:"9500"`
+	Misses    int64   `json:"misses" example:"500"`
+	HitRatio  float64 `json:"hit_ratio" example:"0.95"`
+	Entries   int64   `json:"entries" example:"1200"`
+	Evictions int64   `json:"evictions" example:"40"`
+	Bytes     int64   `json:"bytes,omitempty" example:"268435456"`
+}
+
+func NewCacheStatsResponse(s *cache.Stats) *CacheStatsResponse {
+	if s == nil {
+		return nil
+	}
+	resp := &CacheStatsResponse{
+		Hits:      s.Hits,
+		Misses:    s.Misses,
+		Entries:   s.Size,
+		Evictions: s.Evictions,
+		Bytes:     s.Bytes,
+	}
+	if total := s.Hits + s.Misses; total > 0 {
+		resp.HitRatio = float64(s.Hits) / float64(total)
+	}
+	return resp
+}
+
+type TileCacheStatsResponse struct {
+	// Image, content and index map metadata
+	Metadata *CacheStatsResponse `json:"metadata"`
+	// Tile payloads; absent when tile caching is disabled
+	Tiles *CacheStatsResponse `json:"tiles,omitempty"`
+}
+
+// SignedTileURLResponse grants access to one image's tiles without
+// credentials until ExpiresAt.
+type SignedTileURLResponse struct {
+	// Path segment to place after /api/v1/signed/
+	Token     string    `json:"token" example:"1767225600.q2Xf..."`
+	ExpiresAt time.Time `json:"expires_at" example:"2026-01-01T00:00:00Z"`
+	// Deep Zoom descriptor; tiles resolve relative to it
+	DZIURL string `json:"dzi_url" example:"https://api.example.com/api/v1/signed/1767225600.q2Xf.../proxy/img-123/image.dzi"`
+	// IIIF image service base URI
+	IIIFURL string `json:"iiif_url" example:"https://api.example.com/api/v1/signed/1767225600.q2Xf.../iiif/img-123"`
+}
+
+// TilePrefetchResponse reports how many tiles were queued for loading.
+type TilePrefetchResponse struct {
+	// Zero when the queue is full or tile payloads are not cached
+	Scheduled int `json:"scheduled" example:"24"`
+}
+
+// Swagger docs
+type TileCacheStatsDataResponse struct {
+	Data TileCacheStatsResponse `json:"data"`
+}
+
+type SignedTileURLDataResponse struct {
+	Data SignedTileURLResponse `json:"data"`
+}
+
+type TilePrefetchDataResponse struct {
+	Data TilePrefetchResponse `json:"data"`
+}
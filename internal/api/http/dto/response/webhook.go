@@ -0,0 +1,92 @@
+package response
+
+import (
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+)
+
+type WebhookResponse struct {
+	ID         string    `json:"id" example:"wh-123"`
+	WsID       string    `json:"ws_id" example:"ws-123"`
+	CreatorID  string    `json:"creator_id" example:"user-123"`
+	URL        string    `json:"url" example:"https://lab.example.org/hooks/histopath"`
+	EventTypes []string  `json:"event_types,omitempty" example:"image.processed.v1"`
+	Active     bool      `json:"active" example:"true"`
+	Secret     string    `json:"secret,omitempty" example:"whsec_2f1c..."` // Only returned on creation
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2024-01-02T12:00:00Z"`
+}
+
+func NewWebhookResponse(s *model.WebhookSubscription) *WebhookResponse {
+	return &WebhookResponse{
+		ID:         s.ID,
+		WsID:       s.WsID,
+		CreatorID:  s.CreatorID,
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		Active:     s.Active,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+// NewCreatedWebhookResponse includes the signing secret, which is not
+// returned by any later read.
+func NewCreatedWebhookResponse(s *model.WebhookSubscription) *WebhookResponse {
+	resp := NewWebhookResponse(s)
+	resp.Secret = s.Secret
+	return resp
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string     `json:"id" example:"wh-123_evt-456"`
+	SubscriptionID string     `json:"subscription_id" example:"wh-123"`
+	EventID        string     `json:"event_id" example:"evt-456"`
+	EventType      string     `json:"event_type" example:"image.processed.v1"`
+	Status         string     `json:"status" example:"succeeded"`
+	Attempts       int        `json:"attempts" example:"1"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" example:"2024-01-01T12:00:10Z"`
+	LastStatusCode int        `json:"last_status_code,omitempty" example:"200"`
+	LastError      string     `json:"last_error,omitempty" example:"endpoint returned 503"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" example:"2024-01-01T12:00:01Z"`
+	RedeliveryOf   string     `json:"redelivery_of,omitempty" example:"wh-123_evt-456"`
+	CreatedAt      time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`
+}
+
+func NewWebhookDeliveryResponse(d *model.WebhookDelivery) *WebhookDeliveryResponse {
+	resp := &WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == model.WebhookDeliveryPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	return resp
+}
+
+// Swagger docs
+type WebhookDataResponse struct {
+	Data WebhookResponse `json:"data"`
+}
+
+type WebhookListResponseDoc struct {
+	Data []WebhookResponse `json:"data"`
+}
+
+type WebhookDeliveryDataResponse struct {
+	Data WebhookDeliveryResponse `json:"data"`
+}
+
+type WebhookDeliveryListResponseDoc struct {
+	Data []WebhookDeliveryResponse `json:"data"`
+}
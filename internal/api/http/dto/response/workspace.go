@@ -21,6 +21,8 @@ type WorkspaceResponse struct {
 	ResourceURL     *string            `json:"resource_url,omitempty" example:"https://example.com"`
 	ReleaseYear     *int               `json:"release_year,omitempty" example:"2023"`
 	AnnotationTypes []string           `json:"annotation_types,omitempty"`
+	Members         []string           `json:"members,omitempty"`
+	DeIdentified    bool               `json:"de_identified" example:"false"`
 	CreatedAt       time.Time          `json:"created_at" example:"2024-01-01T12:00:00Z"`
 	UpdatedAt       time.Time          `json:"updated_at" example:"2024-01-02T12:00:00Z"`
 }
@@ -39,6 +41,8 @@ func NewWorkspaceResponse(ws *model.Workspace) *WorkspaceResponse {
 		ResourceURL:     ws.ResourceURL,
 		ReleaseYear:     ws.ReleaseYear,
 		AnnotationTypes: ws.AnnotationTypes,
+		Members:         ws.Members,
+		DeIdentified:    ws.DeIdentified,
 		CreatedAt:       ws.CreatedAt,
 		UpdatedAt:       ws.UpdatedAt,
 	}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/dto/request"
+	"github.com/histopathai/main-service/internal/api/http/dto/response"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
+	"github.com/histopathai/main-service/internal/application/command"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+type EventReplayHandler struct {
+	helper.BaseHandler
+	eventLogQuery port.EventLogQuery
+	replayUseCase port.EventReplayUseCase
+}
+
+func NewEventReplayHandler(query port.EventLogQuery, useCase port.EventReplayUseCase, logger *slog.Logger) *EventReplayHandler {
+	return &EventReplayHandler{
+		eventLogQuery: query,
+		replayUseCase: useCase,
+		BaseHandler:   helper.NewBaseHandler(logger),
+	}
+}
+
+// List godoc
+// @Summary List logged events
+// @Description Published and consumed events, oldest first. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param event_types query string false "Comma separated event types"
+// @Param image_id query string false "Image ID"
+// @Param from query string false "RFC3339 start (inclusive)"
+// @Param to query string false "RFC3339 end (exclusive)"
+// @Param limit query int false "Maximum number of entries" default(100) minimum(1) maximum(1000)
+// @Success 200 {object} response.EventLogListResponseDoc
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/events [get]
+func (h *EventReplayHandler) List(c *gin.Context) {
+	filter := port.EventLogFilter{
+		ImageID: c.Query("image_id"),
+		Limit:   command.DefaultReplayLimit,
+	}
+	details := map[string]interface{}{}
+
+	if raw := c.Query("event_types"); raw != "" {
+		filter.EventTypes = strings.Split(raw, ",")
+	}
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			details["from"] = "from must be an RFC3339 timestamp"
+		}
+		filter.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			details["to"] = "to must be an RFC3339 timestamp"
+		}
+		filter.To = &to
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > command.MaxReplayLimit {
+			details["limit"] = "limit must be between 1 and 1000"
+		}
+		filter.Limit = limit
+	}
+	if len(details) > 0 {
+		h.HandleError(c, errors.NewValidationError("invalid query parameters", details))
+		return
+	}
+
+	entries, err := h.eventLogQuery.List(c.Request.Context(), filter)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	entryResponses := make([]response.EventLogEntryResponse, len(entries))
+	for i, e := range entries {
+		entryResponses[i] = *response.NewEventLogEntryResponse(e)
+	}
+
+	h.Response.SuccessList(c, entryResponses, nil)
+}
+
+// Replay godoc
+// @Summary Replay logged events through their handlers
+// @Description Selected events are re-run with duplicate checks bypassed. Runs as a dry run
+// @Description unless dry_run is explicitly false. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body request.ReplayEventsRequest true "Replay selection"
+// @Success 200 {object} response.ReplayResultDataResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/events/replay [post]
+func (h *EventReplayHandler) Replay(c *gin.Context) {
+	var req request.ReplayEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.HandleError(c, errors.NewValidationError("invalid request payload", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	cmd := command.ReplayEventsCommand{
+		EventTypes: req.EventTypes,
+		ImageID:    req.ImageID,
+		From:       req.From,
+		To:         req.To,
+		Limit:      req.Limit,
+		DryRun:     req.DryRun == nil || *req.DryRun,
+	}
+
+	result, err := h.replayUseCase.Replay(c.Request.Context(), cmd)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	h.Response.Success(c, http.StatusOK, response.NewReplayResultResponse(result))
+}
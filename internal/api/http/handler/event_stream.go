@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// Reconnect delay suggested to EventSource clients, in milliseconds
+const sseRetryMs = 3000
+
+type EventStreamHandler struct {
+	helper.BaseHandler
+	logger         *slog.Logger
+	workspaceQuery port.WorkspaceQuery
+	streamQuery    port.EventStreamQuery
+	marshaler      portevent.EventMarshaler
+	keepAlive      time.Duration
+}
+
+func NewEventStreamHandler(
+	workspaceQuery port.WorkspaceQuery,
+	streamQuery port.EventStreamQuery,
+	marshaler portevent.EventMarshaler,
+	keepAlive time.Duration,
+	logger *slog.Logger,
+) *EventStreamHandler {
+	return &EventStreamHandler{
+		BaseHandler:    helper.NewBaseHandler(logger),
+		logger:         logger,
+		workspaceQuery: workspaceQuery,
+		streamQuery:    streamQuery,
+		marshaler:      marshaler,
+		keepAlive:      keepAlive,
+	}
+}
+
+// Stream godoc
+// @Summary Stream live workspace events
+// @Description Server-sent events for image processing status transitions and annotation
+// @Description create/update/delete in the workspace. Each event's data is a CloudEvents JSON
+// @Description document; send Last-Event-ID (header or query) to resume after a disconnect.
+// @Tags Workspaces
+// @Produce text/event-stream
+// @Param id path string true "Workspace ID"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "Alternative to the Last-Event-ID header"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id}/events [get]
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	wsID := c.Param("id")
+
+	userID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	workspace, err := h.workspaceQuery.Get(c.Request.Context(), wsID)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+	if workspace.IsDeleted() {
+		h.HandleError(c, errors.NewNotFoundError("workspace not found"))
+		return
+	}
+	// Same rule as image access: events carry the workspace's data
+	if !admin && !workspace.HasMember(userID) {
+		h.HandleError(c, errors.NewForbiddenError("no access to the workspace"))
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	ctx := c.Request.Context()
+	stream, err := h.streamQuery.Stream(ctx, wsID, lastEventID)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	// The server-wide write timeout would cut the stream
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetryMs)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-stream.Events():
+			if !ok {
+				if err := stream.Err(); err != nil {
+					h.logger.Error("Event stream ended",
+						slog.String("ws_id", wsID),
+						slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			data, err := h.marshaler.Marshal(event)
+			if err != nil {
+				h.logger.Error("Failed to marshal streamed event",
+					slog.String("event_id", event.GetEventID()),
+					slog.String("error", err.Error()))
+				continue
+			}
+
+			fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.GetEventID(), event.GetEventType(), data)
+			c.Writer.Flush()
+
+		case <-keepAlive.C:
+			// Comment line: keeps proxies from closing an idle connection
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+			c.Writer.Flush()
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
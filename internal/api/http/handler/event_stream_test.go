@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStreamWorkspaceQuery struct {
+	port.WorkspaceQuery
+}
+
+func (fakeStreamWorkspaceQuery) Get(ctx context.Context, id string) (*model.Workspace, error) {
+	return &model.Workspace{
+		Entity:  vobj.Entity{ID: id, EntityType: vobj.EntityTypeWorkspace, CreatorID: "user-1"},
+		Members: []string{"user-2"},
+	}, nil
+}
+
+type fakeStreamQuery struct {
+	port.EventStreamQuery
+	opened bool
+}
+
+func (q *fakeStreamQuery) Stream(ctx context.Context, wsID, lastEventID string) (port.EventStream, error) {
+	q.opened = true
+	return nil, context.Canceled
+}
+
+func TestEventStreamHandler_RejectsNonMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	streamQuery := &fakeStreamQuery{}
+	h := NewEventStreamHandler(fakeStreamWorkspaceQuery{}, streamQuery, nil, time.Second,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/workspaces/ws-1/events", nil)
+	c.Params = gin.Params{{Key: "id", Value: "ws-1"}}
+	c.Set("authenticated_user_id", "user-3")
+	c.Set("user_role", "user")
+	c.Set("request_id", "req-1")
+
+	h.Stream(c)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, streamQuery.opened)
+}
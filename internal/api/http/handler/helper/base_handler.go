@@ -33,12 +33,17 @@ func (bh *BaseHandler) HandleError(c *gin.Context, err error) {
 	if stderr.As(err, &customErr) {
 		statusCode, errResponse := bh.mapCustomError(customErr)
 
-		bh.logger.Error("Request failed",
+		attrs := []any{
 			slog.String("request_id", requestID.(string)),
 			slog.String("error_type", string(customErr.Type)),
 			slog.String("message", customErr.Message),
 			slog.String("path", c.Request.URL.Path),
-		)
+		}
+		// The cause stays in the logs, never in the response
+		if customErr.Err != nil {
+			attrs = append(attrs, slog.String("cause", customErr.Err.Error()))
+		}
+		bh.logger.Error("Request failed", attrs...)
 		c.JSON(statusCode, errResponse)
 		return
 	}
@@ -57,6 +62,7 @@ func (bh *BaseHandler) HandleError(c *gin.Context, err error) {
 func (bh *BaseHandler) mapCustomError(err *errors.Err) (int, response.ErrorResponse) {
 	statusMap := map[errors.ErrorType]int{
 		errors.ErrorTypeValidation:   http.StatusBadRequest,
+		errors.ErrorTypeBadRequest:   http.StatusBadRequest,
 		errors.ErrorTypeNotFound:     http.StatusNotFound,
 		errors.ErrorTypeConflict:     http.StatusConflict,
 		errors.ErrorTypeUnauthorized: http.StatusUnauthorized,
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
+	"github.com/histopathai/main-service/internal/application/proxy"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+type IIIFHandler struct {
+	helper.BaseHandler
+	tileServer *proxy.TileServer
+}
+
+func NewIIIFHandler(tileServer *proxy.TileServer, logger *slog.Logger) *IIIFHandler {
+	return &IIIFHandler{
+		tileServer:  tileServer,
+		BaseHandler: helper.NewBaseHandler(logger),
+	}
+}
+
+// Base redirects the image service base URI to its info.json
+// @Summary      IIIF image service
+// @Description  Redirects to the image information document
+// @Tags         Tiles
+// @Param        imageId path string true "Image ID"
+// @Success      303 "Redirect to info.json"
+// @Router       /iiif/{imageId} [get]
+func (h *IIIFHandler) Base(c *gin.Context) {
+	c.Redirect(http.StatusSeeOther, strings.TrimSuffix(c.Request.URL.Path, "/")+"/info.json")
+}
+
+// Info godoc
+// @Summary      IIIF image information
+// @Description  IIIF Image API 3.0 info.json (level 0) describing the image's tile pyramid
+// @Tags         Tiles
+// @Produce      json
+// @Param        imageId path string true "Image ID"
+// @Success      200 {object} proxy.IIIFInfo
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /iiif/{imageId}/info.json [get]
+func (h *IIIFHandler) Info(c *gin.Context) {
+	info, err := h.tileServer.IIIFInfo(c.Request.Context(), c.Param("imageId"), h.serviceID(c))
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	body, err := json.Marshal(info)
+	if err != nil {
+		h.HandleError(c, errors.NewInternalError("failed to encode info.json", err))
+		return
+	}
+
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Header("Cache-Control", cacheControl(proxy.RequestTypeDZI))
+	c.Data(http.StatusOK, `application/ld+json;profile="`+proxy.IIIFContext+`"`, body)
+}
+
+// Image godoc
+// @Summary      IIIF image request
+// @Description  Serves tile aligned IIIF Image API 3.0 requests from the image's DZI pyramid.
+// @Description  Rotation must be 0, quality default or color and format the pyramid's tile format.
+// @Tags         Tiles
+// @Produce      jpeg
+// @Produce      png
+// @Param        imageId path string true "Image ID"
+// @Param        region path string true "full or x,y,w,h"
+// @Param        size path string true "max, w,h, w, or ,h"
+// @Param        rotation path string true "0"
+// @Param        quality path string true "Quality and format, e.g. default.jpg"
+// @Success      200 {file} binary "The requested tile"
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /iiif/{imageId}/{region}/{size}/{rotation}/{quality} [get]
+func (h *IIIFHandler) Image(c *gin.Context) {
+	quality, format, ok := strings.Cut(c.Param("quality"), ".")
+	if !ok {
+		h.HandleError(c, errors.NewBadRequestError("quality must include a format, e.g. default.jpg", nil))
+		return
+	}
+
+	reader, mediaType, err := h.tileServer.ServeIIIF(
+		c.Request.Context(),
+		c.Param("imageId"),
+		c.Param("region"),
+		c.Param("size"),
+		c.Param("rotation"),
+		quality,
+		format,
+	)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Content-Type", mediaType)
+	c.Header("Cache-Control", cacheControl(proxy.RequestTypeTile))
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, reader)
+}
+
+// serviceID is the image service base URI as the client reached it.
+func (h *IIIFHandler) serviceID(c *gin.Context) string {
+	return requestOrigin(c) + strings.TrimSuffix(c.Request.URL.Path, "/info.json")
+}
+
+// requestOrigin is the scheme and host the client reached the service at.
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := c.GetHeader("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	return scheme + "://" + c.Request.Host
+}
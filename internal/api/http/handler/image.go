@@ -120,7 +120,7 @@ func (ih *ImageHandler) UploadImage(c *gin.Context) {
 
 // Get godoc
 // @Summary Get image by ID
-// @Description Retrieve image details by its ID
+// @Description Retrieve image details by its ID. Queued images include their processing queue position.
 // @Tags Images
 // @Accept json
 // @Produce json
@@ -140,6 +140,17 @@ func (ih *ImageHandler) Get(c *gin.Context) {
 	}
 
 	imageResp := response.NewImageResponse(image)
+	if image.Processing != nil && image.Processing.Status == vobj.StatusQueued {
+		position, err := ih.IQuery.QueuePosition(c.Request.Context(), image.ID)
+		switch {
+		case err == nil:
+			imageResp.QueuePosition = &position
+		case !errors.IsNotFound(err):
+			ih.HandleError(c, err)
+			return
+		}
+		// Not found: the request has not reached the queue yet
+	}
 	ih.Response.Success(c, http.StatusOK, imageResp)
 }
 
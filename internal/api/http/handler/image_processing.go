@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/dto/request"
+	"github.com/histopathai/main-service/internal/api/http/dto/response"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
+	"github.com/histopathai/main-service/internal/application/command"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+type ImageProcessingHandler struct {
+	helper.BaseHandler
+	useCase port.ImageProcessingUseCase
+	query   port.ProcessingQuery
+}
+
+func NewImageProcessingHandler(useCase port.ImageProcessingUseCase, query port.ProcessingQuery, logger *slog.Logger) *ImageProcessingHandler {
+	return &ImageProcessingHandler{
+		useCase:     useCase,
+		query:       query,
+		BaseHandler: helper.NewBaseHandler(logger),
+	}
+}
+
+// Reprocess godoc
+// @Summary Reprocess an image
+// @Description Queues a new processing attempt from the origin content. Derived contents of the
+// @Description previous attempt are removed and its running executions cancelled where supported.
+// @Tags Images
+// @Accept json
+// @Produce json
+// @Param id path string true "Image ID"
+// @Param request body request.ReprocessImageRequest false "Processing options"
+// @Success 202 {object} response.ProcessingActionDataResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /images/{id}/reprocess [post]
+func (h *ImageProcessingHandler) Reprocess(c *gin.Context) {
+	var req request.ReprocessImageRequest
+	// The body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.HandleError(c, errors.NewValidationError("invalid request payload", map[string]interface{}{
+				"error": err.Error(),
+			}))
+			return
+		}
+	}
+
+	cmd := command.ReprocessImageCommand{
+		ID:                c.Param("id"),
+		ProcessingVersion: req.ProcessingVersion,
+		Priority:          req.Priority,
+	}
+
+	errDetails, ok := cmd.Validate()
+	if !ok {
+		h.HandleError(c, errors.NewValidationError("invalid command payload", errDetails))
+		return
+	}
+
+	result, err := h.useCase.Reprocess(c.Request.Context(), cmd)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	h.Response.Success(c, http.StatusAccepted, response.NewProcessingActionResponse(result))
+}
+
+// CancelProcessing godoc
+// @Summary Cancel image processing
+// @Description Stops processing or pending retries of an image. Derived contents are removed and
+// @Description running executions cancelled where supported.
+// @Tags Images
+// @Produce json
+// @Param id path string true "Image ID"
+// @Success 200 {object} response.ProcessingActionDataResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /images/{id}/cancel-processing [post]
+func (h *ImageProcessingHandler) CancelProcessing(c *gin.Context) {
+	cmd := command.CancelProcessingCommand{ID: c.Param("id")}
+
+	errDetails, ok := cmd.Validate()
+	if !ok {
+		h.HandleError(c, errors.NewValidationError("invalid command payload", errDetails))
+		return
+	}
+
+	result, err := h.useCase.CancelProcessing(c.Request.Context(), cmd)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	h.Response.Success(c, http.StatusOK, response.NewProcessingActionResponse(result))
+}
+
+// History godoc
+// @Summary Get image processing history
+// @Description Processing attempts of an image, newest first, with their timings, worker tier,
+// @Description outcome and output sizes.
+// @Tags Images
+// @Produce json
+// @Param id path string true "Image ID"
+// @Param limit query int false "Maximum number of attempts" default(50) minimum(1) maximum(200)
+// @Success 200 {object} response.ProcessingHistoryListResponseDoc
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /images/{id}/processing-history [get]
+func (h *ImageProcessingHandler) History(c *gin.Context) {
+	limit := defaultHistoryLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxHistoryLimit {
+			h.HandleError(c, errors.NewValidationError("invalid query parameters", map[string]interface{}{
+				"limit": "limit must be between 1 and 200",
+			}))
+			return
+		}
+		limit = parsed
+	}
+
+	jobs, err := h.query.History(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	attempts := make([]response.ProcessingAttemptResponse, len(jobs))
+	for i, j := range jobs {
+		attempts[i] = *response.NewProcessingAttemptResponse(j)
+	}
+
+	h.Response.SuccessList(c, attempts, nil)
+}
+
+// Stats godoc
+// @Summary Get processing statistics
+// @Description Attempt counts, queue wait, duration and output size aggregated overall, per
+// @Description workspace and per format. Covers attempts started in the window, the last 7 days
+// @Description by default. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param ws_id query string false "Workspace ID"
+// @Param format query string false "Image format"
+// @Param from query string false "RFC3339 start (inclusive)"
+// @Param to query string false "RFC3339 end (exclusive)"
+// @Success 200 {object} response.ProcessingStatsDataResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/processing/stats [get]
+func (h *ImageProcessingHandler) Stats(c *gin.Context) {
+	filter := port.ProcessingJobFilter{
+		WsID:   c.Query("ws_id"),
+		Format: c.Query("format"),
+	}
+	details := map[string]interface{}{}
+
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			details["from"] = "from must be an RFC3339 timestamp"
+		}
+		filter.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			details["to"] = "to must be an RFC3339 timestamp"
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		details["from"] = "from must be before to"
+	}
+	if len(details) > 0 {
+		h.HandleError(c, errors.NewValidationError("invalid query parameters", details))
+		return
+	}
+
+	stats, err := h.query.Stats(c.Request.Context(), filter)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	h.Response.Success(c, http.StatusOK, response.NewProcessingStatsResponse(stats))
+}
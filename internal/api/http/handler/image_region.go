@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/dto/request"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
+	"github.com/histopathai/main-service/internal/application/proxy"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+type ImageRegionHandler struct {
+	helper.BaseHandler
+	tileServer *proxy.TileServer
+}
+
+func NewImageRegionHandler(tileServer *proxy.TileServer, logger *slog.Logger) *ImageRegionHandler {
+	return &ImageRegionHandler{
+		tileServer:  tileServer,
+		BaseHandler: helper.NewBaseHandler(logger),
+	}
+}
+
+// Region godoc
+// @Summary Render an image region
+// @Description Assembles a region from the image's tiles, cropped and downscaled to the output size.
+// @Description Give output_width and/or output_height, or a magnification below the scan
+// @Description magnification; the region is rendered at full resolution otherwise. Outputs above
+// @Description the configured pixel limit are rejected.
+// @Tags Images
+// @Produce jpeg
+// @Produce png
+// @Param id path string true "Image ID"
+// @Param x query int true "Left edge in full resolution pixels"
+// @Param y query int true "Top edge in full resolution pixels"
+// @Param width query int true "Width in full resolution pixels"
+// @Param height query int true "Height in full resolution pixels"
+// @Param output_width query int false "Output width"
+// @Param output_height query int false "Output height"
+// @Param magnification query number false "Target magnification, e.g. 10"
+// @Param format query string false "jpeg or png" default(jpeg)
+// @Param quality query int false "JPEG quality" default(90) minimum(1) maximum(100)
+// @Success 200 {file} binary "The rendered region"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /images/{id}/region [get]
+func (h *ImageRegionHandler) Region(c *gin.Context) {
+	var req request.ImageRegionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.HandleError(c, errors.NewValidationError("invalid query parameters", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	region, err := h.tileServer.RenderRegion(c.Request.Context(), c.Param("id"), proxy.RegionRequest{
+		X:             req.X,
+		Y:             req.Y,
+		Width:         req.Width,
+		Height:        req.Height,
+		OutputWidth:   req.OutputWidth,
+		OutputHeight:  req.OutputHeight,
+		Magnification: req.Magnification,
+		Format:        req.Format,
+		Quality:       req.Quality,
+	})
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	c.Header("X-Region-Width", strconv.Itoa(region.Width))
+	c.Header("X-Region-Height", strconv.Itoa(region.Height))
+	c.Data(http.StatusOK, region.MediaType, region.Data)
+}
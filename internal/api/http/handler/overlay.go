@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
+	"github.com/histopathai/main-service/internal/application/proxy"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+type OverlayHandler struct {
+	helper.BaseHandler
+	overlay *proxy.OverlayRenderer
+}
+
+func NewOverlayHandler(overlay *proxy.OverlayRenderer, logger *slog.Logger) *OverlayHandler {
+	return &OverlayHandler{
+		BaseHandler: helper.NewBaseHandler(logger),
+		overlay:     overlay,
+	}
+}
+
+// Tile godoc
+// @Summary      Annotation overlay tile
+// @Description  Renders the image's annotation polygons, in their annotation type's color, into a
+// @Description  transparent PNG tile on the image's DZI grid (same level, column, row and overlap), to
+// @Description  stack over the image tiles. The ETag changes whenever the image's annotations do.
+// @Tags         Tiles
+// @Produce      png
+// @Param        imageId path string true "Image ID"
+// @Param        level path int true "DZI level"
+// @Param        tile path string true "Column and row, e.g. 3_4.png"
+// @Param        types query string false "Comma separated annotation type IDs to draw; all when omitted"
+// @Param        If-None-Match header string false "ETag of a cached copy"
+// @Success      200 {file} binary "The overlay tile"
+// @Success      304 "Not modified"
+// @Failure      400 {object} response.ErrorResponse
+// @Failure      401 {object} response.ErrorResponse
+// @Failure      403 {object} response.ErrorResponse
+// @Failure      404 {object} response.ErrorResponse "Image not found or tile outside the image"
+// @Failure      500 {object} response.ErrorResponse
+// @Router       /overlay/{imageId}/{level}/{tile} [get]
+func (h *OverlayHandler) Tile(c *gin.Context) {
+	var level, col, row int
+	if _, err := fmt.Sscanf(c.Param("level")+"/"+c.Param("tile"), "%d/%d_%d.png", &level, &col, &row); err != nil {
+		h.HandleError(c, errors.NewBadRequestError("overlay tiles are addressed as {level}/{col}_{row}.png", nil))
+		return
+	}
+
+	var typeIDs []string
+	for _, id := range strings.Split(c.Query("types"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			typeIDs = append(typeIDs, id)
+		}
+	}
+
+	tile, err := h.overlay.RenderTile(c.Request.Context(), c.Param("imageId"), level, col, row, typeIDs)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	// Annotations change at any time; clients revalidate against the ETag
+	c.Header("ETag", tile.ETag)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Access-Control-Allow-Origin", "*")
+	if etagMatches(c.GetHeader("If-None-Match"), tile.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", tile.Data)
+}
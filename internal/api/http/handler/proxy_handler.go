@@ -1,37 +1,61 @@
 package handler
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/dto/request"
+	"github.com/histopathai/main-service/internal/api/http/dto/response"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
 	"github.com/histopathai/main-service/internal/application/proxy"
+	"github.com/histopathai/main-service/internal/shared/errors"
 )
 
 type TileProxyHandler struct {
+	helper.BaseHandler
 	tileServer *proxy.TileServer
+	access     *proxy.ImageAccess
+	prefetcher *proxy.Prefetcher
 	logger     *slog.Logger
 }
 
-func NewTileProxyHandler(tileServer *proxy.TileServer, logger *slog.Logger) *TileProxyHandler {
+func NewTileProxyHandler(tileServer *proxy.TileServer, access *proxy.ImageAccess, prefetcher *proxy.Prefetcher, logger *slog.Logger) *TileProxyHandler {
 	return &TileProxyHandler{
-		tileServer: tileServer,
-		logger:     logger.WithGroup("tile_proxy"),
+		BaseHandler: helper.NewBaseHandler(logger),
+		tileServer:  tileServer,
+		access:      access,
+		prefetcher:  prefetcher,
+		logger:      logger.WithGroup("tile_proxy"),
 	}
 }
 
 // ProxyTile handles tile proxy requests
 // @Summary      Proxy Tile Request
-// @Description  Proxies DZI, thumbnails, and tile requests
+// @Description  Proxies DZI, thumbnails, and tile requests. Responses carry a strong ETag that changes
+// @Description  when the image is reprocessed; If-None-Match is answered with 304 and single byte
+// @Description  ranges with 206. The caller needs access to the image's workspace; the same route under
+// @Description  /signed/{token} serves a signed URL without credentials. label.{ext} and macro.{ext}
+// @Description  serve the slide's associated images; de-identified workspaces get blank images instead.
 // @Tags         Tiles
 // @Produce      octet-stream
 // @Param        imageId path string true "Image UUID"
-// @Param        objectPath path string true "Object path (e.g., image.dzi, 0/0_0.jpeg)"
+// @Param        objectPath path string true "Object path (e.g., image.dzi, 0/0_0.jpeg, label.jpg)"
+// @Param        If-None-Match header string false "ETag of a cached copy"
+// @Param        Range header string false "Single byte range, e.g. bytes=0-1023"
 // @Success      200 {file} binary "The requested object"
+// @Success      206 {file} binary "The requested byte range"
+// @Success      304 "Not modified"
 // @Failure      400 {object} response.ErrorResponse "Invalid request"
-// @Failure      404 {object} response.ErrorResponse "Object not found"
+// @Failure      401 {object} response.ErrorResponse "Not authenticated or invalid signed URL"
+// @Failure      403 {object} response.ErrorResponse "No access to the image's workspace"
+// @Failure      404 {object} response.ErrorResponse "Image, content or tile not found"
+// @Failure      416 "Range not satisfiable"
 // @Failure      500 {object} response.ErrorResponse "Internal server error"
 // @Router       /proxy/{imageId}/{objectPath} [get]
 func (h *TileProxyHandler) ProxyTile(c *gin.Context) {
@@ -39,10 +63,7 @@ func (h *TileProxyHandler) ProxyTile(c *gin.Context) {
 	objectPath := strings.TrimPrefix(c.Param("objectPath"), "/")
 
 	if imageID == "" || objectPath == "" {
-		h.logger.Warn("Missing imageId or objectPath",
-			"imageId", imageID,
-			"objectPath", objectPath)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "imageId and objectPath are required"})
+		h.HandleError(c, errors.NewBadRequestError("imageId and objectPath are required", nil))
 		return
 	}
 
@@ -51,23 +72,72 @@ func (h *TileProxyHandler) ProxyTile(c *gin.Context) {
 		"objectPath", objectPath)
 
 	ctx := c.Request.Context()
-	reader, err := h.tileServer.ServeRequest(ctx, imageID, objectPath)
+	obj, err := h.tileServer.Object(ctx, imageID, objectPath)
 	if err != nil {
-		h.logger.Error("Failed to serve request",
-			"imageId", imageID,
-			"objectPath", objectPath,
-			"error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.HandleError(c, err)
 		return
 	}
-	defer reader.Close()
 
-	// Set content type based on file extension
-	contentType := h.getContentType(objectPath)
-	c.Header("Content-Type", contentType)
-	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if etagMatches(c.GetHeader("If-None-Match"), obj.ETag) {
+		c.Header("ETag", obj.ETag)
+		c.Header("Cache-Control", cacheControl(obj.Type))
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	// A stale If-Range asks for the whole object instead
+	if rangeHeader := c.GetHeader("Range"); rangeHeader != "" && ifRangeMatches(c.GetHeader("If-Range"), obj.ETag) {
+		size, err := obj.Size()
+		if err != nil {
+			h.HandleError(c, err)
+			return
+		}
 
+		start, length, valid, satisfiable := parseByteRange(rangeHeader, size)
+		if valid && !satisfiable {
+			c.Header("Content-Range", fmt.Sprintf("bytes */%d", size))
+			c.Status(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		if valid {
+			reader, err := obj.OpenRange(start, length)
+			if err != nil {
+				h.HandleError(c, err)
+				return
+			}
+			defer reader.Close()
+
+			h.setObjectHeaders(c, obj, objectPath)
+			c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+			c.Header("Content-Length", strconv.FormatInt(length, 10))
+			c.Status(http.StatusPartialContent)
+			h.copyBody(c, imageID, objectPath, reader)
+			return
+		}
+	}
+
+	reader, err := obj.Open()
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	h.setObjectHeaders(c, obj, objectPath)
 	c.Status(http.StatusOK)
+	h.copyBody(c, imageID, objectPath, reader)
+}
+
+// setObjectHeaders is called once the body is known to be readable, so error
+// responses never carry the object's validators.
+func (h *TileProxyHandler) setObjectHeaders(c *gin.Context, obj *proxy.Object, objectPath string) {
+	c.Header("ETag", obj.ETag)
+	c.Header("Cache-Control", cacheControl(obj.Type))
+	c.Header("Accept-Ranges", "bytes")
+	c.Header("Content-Type", h.getContentType(objectPath))
+}
+
+func (h *TileProxyHandler) copyBody(c *gin.Context, imageID, objectPath string, reader io.Reader) {
 	written, err := io.Copy(c.Writer, reader)
 	if err != nil {
 		h.logger.Error("Error copying data", "error", err)
@@ -79,6 +149,188 @@ func (h *TileProxyHandler) ProxyTile(c *gin.Context) {
 	}
 }
 
+// cacheControl lets browsers keep tiles for a while but revalidate the
+// descriptors, so a reprocessed image shows up on the next load.
+func cacheControl(requestType proxy.RequestType) string {
+	if requestType == proxy.RequestTypeTile {
+		return "private, max-age=3600"
+	}
+	return "private, no-cache"
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifRangeMatches reports whether a Range request may be honored. Only
+// strong entity tags are accepted; dates are treated as stale.
+func ifRangeMatches(header, etag string) bool {
+	return header == "" || header == etag
+}
+
+// parseByteRange resolves a single byte range against size. valid is false
+// for headers that should be ignored, such as multiple ranges.
+func parseByteRange(header string, size int64) (start, length int64, valid, satisfiable bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, false, false
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, 0, false, false
+	}
+
+	if first == "" {
+		// Suffix range: the last n bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false, false
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, true, false
+		}
+		n = min(n, size)
+		return size - n, n, true, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, false
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, false
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return 0, 0, true, false
+	}
+	return start, end - start + 1, true, true
+}
+
+// SignedURL godoc
+// @Summary Issue a signed tile URL
+// @Description Returns Deep Zoom and IIIF URLs that serve the image's tiles without credentials until
+// @Description they expire, for embedding in viewers. The caller needs access to the image.
+// @Tags Tiles
+// @Produce json
+// @Param id path string true "Image ID"
+// @Success 200 {object} response.SignedTileURLDataResponse
+// @Failure 400 {object} response.ErrorResponse "Signed URLs are not enabled"
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /images/{id}/signed-tile-url [post]
+func (h *TileProxyHandler) SignedURL(c *gin.Context) {
+	imageID := c.Param("id")
+
+	signed, err := h.access.Sign(imageID, time.Now())
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	base := requestOrigin(c) + "/api/v1/signed/" + signed.Token
+	h.Response.Success(c, http.StatusOK, response.SignedTileURLResponse{
+		Token:     signed.Token,
+		ExpiresAt: signed.ExpiresAt,
+		DZIURL:    base + "/proxy/" + imageID + "/image.dzi",
+		IIIFURL:   base + "/iiif/" + imageID,
+	})
+}
+
+// Prefetch godoc
+// @Summary Hint the tiles around a viewport
+// @Description Queues the tiles around the viewport and the viewport at the next finer level for
+// @Description loading into the tile cache in the background, so panning and zooming hit warm tiles.
+// @Description Hints beyond the prefetch queue are dropped. The caller needs access to the image.
+// @Tags Tiles
+// @Accept json
+// @Produce json
+// @Param imageId path string true "Image ID"
+// @Param request body request.TilePrefetchRequest true "Viewport"
+// @Success 202 {object} response.TilePrefetchDataResponse
+// @Failure 400 {object} response.ErrorResponse "Invalid viewport"
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /proxy/{imageId}/prefetch [post]
+func (h *TileProxyHandler) Prefetch(c *gin.Context) {
+	var req request.TilePrefetchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.HandleError(c, errors.NewValidationError("invalid request payload", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	viewport := proxy.Viewport{
+		Level:  *req.Level,
+		X:      req.X,
+		Y:      req.Y,
+		Width:  req.Width,
+		Height: req.Height,
+		Margin: proxy.DefaultPrefetchMargin,
+	}
+	if req.Margin != nil {
+		viewport.Margin = *req.Margin
+	}
+
+	scheduled, err := h.prefetcher.Prefetch(c.Request.Context(), c.Param("imageId"), viewport)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+	h.Response.Success(c, http.StatusAccepted, response.TilePrefetchResponse{Scheduled: scheduled})
+}
+
+// CacheStats godoc
+// @Summary Get tile cache statistics
+// @Description Hit, miss, entry and byte counts of the tile server's metadata and tile payload caches.
+// @Description Admin only.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.TileCacheStatsDataResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/tile-cache/stats [get]
+func (h *TileProxyHandler) CacheStats(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	metadata, err := h.tileServer.GetStats(ctx)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+	tiles, err := h.tileServer.GetTileStats(ctx)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+
+	h.Response.Success(c, http.StatusOK, response.TileCacheStatsResponse{
+		Metadata: response.NewCacheStatsResponse(metadata),
+		Tiles:    response.NewCacheStatsResponse(tiles),
+	})
+}
+
 func (h *TileProxyHandler) getContentType(path string) string {
 	lowerPath := strings.ToLower(path)
 
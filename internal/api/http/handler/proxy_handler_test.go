@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		header      string
+		start       int64
+		length      int64
+		valid       bool
+		satisfiable bool
+	}{
+		{"bytes=0-99", 0, 100, true, true},
+		{"bytes=900-", 900, 100, true, true},
+		{"bytes=900-5000", 900, 100, true, true},
+		{"bytes=-10", 990, 10, true, true},
+		{"bytes=-5000", 0, 1000, true, true},
+		{"bytes=1000-", 0, 0, true, false},
+		{"bytes=-0", 0, 0, true, false},
+		// Ignored: the whole object is served
+		{"bytes=0-1,5-9", 0, 0, false, false},
+		{"bytes=9-1", 0, 0, false, false},
+		{"items=0-1", 0, 0, false, false},
+		{"bytes=a-", 0, 0, false, false},
+	}
+
+	for _, tt := range tests {
+		start, length, valid, satisfiable := parseByteRange(tt.header, 1000)
+		assert.Equal(t, tt.valid, valid, tt.header)
+		assert.Equal(t, tt.satisfiable, satisfiable, tt.header)
+		if satisfiable {
+			assert.Equal(t, tt.start, start, tt.header)
+			assert.Equal(t, tt.length, length, tt.header)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `"abc"`
+	assert.True(t, etagMatches(`"abc"`, etag))
+	assert.True(t, etagMatches(`"x", W/"abc"`, etag))
+	assert.True(t, etagMatches(`*`, etag))
+	assert.False(t, etagMatches(`"abd"`, etag))
+	assert.False(t, etagMatches(``, etag))
+
+	assert.True(t, ifRangeMatches(``, etag))
+	assert.True(t, ifRangeMatches(`"abc"`, etag))
+	assert.False(t, ifRangeMatches(`Wed, 21 Oct 2015 07:28:00 GMT`, etag))
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/dto/request"
+	"github.com/histopathai/main-service/internal/api/http/dto/response"
+	"github.com/histopathai/main-service/internal/api/http/handler/helper"
+	"github.com/histopathai/main-service/internal/api/http/middleware"
+	"github.com/histopathai/main-service/internal/application/command"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+const (
+	defaultDeliveryListLimit = 50
+	maxDeliveryListLimit     = 200
+)
+
+type WebhookHandler struct {
+	helper.BaseHandler
+	webhookQuery   port.WebhookQuery
+	webhookUseCase port.WebhookUseCase
+}
+
+func NewWebhookHandler(query port.WebhookQuery, useCase port.WebhookUseCase, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookQuery:   query,
+		webhookUseCase: useCase,
+		BaseHandler:    helper.NewBaseHandler(logger),
+	}
+}
+
+// Create godoc
+// @Summary Create a webhook subscription for a workspace
+// @Description The signing secret is returned only in this response
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param request body request.CreateWebhookRequest true "Webhook creation request"
+// @Success 201 {object} response.WebhookDataResponse "Webhook created successfully"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id}/webhooks [post]
+func (wh *WebhookHandler) Create(c *gin.Context) {
+	creatorID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	var req request.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		wh.HandleError(c, errors.NewValidationError("invalid request payload", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	cmd := command.CreateWebhookCommand{
+		WsID:       c.Param("id"),
+		CreatorID:  creatorID,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+
+		RequesterIsAdmin: admin,
+	}
+	if req.Secret != nil {
+		cmd.Secret = *req.Secret
+	}
+
+	result, err := wh.webhookUseCase.Create(c.Request.Context(), cmd)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	wh.Response.Created(c, response.NewCreatedWebhookResponse(result))
+}
+
+// ListByWorkspace godoc
+// @Summary List webhook subscriptions of a workspace
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} response.WebhookListResponseDoc
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id}/webhooks [get]
+func (wh *WebhookHandler) ListByWorkspace(c *gin.Context) {
+	requesterID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	result, err := wh.webhookQuery.ListByWorkspace(c.Request.Context(), c.Param("id"), requesterID, admin)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	webhookResponses := make([]response.WebhookResponse, len(result))
+	for i, s := range result {
+		webhookResponses[i] = *response.NewWebhookResponse(s)
+	}
+
+	wh.Response.SuccessList(c, webhookResponses, nil)
+}
+
+// Get godoc
+// @Summary Get a webhook subscription by ID
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.WebhookDataResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [get]
+func (wh *WebhookHandler) Get(c *gin.Context) {
+	requesterID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	result, err := wh.webhookQuery.Get(c.Request.Context(), c.Param("id"), requesterID, admin)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	wh.Response.Success(c, http.StatusOK, response.NewWebhookResponse(result))
+}
+
+// Update godoc
+// @Summary Update a webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request body request.UpdateWebhookRequest true "Webhook update request"
+// @Success 204 "Webhook updated successfully"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [put]
+func (wh *WebhookHandler) Update(c *gin.Context) {
+	requesterID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	var req request.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		wh.HandleError(c, errors.NewValidationError("invalid request payload", map[string]interface{}{
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	cmd := command.UpdateWebhookCommand{
+		ID:         c.Param("id"),
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+		Active:     req.Active,
+
+		RequesterID:      requesterID,
+		RequesterIsAdmin: admin,
+	}
+
+	if err := wh.webhookUseCase.Update(c.Request.Context(), cmd); err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	wh.Response.NoContent(c)
+}
+
+// Delete godoc
+// @Summary Delete a webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [delete]
+func (wh *WebhookHandler) Delete(c *gin.Context) {
+	requesterID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	cmd := command.DeleteWebhookCommand{
+		ID:               c.Param("id"),
+		RequesterID:      requesterID,
+		RequesterIsAdmin: admin,
+	}
+	if err := wh.webhookUseCase.Delete(c.Request.Context(), cmd); err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	wh.Response.NoContent(c)
+}
+
+// ListDeliveries godoc
+// @Summary List recent deliveries of a webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Maximum number of deliveries" default(50) minimum(1) maximum(200)
+// @Success 200 {object} response.WebhookDeliveryListResponseDoc
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (wh *WebhookHandler) ListDeliveries(c *gin.Context) {
+	requesterID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	limit := defaultDeliveryListLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxDeliveryListLimit {
+			wh.HandleError(c, errors.NewValidationError("invalid limit", map[string]interface{}{
+				"limit": "must be between 1 and 200",
+			}))
+			return
+		}
+		limit = parsed
+	}
+
+	result, err := wh.webhookQuery.ListDeliveries(c.Request.Context(), c.Param("id"), requesterID, admin, limit)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	deliveryResponses := make([]response.WebhookDeliveryResponse, len(result))
+	for i, d := range result {
+		deliveryResponses[i] = *response.NewWebhookDeliveryResponse(d)
+	}
+
+	wh.Response.SuccessList(c, deliveryResponses, nil)
+}
+
+// Redeliver godoc
+// @Summary Redeliver a past webhook delivery
+// @Description Queues a new delivery with the original payload; the original log entry is kept
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} response.WebhookDeliveryDataResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (wh *WebhookHandler) Redeliver(c *gin.Context) {
+	requesterID, admin, err := authenticatedRequester(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	cmd := command.RedeliverWebhookCommand{
+		WebhookID:        c.Param("id"),
+		DeliveryID:       c.Param("delivery_id"),
+		RequesterID:      requesterID,
+		RequesterIsAdmin: admin,
+	}
+	result, err := wh.webhookUseCase.Redeliver(c.Request.Context(), cmd)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+
+	wh.Response.Success(c, http.StatusAccepted, response.NewWebhookDeliveryResponse(result))
+}
+
+// authenticatedRequester returns the caller's user ID and whether they are an admin.
+func authenticatedRequester(c *gin.Context) (string, bool, error) {
+	userID, err := middleware.GetAuthenticatedUserID(c)
+	if err != nil {
+		return "", false, err
+	}
+	role, _ := middleware.GetAuthenticatedUserRole(c)
+	return userID, role == "admin" || role == "ADMIN", nil
+}
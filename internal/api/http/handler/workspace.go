@@ -70,6 +70,8 @@ func (wh *WorkspaceHandler) Create(c *gin.Context) {
 		},
 		OrganType:       req.OrganType,
 		AnnotationTypes: req.AnnotationTypes,
+		Members:         req.Members,
+		DeIdentified:    req.DeIdentified,
 		Organization:    req.Organization,
 		Description:     req.Description,
 		License:         req.License,
@@ -178,11 +180,19 @@ func (wh *WorkspaceHandler) List(c *gin.Context) {
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Security BearerAuth
 // @Router /workspaces/{id} [put]
 func (wh *WorkspaceHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 
+	requesterID, err := middleware.GetAuthenticatedUserID(c)
+	if err != nil {
+		wh.HandleError(c, err)
+		return
+	}
+	role, _ := middleware.GetAuthenticatedUserRole(c)
+
 	var req request.UpdateWorkspaceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		wh.HandleError(c, errors.NewValidationError("invalid request payload", map[string]interface{}{
@@ -198,13 +208,17 @@ func (wh *WorkspaceHandler) Update(c *gin.Context) {
 			Name:      req.Name,
 			CreatorID: req.CreatorID,
 		},
-		OrganType:       req.OrganType,
-		Organization:    req.Organization,
-		Description:     req.Description,
-		License:         req.License,
-		ResourceURL:     req.ResourceURL,
-		ReleaseYear:     req.ReleaseYear,
-		AnnotationTypes: req.AnnotationTypes,
+		OrganType:        req.OrganType,
+		Organization:     req.Organization,
+		Description:      req.Description,
+		License:          req.License,
+		ResourceURL:      req.ResourceURL,
+		ReleaseYear:      req.ReleaseYear,
+		AnnotationTypes:  req.AnnotationTypes,
+		Members:          req.Members,
+		DeIdentified:     req.DeIdentified,
+		RequesterID:      requesterID,
+		RequesterIsAdmin: role == "admin" || role == "ADMIN",
 	}
 
 	errDetails, ok := cmd.Validate()
@@ -213,8 +227,7 @@ func (wh *WorkspaceHandler) Update(c *gin.Context) {
 		return
 	}
 
-	err := wh.WsUsecase.Update(c.Request.Context(), cmd)
-	if err != nil {
+	if err := wh.WsUsecase.Update(c.Request.Context(), cmd); err != nil {
 		wh.HandleError(c, err)
 		return
 	}
@@ -72,6 +72,23 @@ func (am *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	}
 }
 
+// RequireAdmin must run after RequireAuth.
+func (am *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := GetAuthenticatedUserRole(c)
+		if role != "admin" && role != "ADMIN" {
+			am.logger.Warn("Non-admin user denied", "path", c.Request.URL.Path, "user_role", role)
+			c.JSON(http.StatusForbidden, response.ErrorResponse{
+				ErrorType: string(errors.ErrorTypeForbidden),
+				Message:   "admin role required",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // Helper function for handlers
 func GetAuthenticatedUserID(c *gin.Context) (string, error) {
 	userID, exists := c.Get("authenticated_user_id")
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	stderr "errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/histopathai/main-service/internal/api/http/dto/response"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// ImageAuthorizer decides access to an image's tiles.
+type ImageAuthorizer interface {
+	Authorize(ctx context.Context, userID string, admin bool, imageID string) error
+	VerifyToken(imageID, token string, now time.Time) error
+}
+
+type ImageAccessMiddleware struct {
+	authorizer ImageAuthorizer
+	logger     *slog.Logger
+}
+
+func NewImageAccessMiddleware(authorizer ImageAuthorizer, logger *slog.Logger) *ImageAccessMiddleware {
+	return &ImageAccessMiddleware{authorizer: authorizer, logger: logger}
+}
+
+// RequireImageAccess must run after RequireAuth. param names the route
+// parameter holding the image ID.
+func (im *ImageAccessMiddleware) RequireImageAccess(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := GetAuthenticatedUserID(c)
+		if err != nil {
+			im.abort(c, err)
+			return
+		}
+		role, _ := GetAuthenticatedUserRole(c)
+		admin := role == "admin" || role == "ADMIN"
+
+		if err := im.authorizer.Authorize(c.Request.Context(), userID, admin, c.Param(param)); err != nil {
+			im.abort(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSignedToken admits requests whose :token path segment was signed
+// for the image, in place of user credentials.
+func (im *ImageAccessMiddleware) RequireSignedToken(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := im.authorizer.VerifyToken(c.Param(param), c.Param("token"), time.Now()); err != nil {
+			im.abort(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+func (im *ImageAccessMiddleware) abort(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	errResponse := response.ErrorResponse{
+		ErrorType: string(errors.ErrorTypeInternal),
+		Message:   "An unexpected error occurred",
+	}
+
+	var customErr *errors.Err
+	if stderr.As(err, &customErr) {
+		switch customErr.Type {
+		case errors.ErrorTypeUnauthorized:
+			status = http.StatusUnauthorized
+		case errors.ErrorTypeForbidden:
+			status = http.StatusForbidden
+		case errors.ErrorTypeNotFound:
+			status = http.StatusNotFound
+		}
+		if status != http.StatusInternalServerError {
+			errResponse = response.ErrorResponse{ErrorType: string(customErr.Type), Message: customErr.Message}
+		}
+	}
+
+	if status == http.StatusInternalServerError {
+		im.logger.Error("Image access check failed", "path", c.Request.URL.Path, "error", err)
+	} else {
+		im.logger.Warn("Image access denied", "path", c.Request.URL.Path, "reason", err.Error())
+	}
+	c.AbortWithStatusJSON(status, errResponse)
+}
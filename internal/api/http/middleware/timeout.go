@@ -13,19 +13,34 @@ import (
 )
 
 type TimeoutMiddleware struct {
-	timeout time.Duration
-	logger  *slog.Logger
+	timeout   time.Duration
+	logger    *slog.Logger
+	skipPaths map[string]bool
 }
 
 func NewTimeoutMiddleware(timeout time.Duration, logger *slog.Logger) *TimeoutMiddleware {
 	return &TimeoutMiddleware{
-		timeout: timeout,
-		logger:  logger,
+		timeout:   timeout,
+		logger:    logger,
+		skipPaths: map[string]bool{},
+	}
+}
+
+// Skip exempts routes (by registered pattern, e.g. "/api/v1/workspaces/:id/events")
+// from the timeout. Used for long-lived streaming responses.
+func (tm *TimeoutMiddleware) Skip(routes ...string) {
+	for _, route := range routes {
+		tm.skipPaths[route] = true
 	}
 }
 
 func (tm *TimeoutMiddleware) Handler() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if tm.skipPaths[c.FullPath()] {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), tm.timeout)
 		defer cancel()
 
@@ -26,16 +26,24 @@ type Router struct {
 	config *RouterConfig
 
 	// Handlers
-	workspaceHandler      *handler.WorkspaceHandler
-	patientHandler        *handler.PatientHandler
-	imageHandler          *handler.ImageHandler
-	annotationHandler     *handler.AnnotationHandler
-	annotationTypeHandler *handler.AnnotationTypeHandler
-	tileProxyHandler      *handler.TileProxyHandler
+	workspaceHandler       *handler.WorkspaceHandler
+	patientHandler         *handler.PatientHandler
+	imageHandler           *handler.ImageHandler
+	imageProcessingHandler *handler.ImageProcessingHandler
+	annotationHandler      *handler.AnnotationHandler
+	annotationTypeHandler  *handler.AnnotationTypeHandler
+	tileProxyHandler       *handler.TileProxyHandler
+	iiifHandler            *handler.IIIFHandler
+	overlayHandler         *handler.OverlayHandler
+	imageRegionHandler     *handler.ImageRegionHandler
+	webhookHandler         *handler.WebhookHandler
+	eventStreamHandler     *handler.EventStreamHandler
+	eventReplayHandler     *handler.EventReplayHandler
 
 	// Middleware
-	authMiddleware    *middleware.AuthMiddleware
-	timeoutMiddleware *middleware.TimeoutMiddleware
+	authMiddleware        *middleware.AuthMiddleware
+	timeoutMiddleware     *middleware.TimeoutMiddleware
+	imageAccessMiddleware *middleware.ImageAccessMiddleware
 
 	// Health checker (optional, can be nil)
 	healthChecker HealthChecker
@@ -46,23 +54,39 @@ func NewRouter(
 	workspaceHandler *handler.WorkspaceHandler,
 	patientHandler *handler.PatientHandler,
 	imageHandler *handler.ImageHandler,
+	imageProcessingHandler *handler.ImageProcessingHandler,
 	annotationHandler *handler.AnnotationHandler,
 	annotationTypeHandler *handler.AnnotationTypeHandler,
 	tileProxyHandler *handler.TileProxyHandler,
+	iiifHandler *handler.IIIFHandler,
+	overlayHandler *handler.OverlayHandler,
+	imageRegionHandler *handler.ImageRegionHandler,
+	webhookHandler *handler.WebhookHandler,
+	eventStreamHandler *handler.EventStreamHandler,
+	eventReplayHandler *handler.EventReplayHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	timeoutMiddleware *middleware.TimeoutMiddleware,
+	imageAccessMiddleware *middleware.ImageAccessMiddleware,
 ) *Router {
 	return &Router{
-		engine:                gin.Default(),
-		config:                config,
-		workspaceHandler:      workspaceHandler,
-		patientHandler:        patientHandler,
-		imageHandler:          imageHandler,
-		annotationHandler:     annotationHandler,
-		annotationTypeHandler: annotationTypeHandler,
-		tileProxyHandler:      tileProxyHandler,
-		authMiddleware:        authMiddleware,
-		timeoutMiddleware:     timeoutMiddleware,
+		engine:                 gin.Default(),
+		config:                 config,
+		workspaceHandler:       workspaceHandler,
+		patientHandler:         patientHandler,
+		imageHandler:           imageHandler,
+		imageProcessingHandler: imageProcessingHandler,
+		annotationHandler:      annotationHandler,
+		annotationTypeHandler:  annotationTypeHandler,
+		tileProxyHandler:       tileProxyHandler,
+		iiifHandler:            iiifHandler,
+		overlayHandler:         overlayHandler,
+		imageRegionHandler:     imageRegionHandler,
+		webhookHandler:         webhookHandler,
+		eventStreamHandler:     eventStreamHandler,
+		eventReplayHandler:     eventReplayHandler,
+		authMiddleware:         authMiddleware,
+		timeoutMiddleware:      timeoutMiddleware,
+		imageAccessMiddleware:  imageAccessMiddleware,
 	}
 }
 
@@ -72,6 +96,8 @@ func (r *Router) SetHealthChecker(hc HealthChecker) {
 
 func (r *Router) SetupRoutes() *gin.Engine {
 	// Global Middlewares
+	r.timeoutMiddleware.Skip("/api/v1/workspaces/:id/events") // SSE stream is long-lived
+	r.timeoutMiddleware.Skip("/api/v1/admin/events/replay")   // Runs handlers in sequence
 	r.engine.Use(middleware.RequestIDMiddleware())
 	r.engine.Use(r.timeoutMiddleware.Handler())
 
@@ -93,14 +119,35 @@ func (r *Router) SetupRoutes() *gin.Engine {
 		r.setupImageRoutes(v1)
 		r.setupAnnotationRoutes(v1)
 		r.setupAnnotationTypeRoutes(v1)
+		r.setupWebhookRoutes(v1)
+		r.setupAdminRoutes(v1)
 
-		// Tile Proxy
-		v1.GET("/proxy/:imageId/*objectPath", r.tileProxyHandler.ProxyTile)
+		r.setupTileRoutes(v1, r.imageAccessMiddleware.RequireImageAccess("imageId"))
+	}
+
+	// Signed tile URLs stand in for user credentials
+	signed := r.engine.Group("/api/v1/signed/:token")
+	{
+		r.setupTileRoutes(signed, r.imageAccessMiddleware.RequireSignedToken("imageId"))
 	}
 
 	return r.engine
 }
 
+func (r *Router) setupTileRoutes(rg *gin.RouterGroup, access gin.HandlerFunc) {
+	// Tile Proxy
+	rg.GET("/proxy/:imageId/*objectPath", access, r.tileProxyHandler.ProxyTile)
+	rg.POST("/proxy/:imageId/prefetch", access, r.tileProxyHandler.Prefetch)
+
+	// IIIF Image API
+	rg.GET("/iiif/:imageId", access, r.iiifHandler.Base)
+	rg.GET("/iiif/:imageId/info.json", access, r.iiifHandler.Info)
+	rg.GET("/iiif/:imageId/:region/:size/:rotation/:quality", access, r.iiifHandler.Image)
+
+	// Annotation overlay tiles, on the image's DZI grid
+	rg.GET("/overlay/:imageId/:level/:tile", access, r.overlayHandler.Tile)
+}
+
 func (r *Router) setupWorkspaceRoutes(rg *gin.RouterGroup) {
 	workspaces := rg.Group("/workspaces")
 	{
@@ -119,6 +166,11 @@ func (r *Router) setupWorkspaceRoutes(rg *gin.RouterGroup) {
 
 		// Sub-resources
 		workspaces.GET("/:id/patients", r.patientHandler.GetByParentID)
+		workspaces.POST("/:id/webhooks", r.webhookHandler.Create)
+		workspaces.GET("/:id/webhooks", r.webhookHandler.ListByWorkspace)
+
+		// Live updates (server-sent events)
+		workspaces.GET("/:id/events", r.eventStreamHandler.Stream)
 	}
 }
 
@@ -163,6 +215,16 @@ func (r *Router) setupImageRoutes(rg *gin.RouterGroup) {
 		images.PUT("/:id/transfer/:patient_id", r.imageHandler.Transfer)
 		images.PUT("/transfer-many/:patient_id", r.imageHandler.TransferMany)
 
+		// Processing
+		images.POST("/:id/reprocess", r.imageProcessingHandler.Reprocess)
+		images.POST("/:id/cancel-processing", r.imageProcessingHandler.CancelProcessing)
+		images.GET("/:id/processing-history", r.imageProcessingHandler.History)
+
+		// Rendering
+		imageAccess := r.imageAccessMiddleware.RequireImageAccess("id")
+		images.GET("/:id/region", imageAccess, r.imageRegionHandler.Region)
+		images.POST("/:id/signed-tile-url", imageAccess, r.tileProxyHandler.SignedURL)
+
 		// Queries
 		images.GET("/parent/:parent_id", r.imageHandler.GetByParentID)
 		images.GET("/workspace/:workspace_id", r.imageHandler.GetByWorkspaceID)
@@ -207,6 +269,37 @@ func (r *Router) setupAnnotationTypeRoutes(rg *gin.RouterGroup) {
 	}
 }
 
+func (r *Router) setupWebhookRoutes(rg *gin.RouterGroup) {
+	webhooks := rg.Group("/webhooks")
+	{
+		// CRUD Operations (creation lives under /workspaces/:id/webhooks)
+		webhooks.GET("/:id", r.webhookHandler.Get)
+		webhooks.PUT("/:id", r.webhookHandler.Update)
+		webhooks.DELETE("/:id", r.webhookHandler.Delete)
+
+		// Delivery log
+		webhooks.GET("/:id/deliveries", r.webhookHandler.ListDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/redeliver", r.webhookHandler.Redeliver)
+	}
+}
+
+func (r *Router) setupAdminRoutes(rg *gin.RouterGroup) {
+	admin := rg.Group("/admin")
+	{
+		admin.Use(r.authMiddleware.RequireAdmin())
+
+		// Event log and replay
+		admin.GET("/events", r.eventReplayHandler.List)
+		admin.POST("/events/replay", r.eventReplayHandler.Replay)
+
+		// Processing statistics
+		admin.GET("/processing/stats", r.imageProcessingHandler.Stats)
+
+		// Tile cache statistics
+		admin.GET("/tile-cache/stats", r.tileProxyHandler.CacheStats)
+	}
+}
+
 func (r *Router) healthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "healthy",
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"slices"
+
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/shared/errors"
@@ -97,6 +99,8 @@ type CreateWorkspaceCommand struct {
 	ResourceURL     *string
 	ReleaseYear     *int
 	AnnotationTypes []string
+	Members         []string
+	DeIdentified    bool
 }
 
 func (c *CreateWorkspaceCommand) Validate() (map[string]interface{}, bool) {
@@ -154,6 +158,10 @@ func (c *CreateWorkspaceCommand) Validate() (map[string]interface{}, bool) {
 		}
 	}
 
+	if msg := validateMembers(c.Members); msg != "" {
+		details["members"] = msg
+	}
+
 	if len(details) > 0 {
 		return details, false
 	}
@@ -184,6 +192,7 @@ func (c *CreateWorkspaceCommand) ToEntity() (*model.Workspace, error) {
 		Organization: c.Organization,
 		Description:  c.Description,
 		License:      c.License,
+		DeIdentified: c.DeIdentified,
 	}
 
 	if c.ResourceURL != nil {
@@ -198,9 +207,26 @@ func (c *CreateWorkspaceCommand) ToEntity() (*model.Workspace, error) {
 		workspaceEntity.AnnotationTypes = c.AnnotationTypes
 	}
 
+	if c.Members != nil {
+		workspaceEntity.Members = c.Members
+	}
+
 	return &workspaceEntity, nil
 }
 
+// validateMembers returns why a workspace member list is invalid, or "".
+func validateMembers(members []string) string {
+	for i, member := range members {
+		if member == "" {
+			return "Members cannot contain empty user IDs"
+		}
+		if slices.Contains(members[i+1:], member) {
+			return "Members cannot contain duplicate user IDs"
+		}
+	}
+	return ""
+}
+
 // =============================================================================
 // Create Patient Command
 // =============================================================================
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+)
+
+// =============================================================================
+// Event Replay Commands
+// =============================================================================
+
+const (
+	DefaultReplayLimit = 100
+	MaxReplayLimit     = 1000
+)
+
+type ReplayEventsCommand struct {
+	EventTypes []string
+	ImageID    string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	DryRun     bool
+}
+
+func (c *ReplayEventsCommand) Validate() (map[string]interface{}, bool) {
+	details := make(map[string]interface{})
+
+	if len(c.EventTypes) == 0 && c.ImageID == "" && c.From == nil && c.To == nil {
+		details["filter"] = "At least one of event_types, image_id, from or to is required"
+	}
+	for _, t := range c.EventTypes {
+		if !isKnownEventType(domainevent.EventType(t)) {
+			details["event_types"] = "Unknown event type: " + t
+			break
+		}
+	}
+	if c.From != nil && c.To != nil && !c.From.Before(*c.To) {
+		details["to"] = "To must be after From"
+	}
+	if c.Limit < 0 || c.Limit > MaxReplayLimit {
+		details["limit"] = "Limit must be between 1 and 1000"
+	}
+
+	if len(details) > 0 {
+		return details, false
+	}
+	return nil, true
+}
+
+func isKnownEventType(t domainevent.EventType) bool {
+	switch t {
+	case domainevent.NewFileExistEventType,
+		domainevent.DeleteFileEventType,
+		domainevent.ImageProcessReqEventType,
+		domainevent.ImageProcessCompleteEventType:
+		return true
+	}
+	return domainevent.IsEntityEventType(t)
+}
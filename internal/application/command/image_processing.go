@@ -0,0 +1,47 @@
+package command
+
+import (
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
+
+// =============================================================================
+// Image Processing Commands
+// =============================================================================
+
+type ReprocessImageCommand struct {
+	ID string
+	// Empty keeps the image's current version
+	ProcessingVersion string
+	// Empty means normal
+	Priority string
+}
+
+func (c *ReprocessImageCommand) Validate() (map[string]interface{}, bool) {
+	details := make(map[string]interface{})
+
+	if c.ID == "" {
+		details["id"] = "ID is required"
+	}
+	if c.ProcessingVersion != "" && !vobj.ProcessingVersion(c.ProcessingVersion).IsValid() {
+		details["processing_version"] = "Processing version must be v1 or v2"
+	}
+	if c.Priority != "" && !vobj.ProcessingPriority(c.Priority).IsValid() {
+		details["priority"] = "Priority must be high, normal or low"
+	}
+
+	if len(details) > 0 {
+		return details, false
+	}
+	return nil, true
+}
+
+type CancelProcessingCommand struct {
+	ID string
+}
+
+func (c *CancelProcessingCommand) Validate() (map[string]interface{}, bool) {
+	if c.ID == "" {
+		return map[string]interface{}{"id": "ID is required"}, false
+	}
+	return nil, true
+}
@@ -74,6 +74,13 @@ type UpdateWorkspaceCommand struct {
 	ResourceURL     *string
 	ReleaseYear     *int
 	AnnotationTypes []string
+	// Replaces the member list when not nil; empty removes every member
+	Members      []string
+	DeIdentified *bool
+
+	// Who asks for the change; changing access needs the creator or an admin
+	RequesterID      string
+	RequesterIsAdmin bool
 }
 
 func (c *UpdateWorkspaceCommand) Validate() (map[string]interface{}, bool) {
@@ -99,6 +106,10 @@ func (c *UpdateWorkspaceCommand) Validate() (map[string]interface{}, bool) {
 		}
 	}
 
+	if msg := validateMembers(c.Members); msg != "" {
+		details["members"] = msg
+	}
+
 	if len(details) > 0 {
 		return details, false
 	}
@@ -140,6 +151,12 @@ func (c *UpdateWorkspaceCommand) GetUpdates() map[string]interface{} {
 	if len(c.AnnotationTypes) != 0 {
 		updates[fields.WorkspaceAnnotationTypes.DomainName()] = c.AnnotationTypes
 	}
+	if c.Members != nil {
+		updates[fields.WorkspaceMembers.DomainName()] = c.Members
+	}
+	if c.DeIdentified != nil {
+		updates[fields.WorkspaceDeIdentified.DomainName()] = *c.DeIdentified
+	}
 
 	return updates
 }
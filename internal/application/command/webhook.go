@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/shared/netguard"
+)
+
+// How long validating a webhook URL may wait on DNS
+const webhookResolveTimeout = 5 * time.Second
+
+// =============================================================================
+// Webhook Commands
+// =============================================================================
+
+type CreateWebhookCommand struct {
+	WsID       string
+	CreatorID  string
+	URL        string
+	EventTypes []string
+	Secret     string
+
+	// The creator is the requester; managing webhooks needs the workspace
+	// creator or an admin
+	RequesterIsAdmin bool
+}
+
+func (c *CreateWebhookCommand) Validate() (map[string]interface{}, bool) {
+	details := make(map[string]interface{})
+
+	if c.WsID == "" {
+		details["ws_id"] = "WsID is required"
+	}
+	if c.CreatorID == "" {
+		details["creator_id"] = "CreatorID is required"
+	}
+	if msg := validateWebhookURL(c.URL); msg != "" {
+		details["url"] = msg
+	}
+	if msg := validateEventFilters(c.EventTypes); msg != "" {
+		details["event_types"] = msg
+	}
+
+	if len(details) > 0 {
+		return details, false
+	}
+	return nil, true
+}
+
+type UpdateWebhookCommand struct {
+	ID         string
+	URL        *string
+	EventTypes []string
+	Secret     *string
+	Active     *bool
+
+	RequesterID      string
+	RequesterIsAdmin bool
+}
+
+func (c *UpdateWebhookCommand) Validate() (map[string]interface{}, bool) {
+	details := make(map[string]interface{})
+
+	if c.ID == "" {
+		details["id"] = "ID is required"
+	}
+	if c.URL != nil {
+		if msg := validateWebhookURL(*c.URL); msg != "" {
+			details["url"] = msg
+		}
+	}
+	if msg := validateEventFilters(c.EventTypes); msg != "" {
+		details["event_types"] = msg
+	}
+	if c.Secret != nil && *c.Secret == "" {
+		details["secret"] = "Secret cannot be empty"
+	}
+
+	if len(details) > 0 {
+		return details, false
+	}
+	return nil, true
+}
+
+type DeleteWebhookCommand struct {
+	ID               string
+	RequesterID      string
+	RequesterIsAdmin bool
+}
+
+type RedeliverWebhookCommand struct {
+	WebhookID        string
+	DeliveryID       string
+	RequesterID      string
+	RequesterIsAdmin bool
+}
+
+// validateWebhookURL requires an https URL whose host resolves only to
+// public addresses. Deliveries re-check the address they connect to.
+func validateWebhookURL(raw string) string {
+	if raw == "" {
+		return "URL is required"
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || u.Scheme != "https" {
+		return "URL must be an absolute https URL"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+	defer cancel()
+	if err := netguard.CheckHost(ctx, u.Hostname()); err != nil {
+		return "URL must point to a public host: " + err.Error()
+	}
+	return ""
+}
+
+// validateEventFilters accepts "*", an entity wildcard such as "image.*",
+// or a known entity event type.
+func validateEventFilters(filters []string) string {
+	for _, f := range filters {
+		if f == "*" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(f, ".*"); ok && prefix != "" {
+			continue
+		}
+		if !domainevent.IsEntityEventType(domainevent.EventType(f)) {
+			return "Unknown event type: " + f
+		}
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+)
+
+// EventRecorder writes events to the event log. Recording is best effort: a
+// failure is logged but never fails the publish or the handler.
+type EventRecorder struct {
+	repo       port.EventLogRepository
+	serializer portevent.EventSerializer
+	logger     *slog.Logger
+}
+
+func NewEventRecorder(repo port.EventLogRepository, serializer portevent.EventSerializer, logger *slog.Logger) *EventRecorder {
+	return &EventRecorder{
+		repo:       repo,
+		serializer: serializer,
+		logger:     logger,
+	}
+}
+
+func (r *EventRecorder) Record(ctx context.Context, event domainevent.Event, direction model.EventDirection, channel string) {
+	payload, err := r.serializer.Serialize(event)
+	if err != nil {
+		r.logger.Warn("Failed to serialize event for event log",
+			slog.String("event_id", event.GetEventID()),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	entry := &model.EventLogEntry{
+		EventID:    event.GetEventID(),
+		EventType:  string(event.GetEventType()),
+		Direction:  direction,
+		Channel:    channel,
+		ImageID:    domainevent.ImageIDOf(event),
+		Payload:    payload,
+		OccurredAt: event.GetTimestamp(),
+	}
+	if err := r.repo.Append(ctx, entry); err != nil {
+		r.logger.Warn("Failed to record event in event log",
+			slog.String("event_id", event.GetEventID()),
+			slog.String("error", err.Error()))
+	}
+}
+
+// RecordingPublisher records every successfully published event.
+type RecordingPublisher struct {
+	next     portevent.EventPublisher
+	recorder *EventRecorder
+}
+
+func NewRecordingPublisher(next portevent.EventPublisher, recorder *EventRecorder) *RecordingPublisher {
+	return &RecordingPublisher{
+		next:     next,
+		recorder: recorder,
+	}
+}
+
+func (p *RecordingPublisher) Publish(ctx context.Context, event domainevent.Event) error {
+	if err := p.next.Publish(ctx, event); err != nil {
+		return err
+	}
+	p.recorder.Record(ctx, event, model.EventPublished, "")
+	return nil
+}
+
+// RecordingSubscriber records every event received on a subscription before
+// handing it to the handler, so failed deliveries can be replayed too.
+type RecordingSubscriber struct {
+	next     portevent.EventSubscriber
+	recorder *EventRecorder
+	channel  string
+}
+
+func NewRecordingSubscriber(next portevent.EventSubscriber, recorder *EventRecorder, channel string) *RecordingSubscriber {
+	return &RecordingSubscriber{
+		next:     next,
+		recorder: recorder,
+		channel:  channel,
+	}
+}
+
+func (s *RecordingSubscriber) Subscribe(ctx context.Context, handler portevent.EventHandler) error {
+	return s.next.Subscribe(ctx, &recordingHandler{next: handler, subscriber: s})
+}
+
+func (s *RecordingSubscriber) Stop() error {
+	return s.next.Stop()
+}
+
+type recordingHandler struct {
+	next       portevent.EventHandler
+	subscriber *RecordingSubscriber
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, event domainevent.Event) error {
+	h.subscriber.recorder.Record(ctx, event, model.EventConsumed, h.subscriber.channel)
+	return h.next.Handle(ctx, event)
+}
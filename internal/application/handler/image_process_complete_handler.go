@@ -7,32 +7,38 @@ import (
 
 	"github.com/google/uuid"
 	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
 
 	portevent "github.com/histopathai/main-service/internal/port/event"
 )
 
+// Enough to reach the job of any attempt whose result can still be applied
+const jobLookback = 20
+
+// ImageProcessCompleteHandler applies a worker's result to the image and
+// hands the produced files to the NewFileHandler.
 type ImageProcessCompleteHandler struct {
-	subscriber  portevent.EventSubscriber
-	publisher   portevent.EventPublisher
-	imageRepo   port.ImageRepository
-	contentRepo port.ContentRepository
-	logger      *slog.Logger
+	subscriber portevent.EventSubscriber
+	publisher  portevent.EventPublisher
+	uow        port.UnitOfWorkFactory
+	logger     *slog.Logger
 }
 
 func NewImageProcessCompleteHandler(
 	subscriber portevent.EventSubscriber,
 	publisher portevent.EventPublisher,
-	imageRepo port.ImageRepository,
-	contentRepo port.ContentRepository,
+	uow port.UnitOfWorkFactory,
 	logger *slog.Logger,
 ) *ImageProcessCompleteHandler {
 	return &ImageProcessCompleteHandler{
-		subscriber:  subscriber,
-		publisher:   publisher,
-		imageRepo:   imageRepo,
-		contentRepo: contentRepo,
-		logger:      logger,
+		subscriber: subscriber,
+		publisher:  publisher,
+		uow:        uow,
+		logger:     logger,
 	}
 }
 
@@ -54,31 +60,198 @@ func (h *ImageProcessCompleteHandler) Handle(ctx context.Context, event domainev
 		return nil
 	}
 
-	if processCompleteEvent.Success {
-		for _, content := range processCompleteEvent.Contents {
+	applied, err := h.apply(ctx, processCompleteEvent)
+	if err != nil {
+		return err
+	}
+	if applied {
+		h.recordOutcome(ctx, processCompleteEvent)
+	}
+	if !applied || !processCompleteEvent.Success {
+		return nil
+	}
+
+	for _, content := range processCompleteEvent.Contents {
+
+		event := domainevent.NewFileExistEvent{
+			BaseEvent: domainevent.BaseEvent{
+				EventID:   uuid.New().String(),
+				EventType: domainevent.NewFileExistEventType,
+				Timestamp: time.Now(),
+			},
+			Content: content,
+		}
+
+		if err := h.publisher.Publish(ctx, &event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply records the result on the image and reports whether it was accepted.
+// Results for an attempt that is no longer the image's active one (retried,
+// reprocessed or cancelled since) are dropped.
+func (h *ImageProcessCompleteHandler) apply(ctx context.Context, event *domainevent.ImageProcessCompleteEvent) (bool, error) {
+	logger := h.logger.With(
+		slog.String("image_id", event.ImageID),
+		slog.String("request_event_id", event.RequestEventID))
+	applied := false
+
+	err := h.uow.WithTx(ctx, func(txCtx context.Context) error {
+		applied = false
+
+		imageRepo := h.uow.GetImageRepo()
+		image, err := imageRepo.Read(txCtx, event.ImageID)
+		if errors.IsNotFound(err) {
+			logger.Warn("ImageProcessCompleteHandler: image not found, dropping result")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if image.IsDeleted() || image.Processing == nil {
+			logger.Warn("ImageProcessCompleteHandler: image is not being processed, dropping result")
+			return nil
+		}
+
+		info := *image.Processing
+		// Workers that predate request_event_id are trusted while the image is processing
+		if event.RequestEventID != "" && event.RequestEventID != info.ActiveEventID {
+			logger.Info("ImageProcessCompleteHandler: result of a superseded attempt, dropping",
+				slog.String("active_event_id", info.ActiveEventID))
+			return nil
+		}
+
+		// The produced files may already have completed the image
+		alreadyProcessed := event.Success && info.Status == vobj.StatusProcessed
+		if info.Status != vobj.StatusProcessing && !alreadyProcessed {
+			logger.Info("ImageProcessCompleteHandler: image is no longer processing, dropping result",
+				slog.String("status", info.Status.String()))
+			return nil
+		}
+
+		var origin *model.Content
+		if event.Success && event.Result != nil && event.Result.Size > 0 && image.OriginContentID != nil {
+			origin, err = h.uow.GetContentRepo().Read(txCtx, *image.OriginContentID)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
 
-			event := domainevent.NewFileExistEvent{
-				BaseEvent: domainevent.BaseEvent{
-					EventID:   uuid.New().String(),
-					EventType: domainevent.NewFileExistEventType,
-					Timestamp: time.Now(),
-				},
-				Content: content,
+		// Writes must come after all reads.
+		updates := map[string]interface{}{}
+		if event.Success {
+			if event.Result != nil && event.Result.Width > 0 && event.Result.Height > 0 {
+				updates[fields.ImageWidth.DomainName()] = event.Result.Width
+				updates[fields.ImageHeight.DomainName()] = event.Result.Height
+				image.Width = &event.Result.Width
+				image.Height = &event.Result.Height
 			}
+			if !alreadyProcessed {
+				version := event.ProcessingVersion
+				if !version.IsValid() {
+					version = info.Version
+				}
+				if err := info.MarkAsProcessed(version); err != nil {
+					return err
+				}
+				updates[fields.ImageProcessingVersion.DomainName()] = info.Version
+				updates[fields.ImageProcessingFailureReason.DomainName()] = ""
+			}
+		} else {
+			reason := event.FailureReason
+			if reason == "" {
+				reason = "worker reported failure without a reason"
+			}
+			if err := info.MarkAsFailed(reason); err != nil {
+				return err
+			}
+			updates[fields.ImageProcessingFailureReason.DomainName()] = reason
+		}
+		if info.Status != image.Processing.Status {
+			updates[fields.ImageProcessingStatus.DomainName()] = info.Status
+			updates[fields.ImageProcessingLastProcessedAt.DomainName()] = info.LastProcessedAt
+		}
 
-			if err := h.publisher.Publish(ctx, &event); err != nil {
+		// Sizes reported at upload may be missing; the worker read the file
+		if origin != nil && origin.Size != event.Result.Size {
+			if err := h.uow.GetContentRepo().Update(txCtx, origin.ID, map[string]interface{}{
+				fields.ContentSize.DomainName(): event.Result.Size,
+			}); err != nil {
 				return err
 			}
 		}
 
-	} else {
+		applied = true
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := imageRepo.Update(txCtx, image.ID, updates); err != nil {
+			return err
+		}
+
+		image.Processing = &info
+		if err := h.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(image, updates)); err != nil {
+			return err
+		}
+
+		if info.Status == vobj.StatusFailed {
+			logger.Error("Image processing failed", slog.String("reason", *info.FailureReason))
+		}
+		return nil
+	})
+
+	return applied, err
+}
 
-		h.logger.Error("Image processing failed",
-			slog.String("image_id", processCompleteEvent.ImageID),
-			slog.String("reason", processCompleteEvent.FailureReason),
-		)
+// recordOutcome closes the attempt's job record with the result and the
+// sizes of its outputs. It is best effort: the image already has the result.
+func (h *ImageProcessCompleteHandler) recordOutcome(ctx context.Context, event *domainevent.ImageProcessCompleteEvent) {
+	// Results of workers that predate request_event_id can't be matched to an attempt
+	if event.RequestEventID == "" {
+		return
+	}
 
+	jobRepo := h.uow.GetProcessingJobRepo()
+	jobs, err := jobRepo.ListByImage(ctx, event.ImageID, jobLookback)
+	if err != nil {
+		h.logger.Warn("ImageProcessCompleteHandler: failed to list processing jobs",
+			slog.String("image_id", event.ImageID),
+			slog.String("error", err.Error()))
+		return
 	}
 
-	return nil
+	for _, job := range jobs {
+		if job.EventID != event.RequestEventID {
+			continue
+		}
+
+		// The tracker may have seen the execution succeed first
+		if job.Status == model.ProcessingJobRunning {
+			if event.Success {
+				job.Finish(model.ProcessingJobSucceeded, "")
+			} else {
+				job.Finish(model.ProcessingJobFailed, event.FailureReason)
+			}
+		}
+		for _, content := range event.Contents {
+			output, ok := vobj.OutputOf(content.ContentType)
+			if !ok {
+				continue
+			}
+			if job.OutputSizes == nil {
+				job.OutputSizes = map[vobj.ProcessingOutput]int64{}
+			}
+			job.OutputSizes[output] += content.Size
+		}
+
+		if err := jobRepo.Update(ctx, job); err != nil {
+			h.logger.Warn("ImageProcessCompleteHandler: failed to record processing outcome",
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()))
+		}
+	}
 }
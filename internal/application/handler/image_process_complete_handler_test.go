@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func completeEvent(imageID, requestEventID string, success bool) *domainevent.ImageProcessCompleteEvent {
+	return &domainevent.ImageProcessCompleteEvent{
+		BaseEvent:         domainevent.BaseEvent{EventID: "done-" + imageID, EventType: domainevent.ImageProcessCompleteEventType},
+		ImageID:           imageID,
+		RequestEventID:    requestEventID,
+		ProcessingVersion: vobj.ProcessingV2,
+		Success:           success,
+	}
+}
+
+func TestImageProcessCompleteHandler_AppliesOnlyActiveAttempt(t *testing.T) {
+	origin := "origin-1"
+	image := processingImage("evt-1")
+	image.ID = "img-1"
+	image.OriginContentID = &origin
+	images := &fakeImageRepo{images: map[string]*model.Image{"img-1": image}, updates: map[string]map[string]interface{}{}}
+	contents := &fakeContentRepo{contents: map[string]*model.Content{origin: {Entity: vobj.Entity{ID: origin}}}}
+	outbox := &fakeOutboxRepo{}
+	publisher := &fakePublisher{}
+	jobs := &fakeProcessingJobRepo{running: []*model.ProcessingJob{
+		{ID: "job-0", ImageID: "img-1", EventID: "evt-0", Status: model.ProcessingJobCancelled},
+		{ID: "job-1", ImageID: "img-1", EventID: "evt-1", Status: model.ProcessingJobRunning},
+	}}
+
+	h := NewImageProcessCompleteHandler(nil, publisher,
+		&fakeUOW{images: images, contents: contents, outbox: outbox, jobs: jobs},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	// A late result of a superseded attempt changes nothing
+	stale := completeEvent("img-1", "evt-0", false)
+	stale.FailureReason = "killed"
+	require.NoError(t, h.Handle(ctx, stale))
+	assert.Empty(t, images.updates)
+	assert.Empty(t, outbox.added)
+
+	done := completeEvent("img-1", "evt-1", true)
+	done.Result = &domainevent.ProcessResult{Width: 4000, Height: 3000, Size: 1 << 20}
+	done.Contents = []model.Content{
+		{Entity: vobj.Entity{ID: "thumb-1"}, ContentType: vobj.ContentTypeThumbnailJPEG, Size: 2048},
+		{Entity: vobj.Entity{ID: "tiles-1"}, ContentType: vobj.ContentTypeApplicationZip, Size: 1 << 24},
+	}
+	require.NoError(t, h.Handle(ctx, done))
+
+	updates := images.updates["img-1"]
+	assert.Equal(t, vobj.StatusProcessed, updates[fields.ImageProcessingStatus.DomainName()])
+	assert.Equal(t, 4000, updates[fields.ImageWidth.DomainName()])
+	assert.Equal(t, 3000, updates[fields.ImageHeight.DomainName()])
+	assert.Equal(t, int64(1<<20), contents.updates[origin][fields.ContentSize.DomainName()])
+	assert.Len(t, outbox.added, 1)
+	require.Len(t, publisher.published, 2)
+	assert.Equal(t, "thumb-1", publisher.published[0].(*domainevent.NewFileExistEvent).Content.ID)
+
+	// Only the active attempt's job is closed, with its output sizes
+	require.Len(t, jobs.updated, 1)
+	assert.Equal(t, "job-1", jobs.updated[0].ID)
+	assert.Equal(t, model.ProcessingJobSucceeded, jobs.updated[0].Status)
+	assert.Equal(t, int64(2048), jobs.updated[0].OutputSizes[vobj.OutputThumbnail])
+	assert.Equal(t, int64(2048+1<<24), jobs.updated[0].OutputBytes())
+}
+
+func TestImageProcessCompleteHandler_StoresFailureReason(t *testing.T) {
+	image := processingImage("evt-1")
+	image.ID = "img-1"
+	images := &fakeImageRepo{images: map[string]*model.Image{"img-1": image}, updates: map[string]map[string]interface{}{}}
+	h := NewImageProcessCompleteHandler(nil, &fakePublisher{},
+		&fakeUOW{images: images, outbox: &fakeOutboxRepo{}, jobs: &fakeProcessingJobRepo{}},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	failed := completeEvent("img-1", "evt-1", false)
+	failed.FailureReason = "openslide: unsupported format"
+	require.NoError(t, h.Handle(context.Background(), failed))
+
+	updates := images.updates["img-1"]
+	assert.Equal(t, vobj.StatusFailed, updates[fields.ImageProcessingStatus.DomainName()])
+	assert.Equal(t, "openslide: unsupported format", updates[fields.ImageProcessingFailureReason.DomainName()])
+}
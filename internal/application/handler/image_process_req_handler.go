@@ -6,27 +6,30 @@ import (
 
 	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/port"
 	portevent "github.com/histopathai/main-service/internal/port/event"
 )
 
+// ImageProcessHandler validates image process requests and queues them; the
+// ProcessingScheduler starts them on the worker.
 type ImageProcessHandler struct {
 	subscriber portevent.EventSubscriber
-	worker     port.ImageProcessingWorker
+	scheduler  *ProcessingScheduler
 	imageRepo  port.ImageRepository
 	logger     *slog.Logger
 }
 
 func NewImageProcessHandler(
 	subscriber portevent.EventSubscriber,
-	worker port.ImageProcessingWorker,
+	scheduler *ProcessingScheduler,
 	imageRepo port.ImageRepository,
 	logger *slog.Logger,
 ) *ImageProcessHandler {
 	return &ImageProcessHandler{
 		subscriber: subscriber,
-		worker:     worker,
+		scheduler:  scheduler,
 		imageRepo:  imageRepo,
 		logger:     logger,
 	}
@@ -62,7 +65,8 @@ func (h *ImageProcessHandler) Handle(ctx context.Context, event domainevent.Even
 
 	// 2. Check for Stale/Duplicate Event
 	// If the active event ID in DB does not match this event's ID, it means another request (newer or older winning race) took precedence.
-	if imageEntity.Processing != nil && imageEntity.Processing.ActiveEventID != "" {
+	replay := portevent.IsReplay(ctx)
+	if !replay && imageEntity.Processing != nil && imageEntity.Processing.ActiveEventID != "" {
 		if imageEntity.Processing.ActiveEventID != processEvent.EventID {
 			h.logger.Warn("ImageProcessHandler: skipping stale/duplicate event",
 				"current_active_id", imageEntity.Processing.ActiveEventID,
@@ -71,24 +75,61 @@ func (h *ImageProcessHandler) Handle(ctx context.Context, event domainevent.Even
 		}
 	}
 
-	updates := map[string]any{
-		fields.ImageProcessingStatus.DomainName():  vobj.StatusProcessing,
-		fields.ImageProcessingVersion.DomainName(): processEvent.ProcessingVersion,
+	info := vobj.NewProcessingInfo(processEvent.ProcessingVersion)
+	if imageEntity.Processing != nil {
+		*info = *imageEntity.Processing
+	}
+	// Whoever issued the request already queued the image; a redelivery after
+	// it started, finished or was cancelled must not queue it again. Images
+	// still processing were requested before the queue existed.
+	if !replay && info.Status != vobj.StatusQueued && info.Status != vobj.StatusProcessing {
+		h.logger.Warn("ImageProcessHandler: image is no longer queued, skipping event",
+			"image_id", processEvent.Content.Parent.ID,
+			"status", info.Status,
+			"event_id", processEvent.EventID)
+		return nil
 	}
 
-	// We technically don't need to update again if NewFileHandler did, but we might want to update timestamps or verify connection.
-	// Actually, let's keep the update to ensure "Processing" state and timestamp refreshed if we want.
-	// But minimal is fine.
+	// Requests issued before profiles existed process as before
+	profile := processEvent.ProcessingProfile
+	if !profile.IsValid() {
+		profile = info.Profile
+	}
+	if !profile.IsValid() {
+		profile = vobj.ProfilePyramid
+	}
 
-	err = h.imageRepo.Update(ctx, processEvent.Content.Parent.ID, updates)
-	if err != nil {
-		return err
+	updates := map[string]any{
+		fields.ImageProcessingVersion.DomainName(): processEvent.ProcessingVersion,
+		fields.ImageProcessingProfile.DomainName(): profile,
+	}
+	// A replayed event is re-run on purpose and takes over as the active one
+	if replay {
+		if err := info.MarkAsQueued(); err != nil {
+			h.logger.Warn("ImageProcessHandler: image cannot be processed, skipping event",
+				"image_id", processEvent.Content.Parent.ID,
+				"error", err)
+			return nil
+		}
+		updates[fields.ImageProcessingStatus.DomainName()] = info.Status
+		updates[fields.ImageProcessingLastProcessedAt.DomainName()] = info.LastProcessedAt
+		updates[fields.ImageProcessingActiveEventID.DomainName()] = processEvent.EventID
 	}
 
-	err = h.worker.ProcessImage(ctx, processEvent.Content, processEvent.ProcessingVersion)
+	// Updated before queueing, so the scheduler never sees an entry ahead of
+	// the image state it was queued for
+	err = h.imageRepo.Update(ctx, processEvent.Content.Parent.ID, updates)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return h.scheduler.Enqueue(ctx, &model.ProcessingQueueEntry{
+		ImageID:           processEvent.Content.Parent.ID,
+		WsID:              imageEntity.WsID,
+		EventID:           processEvent.EventID,
+		ContentID:         processEvent.Content.ID,
+		ProcessingVersion: processEvent.ProcessingVersion,
+		ProcessingProfile: profile,
+		Priority:          processEvent.Priority,
+	})
 }
@@ -19,6 +19,7 @@ type NewFileHandler struct {
 	subscriber portevent.EventSubscriber
 	publisher  portevent.EventPublisher
 	uow        port.UnitOfWorkFactory
+	pipelines  *vobj.PipelineRegistry
 	logger     *slog.Logger
 }
 
@@ -26,12 +27,14 @@ func NewNewFileHandler(
 	subscriber portevent.EventSubscriber,
 	uow port.UnitOfWorkFactory,
 	publisher portevent.EventPublisher,
+	pipelines *vobj.PipelineRegistry,
 	logger *slog.Logger,
 ) *NewFileHandler {
 	return &NewFileHandler{
 		subscriber: subscriber,
 		publisher:  publisher,
 		uow:        uow,
+		pipelines:  pipelines,
 		logger:     logger,
 	}
 }
@@ -56,6 +59,7 @@ func (h *NewFileHandler) Handle(ctx context.Context, event domainevent.Event) er
 
 	content := &newFileEvent.Content
 	shouldPublish := false
+	var profile vobj.ProcessingProfile
 	eventID := uuid.New().String()
 
 	// ... inside WithTx ...
@@ -85,34 +89,65 @@ func (h *NewFileHandler) Handle(ctx context.Context, event domainevent.Event) er
 			imageUpdates[fields.ImageDziContentID.DomainName()] = content.ID
 			imageEntity.DziContentID = &content.ID
 
+		} else if content.ContentType.IsTiles() {
+			imageUpdates[fields.ImageTilesContentID.DomainName()] = content.ID
+			imageEntity.TilesContentID = &content.ID
+
 		} else if content.ContentType.IsArchive() {
 			imageUpdates[fields.ImageZipTilesContentID.DomainName()] = content.ID
 			imageEntity.ZipTilesContentID = &content.ID
 
+		} else if content.ContentType.IsLabel() {
+			imageUpdates[fields.ImageLabelContentID.DomainName()] = content.ID
+			imageEntity.LabelContentID = &content.ID
+
+		} else if content.ContentType.IsMacro() {
+			imageUpdates[fields.ImageMacroContentID.DomainName()] = content.ID
+			imageEntity.MacroContentID = &content.ID
+
 		} else if content.ContentType.IsOriginImage() {
 			// Idempotency Check
-			if imageEntity.Processing != nil && (imageEntity.Processing.Status == vobj.StatusProcessing || imageEntity.Processing.Status == vobj.StatusProcessed) {
-				h.logger.Info("NewFileHandler: image already processing or processed, skipping request",
+			// (bypassed on replay, which re-runs processing on purpose)
+			if !portevent.IsReplay(ctx) && imageEntity.Processing != nil && (imageEntity.Processing.Status == vobj.StatusQueued || imageEntity.Processing.Status == vobj.StatusProcessing || imageEntity.Processing.Status == vobj.StatusProcessed) {
+				h.logger.Info("NewFileHandler: image already queued, processing or processed, skipping request",
 					"image_id", content.Parent.ID,
 					"status", imageEntity.Processing.Status)
 				return nil
 			}
 
+			// Note: Processing struct might be nil
+			info := vobj.NewProcessingInfo(vobj.ProcessingV2)
+			if imageEntity.Processing != nil {
+				*info = *imageEntity.Processing
+			}
+			// A first upload passes through uploaded; a re-upload restarts processing
+			if info.Status == "" || info.Status == vobj.StatusPending {
+				if err := info.MarkAsUploaded(); err != nil {
+					return err
+				}
+			}
+			if err := info.MarkAsQueued(); err != nil {
+				h.logger.Warn("NewFileHandler: origin uploaded for an image that cannot be processed, skipping request",
+					"image_id", content.Parent.ID,
+					"error", err)
+				return nil
+			}
+			info.Version = vobj.ProcessingV2
+			info.Profile = h.pipelines.Resolve(content.ContentType, content.Size)
+			info.ActiveEventID = eventID
+
 			imageUpdates[fields.ImageOriginContentID.DomainName()] = content.ID
-			imageUpdates[fields.ImageProcessingStatus.DomainName()] = vobj.StatusProcessing
-			imageUpdates[fields.ImageProcessingVersion.DomainName()] = vobj.ProcessingV2
+			imageUpdates[fields.ImageProcessingStatus.DomainName()] = info.Status
+			imageUpdates[fields.ImageProcessingVersion.DomainName()] = info.Version
+			imageUpdates[fields.ImageProcessingProfile.DomainName()] = info.Profile
 			imageUpdates[fields.ImageProcessingActiveEventID.DomainName()] = eventID
+			imageUpdates[fields.ImageProcessingLastProcessedAt.DomainName()] = info.LastProcessedAt
 
 			// Update local model
 			imageEntity.OriginContentID = &content.ID
-			// Note: Processing struct might be nil
-			if imageEntity.Processing == nil {
-				imageEntity.Processing = &vobj.ProcessingInfo{}
-			}
-			imageEntity.Processing.Status = vobj.StatusProcessing
-			imageEntity.Processing.Version = vobj.ProcessingV2
-			imageEntity.Processing.ActiveEventID = eventID
+			imageEntity.Processing = info
 
+			profile = info.Profile
 			shouldPublish = true
 
 		} else if content.ContentType.IsIndexMap() {
@@ -120,29 +155,28 @@ func (h *NewFileHandler) Handle(ctx context.Context, event domainevent.Event) er
 			imageEntity.IndexmapContentID = &content.ID
 		}
 
-		// 3. Check completion logic (using in-memory imageEntity)
-		isComplete := false
-		if imageEntity.Processing != nil && !content.ContentType.IsOriginImage() {
-			// Only check completion for generated files, not when origin uploads (unless it somehow completes everything immediately which is impossible)
-			// Actually origin upload sets status to Processing, so we shouldn't overwrite it to Processed here anyway.
-
-			if imageEntity.Processing.Version == vobj.ProcessingV1 {
-				// v1: thumbnail, dzi, tiles
-				if imageEntity.ThumbnailContentID != nil && imageEntity.DziContentID != nil && imageEntity.TilesContentID != nil {
-					isComplete = true
-				}
-			} else if imageEntity.Processing.Version == vobj.ProcessingV2 {
-				// v2: thumbnail, dzi, zip, indexmap
-				if imageEntity.ThumbnailContentID != nil && imageEntity.DziContentID != nil && imageEntity.ZipTilesContentID != nil && imageEntity.IndexmapContentID != nil {
-					isComplete = true
-				}
+		// 3. Check completion against the outputs the image's profile requires
+		// (using in-memory imageEntity). Origin uploads only start processing;
+		// associated images are optional and never complete an image.
+		isComplete := imageEntity.Processing != nil && !content.ContentType.IsOriginImage() &&
+			!content.ContentType.IsAssociatedImage() && len(imageEntity.MissingOutputs()) == 0
+
+		// The completion event may have marked the image processed already;
+		// images moved on (e.g. cancelled) stay where they are
+		if isComplete && imageEntity.Processing.Status != vobj.StatusProcessed {
+			info := *imageEntity.Processing
+			if err := info.MarkAsProcessed(info.Version); err != nil {
+				h.logger.Info("NewFileHandler: image is no longer processing, not marking it processed",
+					"image_id", imageEntity.ID,
+					"status", info.Status)
+				isComplete = false
+			} else {
+				imageUpdates[fields.ImageProcessingStatus.DomainName()] = info.Status
+				imageUpdates[fields.ImageProcessingLastProcessedAt.DomainName()] = info.LastProcessedAt
+				imageEntity.Processing = &info
 			}
 		}
 
-		if isComplete {
-			imageUpdates[fields.ImageProcessingStatus.DomainName()] = vobj.StatusProcessed
-		}
-
 		// 4. Perform Writes (Create Content + Update Image)
 		// Writes must come after all reads.
 
@@ -158,6 +192,20 @@ func (h *NewFileHandler) Handle(ctx context.Context, event domainevent.Event) er
 			}
 		}
 
+		if shouldPublish {
+			statusEvent := domainevent.NewEntityUpdatedEvent(imageEntity, imageUpdates)
+			if err := h.uow.GetOutboxRepo().Add(ctx, statusEvent); err != nil {
+				return err
+			}
+		}
+
+		if isComplete {
+			processedEvent := domainevent.NewEntityEvent(domainevent.EntityProcessed, imageEntity)
+			if err := h.uow.GetOutboxRepo().Add(ctx, processedEvent); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 
@@ -175,6 +223,7 @@ func (h *NewFileHandler) Handle(ctx context.Context, event domainevent.Event) er
 			},
 			Content:           *content,
 			ProcessingVersion: vobj.ProcessingV2,
+			ProcessingProfile: profile,
 		})
 		if err != nil {
 			return err
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+)
+
+// OutboxRelay publishes events recorded in the outbox by the use cases.
+// Delivery is at-least-once: an event is marked published only after the
+// publisher acknowledges it, so a crash in between causes a re-publish.
+//
+// Listeners receive every event after it is published and before it is
+// marked, so they share the at-least-once guarantee and must be idempotent.
+//
+// An event whose publish or listeners fail is retried with backoff while
+// newer events go ahead; once the policy gives up it is dead-lettered and
+// left in the outbox for inspection.
+type OutboxRelay struct {
+	outbox       port.OutboxRepository
+	publisher    portevent.EventPublisher
+	listeners    []portevent.EventHandler
+	policy       RetryPolicy
+	pollInterval time.Duration
+	batchSize    int
+	logger       *slog.Logger
+	stop         chan struct{}
+}
+
+func NewOutboxRelay(
+	outbox port.OutboxRepository,
+	publisher portevent.EventPublisher,
+	policy RetryPolicy,
+	pollInterval time.Duration,
+	batchSize int,
+	logger *slog.Logger,
+	listeners ...portevent.EventHandler,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:       outbox,
+		publisher:    publisher,
+		listeners:    listeners,
+		policy:       policy,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		logger:       logger,
+		stop:         make(chan struct{}),
+	}
+}
+
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	r.logger.Info("OutboxRelay started", slog.Duration("poll_interval", r.pollInterval))
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.stop:
+			return nil
+		case <-ticker.C:
+			r.relayPending(ctx)
+		}
+	}
+}
+
+func (r *OutboxRelay) Stop() error {
+	r.logger.Info("OutboxRelay stopping...")
+	close(r.stop)
+	return nil
+}
+
+func (r *OutboxRelay) relayPending(ctx context.Context) {
+	for {
+		pending, err := r.outbox.FetchPending(ctx, time.Now(), r.batchSize)
+		if err != nil {
+			r.logger.Error("OutboxRelay: failed to fetch pending events", slog.String("error", err.Error()))
+			return
+		}
+
+		for _, p := range pending {
+			if err := r.relay(ctx, p.Event); err != nil {
+				// Stop the tick if the failure cannot be recorded, or the
+				// next fetch returns the same event again
+				if err := r.recordFailure(ctx, p, err); err != nil {
+					r.logger.Error("OutboxRelay: failed to record relay failure",
+						slog.String("event_id", p.Event.GetEventID()),
+						slog.String("error", err.Error()))
+					return
+				}
+				continue
+			}
+
+			if err := r.outbox.MarkPublished(ctx, p.Event.GetEventID()); err != nil {
+				r.logger.Error("OutboxRelay: failed to mark event published",
+					slog.String("event_id", p.Event.GetEventID()),
+					slog.String("error", err.Error()))
+				return
+			}
+		}
+
+		if len(pending) < r.batchSize {
+			return
+		}
+	}
+}
+
+// relay publishes the event and hands it to the listeners.
+func (r *OutboxRelay) relay(ctx context.Context, event domainevent.Event) error {
+	if err := r.publisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+
+	for _, listener := range r.listeners {
+		if err := listener.Handle(ctx, event); err != nil {
+			return fmt.Errorf("listener: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// recordFailure schedules the next attempt, or dead-letters the event once
+// the policy's attempts are used up.
+func (r *OutboxRelay) recordFailure(ctx context.Context, p port.PendingEvent, cause error) error {
+	eventID := p.Event.GetEventID()
+	attempts := p.Attempts + 1
+
+	if attempts >= r.policy.MaxAttempts {
+		r.logger.Error("OutboxRelay: event dead-lettered",
+			slog.String("event_id", eventID),
+			slog.String("event_type", string(p.Event.GetEventType())),
+			slog.Int("attempts", attempts),
+			slog.String("error", cause.Error()))
+		return r.outbox.MarkDeadLettered(ctx, eventID, attempts, cause.Error())
+	}
+
+	r.logger.Warn("OutboxRelay: failed to relay event",
+		slog.String("event_id", eventID),
+		slog.String("event_type", string(p.Event.GetEventType())),
+		slog.Int("attempt", attempts),
+		slog.String("error", cause.Error()))
+	return r.outbox.RecordFailure(ctx, eventID, attempts, cause.Error(), time.Now().Add(r.policy.Backoff(attempts)))
+}
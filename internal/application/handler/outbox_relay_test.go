@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/stretchr/testify/assert"
+)
+
+type relayOutboxEntry struct {
+	event         domainevent.Event
+	status        string
+	attempts      int
+	nextAttemptAt time.Time
+}
+
+type fakeRelayOutbox struct {
+	port.OutboxRepository
+	entries []*relayOutboxEntry
+}
+
+func (o *fakeRelayOutbox) entry(eventID string) *relayOutboxEntry {
+	for _, e := range o.entries {
+		if e.event.GetEventID() == eventID {
+			return e
+		}
+	}
+	return nil
+}
+
+func (o *fakeRelayOutbox) FetchPending(ctx context.Context, now time.Time, limit int) ([]port.PendingEvent, error) {
+	var pending []port.PendingEvent
+	for _, e := range o.entries {
+		if e.status == "pending" && !e.nextAttemptAt.After(now) && len(pending) < limit {
+			pending = append(pending, port.PendingEvent{Event: e.event, Attempts: e.attempts})
+		}
+	}
+	return pending, nil
+}
+
+func (o *fakeRelayOutbox) MarkPublished(ctx context.Context, eventID string) error {
+	o.entry(eventID).status = "published"
+	return nil
+}
+
+func (o *fakeRelayOutbox) RecordFailure(ctx context.Context, eventID string, attempts int, cause string, nextAttemptAt time.Time) error {
+	e := o.entry(eventID)
+	e.attempts = attempts
+	e.nextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (o *fakeRelayOutbox) MarkDeadLettered(ctx context.Context, eventID string, attempts int, cause string) error {
+	e := o.entry(eventID)
+	e.status = "dead_lettered"
+	e.attempts = attempts
+	return nil
+}
+
+type rejectingPublisher struct {
+	fakePublisher
+	reject string
+}
+
+func (p *rejectingPublisher) Publish(ctx context.Context, event domainevent.Event) error {
+	if event.GetEventID() == p.reject {
+		return fmt.Errorf("rejected")
+	}
+	return p.fakePublisher.Publish(ctx, event)
+}
+
+func TestOutboxRelay_DeadLettersPoisonEvent(t *testing.T) {
+	ctx := context.Background()
+	outbox := &fakeRelayOutbox{}
+	for _, id := range []string{"evt-1", "evt-2", "evt-3"} {
+		event := domainevent.NewEntityEvent(domainevent.EntityCreated, &model.Image{Entity: vobj.Entity{ID: id, EntityType: vobj.EntityTypeImage}})
+		event.EventID = id
+		outbox.entries = append(outbox.entries, &relayOutboxEntry{event: event, status: "pending"})
+	}
+	publisher := &rejectingPublisher{reject: "evt-1"}
+	relay := NewOutboxRelay(outbox, publisher,
+		RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Hour, Multiplier: 2},
+		time.Second, 10, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The failing event must not hold back the ones behind it
+	relay.relayPending(ctx)
+	assert.Len(t, publisher.published, 2)
+	assert.Equal(t, "published", outbox.entry("evt-2").status)
+	assert.Equal(t, "published", outbox.entry("evt-3").status)
+
+	poison := outbox.entry("evt-1")
+	assert.Equal(t, "pending", poison.status)
+	assert.Equal(t, 1, poison.attempts)
+	assert.True(t, poison.nextAttemptAt.After(time.Now().Add(59*time.Minute)))
+
+	// Not due yet
+	relay.relayPending(ctx)
+	assert.Equal(t, 1, poison.attempts)
+
+	for range 2 {
+		poison.nextAttemptAt = time.Time{}
+		relay.relayPending(ctx)
+	}
+	assert.Equal(t, "dead_lettered", poison.status)
+	assert.Equal(t, 3, poison.attempts)
+
+	poison.nextAttemptAt = time.Time{}
+	relay.relayPending(ctx)
+	assert.Equal(t, 3, poison.attempts)
+	assert.Len(t, publisher.published, 2)
+}
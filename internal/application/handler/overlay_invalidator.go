@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/histopathai/main-service/internal/application/proxy"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
+
+// OverlayInvalidator drops cached annotation overlays when annotations or
+// annotation types change.
+type OverlayInvalidator struct {
+	overlay *proxy.OverlayRenderer
+}
+
+func NewOverlayInvalidator(overlay *proxy.OverlayRenderer) *OverlayInvalidator {
+	return &OverlayInvalidator{overlay: overlay}
+}
+
+func (i *OverlayInvalidator) Handle(ctx context.Context, event domainevent.Event) error {
+	entityEvent, ok := event.(*domainevent.EntityEvent)
+	if !ok {
+		return nil
+	}
+
+	switch entityEvent.EntityType {
+	case vobj.EntityTypeAnnotation:
+		// Annotations hang off their image
+		i.overlay.InvalidateImage(ctx, entityEvent.Parent.ID)
+		if entityEvent.PreviousParent != nil {
+			i.overlay.InvalidateImage(ctx, entityEvent.PreviousParent.ID)
+		}
+	case vobj.EntityTypeAnnotationType:
+		if entityEvent.Action != domainevent.EntityCreated {
+			i.overlay.InvalidateAll(ctx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// ProcessingJobTracker polls the executions of running processing jobs and
+// fails the image when its execution failed or disappeared without a
+// completion event, so it does not stay processing forever.
+type ProcessingJobTracker struct {
+	uow           port.UnitOfWorkFactory
+	jobRepo       port.ProcessingJobRepository
+	worker        port.ImageProcessingWorker
+	backend       string
+	pollInterval  time.Duration
+	batchSize     int
+	notFoundGrace time.Duration
+	logger        *slog.Logger
+	stop          chan struct{}
+}
+
+func NewProcessingJobTracker(
+	uow port.UnitOfWorkFactory,
+	jobRepo port.ProcessingJobRepository,
+	worker port.ImageProcessingWorker,
+	backend string,
+	pollInterval time.Duration,
+	batchSize int,
+	notFoundGrace time.Duration,
+	logger *slog.Logger,
+) *ProcessingJobTracker {
+	return &ProcessingJobTracker{
+		uow:           uow,
+		jobRepo:       jobRepo,
+		worker:        worker,
+		backend:       backend,
+		pollInterval:  pollInterval,
+		batchSize:     batchSize,
+		notFoundGrace: notFoundGrace,
+		logger:        logger,
+		stop:          make(chan struct{}),
+	}
+}
+
+func (t *ProcessingJobTracker) Start(ctx context.Context) error {
+	t.logger.Info("ProcessingJobTracker started", slog.Duration("poll_interval", t.pollInterval))
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.stop:
+			return nil
+		case <-ticker.C:
+			t.Poll(ctx)
+		}
+	}
+}
+
+func (t *ProcessingJobTracker) Stop() error {
+	t.logger.Info("ProcessingJobTracker stopping...")
+	close(t.stop)
+	return nil
+}
+
+// Poll checks one batch of running jobs.
+func (t *ProcessingJobTracker) Poll(ctx context.Context) {
+	jobs, err := t.jobRepo.ListRunning(ctx, t.batchSize)
+	if err != nil {
+		t.logger.Error("ProcessingJobTracker: failed to list running jobs", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, job := range jobs {
+		// Executions of another backend can't be looked up from here; they are
+		// left for a deployment running that backend
+		if job.Backend != t.backend {
+			continue
+		}
+		if err := t.check(ctx, job); err != nil {
+			t.logger.Error("ProcessingJobTracker: failed to check job",
+				slog.String("job_id", job.ID),
+				slog.String("execution", job.ExecutionName),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (t *ProcessingJobTracker) check(ctx context.Context, job *model.ProcessingJob) error {
+	status, err := t.worker.GetExecutionStatus(ctx, job.ExecutionName)
+	if err != nil {
+		return err
+	}
+
+	switch status.State {
+	case port.ExecutionSucceeded:
+		// The completion event takes the image from here
+		job.Finish(model.ProcessingJobSucceeded, "")
+		return t.jobRepo.Update(ctx, job)
+	case port.ExecutionFailed:
+		reason := status.Reason
+		if reason == "" {
+			reason = "execution failed"
+		}
+		return t.fail(ctx, job, reason)
+	case port.ExecutionNotFound:
+		// Cloud Run lists an execution only shortly after RunJob returns
+		if time.Since(job.StartedAt) < t.notFoundGrace {
+			return nil
+		}
+		return t.fail(ctx, job, fmt.Sprintf("execution %s no longer exists", job.ExecutionName))
+	default:
+		return nil
+	}
+}
+
+func (t *ProcessingJobTracker) fail(ctx context.Context, job *model.ProcessingJob, reason string) error {
+	job.Finish(model.ProcessingJobFailed, reason)
+
+	// Firestore transactions must read before they write
+	return t.uow.WithTx(ctx, func(txCtx context.Context) error {
+		imageRepo := t.uow.GetImageRepo()
+		image, err := imageRepo.Read(txCtx, job.ImageID)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+
+		if err := t.jobRepo.Update(txCtx, job); err != nil {
+			return err
+		}
+
+		// The image is gone, or a newer request or a completion event already
+		// moved it on
+		if image == nil || image.Processing == nil ||
+			image.Processing.ActiveEventID != job.EventID ||
+			image.Processing.Status != vobj.StatusProcessing {
+			return nil
+		}
+
+		info := *image.Processing
+		if err := info.MarkAsFailed(reason); err != nil {
+			return err
+		}
+
+		t.logger.Warn("ProcessingJobTracker: marking image failed",
+			slog.String("image_id", job.ImageID),
+			slog.String("execution", job.ExecutionName),
+			slog.String("reason", reason))
+
+		updates := map[string]interface{}{
+			fields.ImageProcessingStatus.DomainName():          info.Status,
+			fields.ImageProcessingFailureReason.DomainName():   reason,
+			fields.ImageProcessingLastProcessedAt.DomainName(): info.LastProcessedAt,
+		}
+		if err := imageRepo.Update(txCtx, job.ImageID, updates); err != nil {
+			return err
+		}
+
+		image.Processing = &info
+		return t.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(image, updates))
+	})
+}
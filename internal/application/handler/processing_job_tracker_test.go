@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/histopathai/main-service/internal/shared/query"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUOW struct {
+	port.UnitOfWorkFactory
+	images   *fakeImageRepo
+	contents *fakeContentRepo
+	outbox   *fakeOutboxRepo
+	queue    *fakeQueueRepo
+	jobs     *fakeProcessingJobRepo
+}
+
+func (u *fakeUOW) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (u *fakeUOW) GetImageRepo() port.ImageRepository {
+	return u.images
+}
+
+func (u *fakeUOW) GetContentRepo() port.ContentRepository {
+	return u.contents
+}
+
+func (u *fakeUOW) GetOutboxRepo() port.OutboxRepository {
+	return u.outbox
+}
+
+func (u *fakeUOW) GetProcessingQueueRepo() port.ProcessingQueueRepository {
+	return u.queue
+}
+
+func (u *fakeUOW) GetProcessingJobRepo() port.ProcessingJobRepository {
+	return u.jobs
+}
+
+type fakeImageRepo struct {
+	port.ImageRepository
+	images  map[string]*model.Image
+	updates map[string]map[string]interface{}
+}
+
+func (r *fakeImageRepo) Read(ctx context.Context, id string) (*model.Image, error) {
+	image, ok := r.images[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("document not found")
+	}
+	return image, nil
+}
+
+func (r *fakeImageRepo) Update(ctx context.Context, id string, updates map[string]interface{}) error {
+	r.updates[id] = updates
+	return nil
+}
+
+// Find supports the equality and time range filters the sweepers use.
+func (r *fakeImageRepo) Find(ctx context.Context, spec query.Specification) (*query.Result[*model.Image], error) {
+	result := &query.Result[*model.Image]{Data: []*model.Image{}}
+	for _, image := range r.images {
+		if matchesFilters(image, spec.Filters) {
+			result.Data = append(result.Data, image)
+		}
+	}
+	return result, nil
+}
+
+func matchesFilters(image *model.Image, filters []query.Filter) bool {
+	for _, f := range filters {
+		switch f.Field {
+		case fields.ImageProcessingStatus.DomainName():
+			if image.Processing == nil || image.Processing.Status.String() != f.Value {
+				return false
+			}
+		case fields.ImageProcessingLastProcessedAt.DomainName():
+			if image.Processing == nil {
+				return false
+			}
+			at := f.Value.(time.Time)
+			if f.Operator == query.OpGreaterThan && !image.Processing.LastProcessedAt.After(at) {
+				return false
+			}
+			if f.Operator == query.OpLessThan && !image.Processing.LastProcessedAt.Before(at) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type fakeContentRepo struct {
+	port.ContentRepository
+	contents map[string]*model.Content
+	updates  map[string]map[string]interface{}
+}
+
+func (r *fakeContentRepo) Read(ctx context.Context, id string) (*model.Content, error) {
+	content, ok := r.contents[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("document not found")
+	}
+	return content, nil
+}
+
+func (r *fakeContentRepo) Update(ctx context.Context, id string, updates map[string]interface{}) error {
+	if r.updates == nil {
+		r.updates = map[string]map[string]interface{}{}
+	}
+	r.updates[id] = updates
+	return nil
+}
+
+type fakeOutboxRepo struct {
+	port.OutboxRepository
+	added []domainevent.Event
+}
+
+func (r *fakeOutboxRepo) Add(ctx context.Context, event domainevent.Event) error {
+	r.added = append(r.added, event)
+	return nil
+}
+
+type fakePublisher struct {
+	published []domainevent.Event
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event domainevent.Event) error {
+	p.published = append(p.published, event)
+	return nil
+}
+
+type fakeProcessingJobRepo struct {
+	running []*model.ProcessingJob
+	created []*model.ProcessingJob
+	updated []*model.ProcessingJob
+}
+
+func (r *fakeProcessingJobRepo) Create(ctx context.Context, job *model.ProcessingJob) error {
+	r.created = append(r.created, job)
+	return nil
+}
+
+func (r *fakeProcessingJobRepo) Update(ctx context.Context, job *model.ProcessingJob) error {
+	r.updated = append(r.updated, job)
+	return nil
+}
+
+func (r *fakeProcessingJobRepo) ListRunning(ctx context.Context, limit int) ([]*model.ProcessingJob, error) {
+	return r.running, nil
+}
+
+func (r *fakeProcessingJobRepo) ListByImage(ctx context.Context, imageID string, limit int) ([]*model.ProcessingJob, error) {
+	jobs := []*model.ProcessingJob{}
+	for _, job := range append(r.running, r.created...) {
+		if job.ImageID == imageID {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeProcessingJobRepo) Find(ctx context.Context, filter port.ProcessingJobFilter) ([]*model.ProcessingJob, error) {
+	return append(r.running, r.created...), nil
+}
+
+func (r *fakeProcessingJobRepo) CountRunning(ctx context.Context) (int, error) {
+	return len(r.running), nil
+}
+
+type fakeExecutionWorker struct {
+	port.ImageProcessingWorker
+	states map[string]port.ExecutionStatus
+}
+
+func (w *fakeExecutionWorker) GetExecutionStatus(ctx context.Context, executionName string) (*port.ExecutionStatus, error) {
+	status, ok := w.states[executionName]
+	if !ok {
+		return &port.ExecutionStatus{State: port.ExecutionNotFound}, nil
+	}
+	return &status, nil
+}
+
+func processingImage(eventID string) *model.Image {
+	return &model.Image{Processing: &vobj.ProcessingInfo{Status: vobj.StatusProcessing, ActiveEventID: eventID}}
+}
+
+func TestProcessingJobTracker_FailsImagesOfDeadExecutions(t *testing.T) {
+	started := time.Now().Add(-10 * time.Minute)
+	images := &fakeImageRepo{
+		images: map[string]*model.Image{
+			"img-failed":  processingImage("evt-1"),
+			"img-gone":    processingImage("evt-2"),
+			"img-running": processingImage("evt-3"),
+			"img-stale":   processingImage("evt-newer"),
+		},
+		updates: map[string]map[string]interface{}{},
+	}
+	jobs := &fakeProcessingJobRepo{running: []*model.ProcessingJob{
+		{ID: "j1", ImageID: "img-failed", EventID: "evt-1", Backend: "cloudrun", ExecutionName: "exec-1", StartedAt: started},
+		{ID: "j2", ImageID: "img-gone", EventID: "evt-2", Backend: "cloudrun", ExecutionName: "exec-2", StartedAt: started},
+		{ID: "j3", ImageID: "img-running", EventID: "evt-3", Backend: "cloudrun", ExecutionName: "exec-3", StartedAt: started},
+		{ID: "j4", ImageID: "img-stale", EventID: "evt-4", Backend: "cloudrun", ExecutionName: "exec-4", StartedAt: started},
+		{ID: "j5", ImageID: "img-young", EventID: "evt-5", Backend: "cloudrun", ExecutionName: "exec-5", StartedAt: time.Now()},
+		{ID: "j6", ImageID: "img-local", EventID: "evt-6", Backend: "local", ExecutionName: "exec-6", StartedAt: started},
+	}}
+	worker := &fakeExecutionWorker{states: map[string]port.ExecutionStatus{
+		"exec-1": {State: port.ExecutionFailed, Reason: "container exited with code 137"},
+		"exec-3": {State: port.ExecutionRunning},
+		"exec-4": {State: port.ExecutionFailed},
+	}}
+
+	for id, image := range images.images {
+		image.ID = id
+	}
+	outbox := &fakeOutboxRepo{}
+	tracker := NewProcessingJobTracker(&fakeUOW{images: images, outbox: outbox}, jobs, worker, "cloudrun",
+		time.Second, 10, 2*time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	tracker.Poll(context.Background())
+
+	assert.Equal(t, vobj.StatusFailed, images.updates["img-failed"][fields.ImageProcessingStatus.DomainName()])
+	assert.Equal(t, "container exited with code 137", images.updates["img-failed"][fields.ImageProcessingFailureReason.DomainName()])
+	assert.Equal(t, vobj.StatusFailed, images.updates["img-gone"][fields.ImageProcessingStatus.DomainName()])
+	assert.Contains(t, images.updates["img-gone"][fields.ImageProcessingFailureReason.DomainName()], "no longer exists")
+
+	var failedImages []string
+	for _, e := range outbox.added {
+		failedImages = append(failedImages, e.(*domainevent.EntityEvent).EntityID)
+	}
+	assert.ElementsMatch(t, []string{"img-failed", "img-gone"}, failedImages)
+
+	// Superseded by a newer request: only the job is closed
+	assert.NotContains(t, images.updates, "img-stale")
+	assert.NotContains(t, images.updates, "img-running")
+
+	var finished []string
+	for _, job := range jobs.updated {
+		assert.Equal(t, model.ProcessingJobFailed, job.Status)
+		assert.NotNil(t, job.FinishedAt)
+		finished = append(finished, job.ID)
+	}
+	assert.ElementsMatch(t, []string{"j1", "j2", "j4"}, finished)
+}
@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// ProcessingScheduler sits between ImageProcessHandler and the worker. Queued
+// images are started while fewer than concurrency executions are running,
+// highest priority first; within a priority, workspaces with images queued
+// take turns, so a bulk import in one workspace does not hold back single
+// uploads in the others.
+//
+// The concurrency cap is a soft limit: running executions are counted outside
+// any transaction, so instances polling at the same time can together start
+// up to one batch each beyond it. Each queue entry is still started at most
+// once.
+type ProcessingScheduler struct {
+	uow          port.UnitOfWorkFactory
+	queue        port.ProcessingQueueRepository
+	jobRepo      port.ProcessingJobRepository
+	worker       port.ImageProcessingWorker
+	concurrency  int
+	pollInterval time.Duration
+	logger       *slog.Logger
+	stop         chan struct{}
+
+	// Serializes round assignment within this instance; entries of one
+	// workspace enqueued concurrently by several instances may share a round
+	enqueueMu sync.Mutex
+}
+
+func NewProcessingScheduler(
+	uow port.UnitOfWorkFactory,
+	queue port.ProcessingQueueRepository,
+	jobRepo port.ProcessingJobRepository,
+	worker port.ImageProcessingWorker,
+	concurrency int,
+	pollInterval time.Duration,
+	logger *slog.Logger,
+) *ProcessingScheduler {
+	return &ProcessingScheduler{
+		uow:          uow,
+		queue:        queue,
+		jobRepo:      jobRepo,
+		worker:       worker,
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		logger:       logger,
+		stop:         make(chan struct{}),
+	}
+}
+
+func (s *ProcessingScheduler) Start(ctx context.Context) error {
+	s.logger.Info("ProcessingScheduler started",
+		slog.Duration("poll_interval", s.pollInterval),
+		slog.Int("concurrency", s.concurrency))
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.stop:
+			return nil
+		case <-ticker.C:
+			s.Poll(ctx)
+		}
+	}
+}
+
+func (s *ProcessingScheduler) Stop() error {
+	s.logger.Info("ProcessingScheduler stopping...")
+	close(s.stop)
+	return nil
+}
+
+// Enqueue adds or replaces the image's entry. The entry gets the round after
+// the workspace's last queued one, but no earlier than the round currently
+// served, so a workspace without queued images goes next.
+func (s *ProcessingScheduler) Enqueue(ctx context.Context, entry *model.ProcessingQueueEntry) error {
+	if !entry.Priority.IsValid() {
+		entry.Priority = vobj.PriorityNormal
+	}
+
+	s.enqueueMu.Lock()
+	defer s.enqueueMu.Unlock()
+
+	first, wsLast, ok, err := s.queue.RoundRange(ctx, entry.Priority, entry.WsID)
+	if err != nil {
+		return err
+	}
+	entry.Round = 0
+	if ok {
+		entry.Round = first
+	}
+	if wsLast >= entry.Round {
+		entry.Round = wsLast + 1
+	}
+	entry.EnqueuedAt = time.Now()
+
+	if err := s.queue.Save(ctx, entry); err != nil {
+		return err
+	}
+
+	s.logger.Info("ProcessingScheduler: image queued",
+		slog.String("image_id", entry.ImageID),
+		slog.String("ws_id", entry.WsID),
+		slog.String("priority", entry.Priority.String()),
+		slog.Int64("round", entry.Round))
+	return nil
+}
+
+// Poll starts as many queued images as the concurrency cap allows, going by
+// this instance's count of running executions.
+func (s *ProcessingScheduler) Poll(ctx context.Context) {
+	running, err := s.jobRepo.CountRunning(ctx)
+	if err != nil {
+		s.logger.Error("ProcessingScheduler: failed to count running jobs", slog.String("error", err.Error()))
+		return
+	}
+	free := s.concurrency - running
+	if free <= 0 {
+		return
+	}
+
+	entries, err := s.queue.ListNext(ctx, free)
+	if err != nil {
+		s.logger.Error("ProcessingScheduler: failed to list queue", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, entry := range entries {
+		if err := s.dispatch(ctx, entry); err != nil {
+			s.logger.Error("ProcessingScheduler: failed to start image",
+				slog.String("image_id", entry.ImageID),
+				slog.String("event_id", entry.EventID),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// dispatch takes the entry off the queue and starts its execution. Entries
+// whose image moved on since they were queued are dropped. The entry is
+// re-read in the transaction, so when several instances list the same entry,
+// or the transaction is retried, only the one that deletes it starts the image.
+func (s *ProcessingScheduler) dispatch(ctx context.Context, entry *model.ProcessingQueueEntry) error {
+	logger := s.logger.With(
+		slog.String("image_id", entry.ImageID),
+		slog.String("event_id", entry.EventID))
+
+	var origin *model.Content
+	var image *model.Image
+	start := false
+
+	err := s.uow.WithTx(ctx, func(txCtx context.Context) error {
+		start = false
+
+		current, err := s.queue.Read(txCtx, entry.ImageID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				logger.Info("ProcessingScheduler: queue entry already taken")
+				return nil
+			}
+			return err
+		}
+		if current.EventID != entry.EventID {
+			// Replaced by a newer request, which keeps its place in the queue
+			logger.Info("ProcessingScheduler: queue entry was replaced")
+			return nil
+		}
+
+		imageRepo := s.uow.GetImageRepo()
+		image, err = imageRepo.Read(txCtx, entry.ImageID)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		origin, err = s.uow.GetContentRepo().Read(txCtx, entry.ContentID)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+
+		// Writes must come after all reads.
+		if err := s.queue.Delete(txCtx, entry.ImageID); err != nil {
+			return err
+		}
+
+		if image == nil || image.IsDeleted() || image.Processing == nil ||
+			image.Processing.ActiveEventID != entry.EventID {
+			logger.Info("ProcessingScheduler: dropping stale queue entry")
+			return nil
+		}
+		if image.Processing.Status != vobj.StatusQueued {
+			logger.Info("ProcessingScheduler: image is no longer queued, dropping queue entry",
+				slog.String("status", image.Processing.Status.String()))
+			return nil
+		}
+
+		info := *image.Processing
+		if err := info.MarkAsProcessing(); err != nil {
+			logger.Info("ProcessingScheduler: image can no longer be processed, dropping queue entry",
+				slog.String("status", info.Status.String()))
+			return nil
+		}
+
+		if origin == nil {
+			if err := info.MarkAsFailed("origin content is missing"); err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{
+			fields.ImageProcessingStatus.DomainName(): info.Status,
+			// Start of the processing deadline the watchdog enforces
+			fields.ImageProcessingLastProcessedAt.DomainName(): info.LastProcessedAt,
+		}
+		if info.FailureReason != nil {
+			updates[fields.ImageProcessingFailureReason.DomainName()] = *info.FailureReason
+		}
+		if err := imageRepo.Update(txCtx, image.ID, updates); err != nil {
+			return err
+		}
+
+		image.Processing = &info
+		if err := s.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(image, updates)); err != nil {
+			return err
+		}
+
+		start = info.Status == vobj.StatusProcessing
+		return nil
+	})
+	if err != nil || !start {
+		return err
+	}
+
+	version := entry.ProcessingVersion
+	if !version.IsValid() {
+		version = vobj.ProcessingV2
+	}
+	profile := entry.ProcessingProfile
+	if !profile.IsValid() {
+		profile = vobj.ProfilePyramid
+	}
+
+	execution, err := s.worker.ProcessImage(port.WithProcessingEventID(ctx, entry.EventID), *origin, version, profile)
+	if err != nil {
+		// The entry is gone; the watchdog retries the failed image
+		return s.failStart(ctx, entry, fmt.Sprintf("failed to start execution: %s", err))
+	}
+
+	logger.Info("ProcessingScheduler: image started",
+		slog.String("execution", execution.Name),
+		slog.String("priority", entry.Priority.String()),
+		slog.Duration("waited", time.Since(entry.EnqueuedAt)))
+
+	// The execution is already running, so a failed record only loses tracking
+	queuedAt := entry.EnqueuedAt
+	job := &model.ProcessingJob{
+		ImageID:           entry.ImageID,
+		WsID:              image.WsID,
+		Format:            image.Format,
+		EventID:           entry.EventID,
+		ProcessingVersion: version,
+		ProcessingProfile: profile,
+		Backend:           execution.Backend,
+		ExecutionName:     execution.Name,
+		Tier:              execution.Tier,
+		Attempt:           image.Processing.RetryCount + 1,
+		Status:            model.ProcessingJobRunning,
+		QueuedAt:          &queuedAt,
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		logger.Warn("ProcessingScheduler: failed to record processing job",
+			slog.String("execution", execution.Name),
+			slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+func (s *ProcessingScheduler) failStart(ctx context.Context, entry *model.ProcessingQueueEntry, reason string) error {
+	return s.uow.WithTx(ctx, func(txCtx context.Context) error {
+		imageRepo := s.uow.GetImageRepo()
+		image, err := imageRepo.Read(txCtx, entry.ImageID)
+		if err != nil {
+			return err
+		}
+		if image.Processing == nil ||
+			image.Processing.ActiveEventID != entry.EventID ||
+			image.Processing.Status != vobj.StatusProcessing {
+			return nil
+		}
+
+		info := *image.Processing
+		if err := info.MarkAsFailed(reason); err != nil {
+			return err
+		}
+
+		s.logger.Warn("ProcessingScheduler: marking image failed",
+			slog.String("image_id", entry.ImageID),
+			slog.String("reason", reason))
+
+		updates := map[string]interface{}{
+			fields.ImageProcessingStatus.DomainName():          info.Status,
+			fields.ImageProcessingFailureReason.DomainName():   reason,
+			fields.ImageProcessingLastProcessedAt.DomainName(): info.LastProcessedAt,
+		}
+		if err := imageRepo.Update(txCtx, entry.ImageID, updates); err != nil {
+			return err
+		}
+
+		image.Processing = &info
+		return s.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(image, updates))
+	})
+}
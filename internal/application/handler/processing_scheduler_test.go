@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueueRepo struct {
+	entries map[string]*model.ProcessingQueueEntry
+}
+
+func newFakeQueueRepo() *fakeQueueRepo {
+	return &fakeQueueRepo{entries: map[string]*model.ProcessingQueueEntry{}}
+}
+
+func (r *fakeQueueRepo) Save(ctx context.Context, entry *model.ProcessingQueueEntry) error {
+	r.entries[entry.ImageID] = entry
+	return nil
+}
+
+func (r *fakeQueueRepo) Read(ctx context.Context, imageID string) (*model.ProcessingQueueEntry, error) {
+	entry, ok := r.entries[imageID]
+	if !ok {
+		return nil, errors.NewNotFoundError("document not found")
+	}
+	return entry, nil
+}
+
+func (r *fakeQueueRepo) Delete(ctx context.Context, imageID string) error {
+	delete(r.entries, imageID)
+	return nil
+}
+
+func (r *fakeQueueRepo) ListNext(ctx context.Context, limit int) ([]*model.ProcessingQueueEntry, error) {
+	entries := []*model.ProcessingQueueEntry{}
+	for _, entry := range r.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return servedBefore(entries[i], entries[j]) })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (r *fakeQueueRepo) RoundRange(ctx context.Context, priority vobj.ProcessingPriority, wsID string) (int64, int64, bool, error) {
+	first, wsLast, ok := int64(0), int64(-1), false
+	for _, entry := range r.entries {
+		if entry.Priority != priority {
+			continue
+		}
+		if !ok || entry.Round < first {
+			first = entry.Round
+		}
+		ok = true
+		if entry.WsID == wsID && entry.Round > wsLast {
+			wsLast = entry.Round
+		}
+	}
+	return first, wsLast, ok, nil
+}
+
+func (r *fakeQueueRepo) CountAhead(ctx context.Context, entry *model.ProcessingQueueEntry) (int, error) {
+	n := 0
+	for _, other := range r.entries {
+		if servedBefore(other, entry) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func servedBefore(a, b *model.ProcessingQueueEntry) bool {
+	if a.Priority.Rank() != b.Priority.Rank() {
+		return a.Priority.Rank() > b.Priority.Rank()
+	}
+	if a.Round != b.Round {
+		return a.Round < b.Round
+	}
+	return a.EnqueuedAt.Before(b.EnqueuedAt)
+}
+
+type fakeStartingWorker struct {
+	port.ImageProcessingWorker
+	started []string
+}
+
+func (w *fakeStartingWorker) ProcessImage(ctx context.Context, content model.Content, version vobj.ProcessingVersion, profile vobj.ProcessingProfile) (*port.WorkerExecution, error) {
+	w.started = append(w.started, content.ID)
+	return &port.WorkerExecution{Backend: "local", Name: "exec-" + content.ID}, nil
+}
+
+type failingStartWorker struct {
+	port.ImageProcessingWorker
+}
+
+func (failingStartWorker) ProcessImage(ctx context.Context, content model.Content, version vobj.ProcessingVersion, profile vobj.ProcessingProfile) (*port.WorkerExecution, error) {
+	return nil, fmt.Errorf("quota exceeded")
+}
+
+func newTestScheduler(uow *fakeUOW, jobs *fakeProcessingJobRepo, worker port.ImageProcessingWorker, concurrency int) *ProcessingScheduler {
+	return NewProcessingScheduler(uow, uow.queue, jobs, worker, concurrency, 0,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestProcessingScheduler_WorkspacesTakeTurns(t *testing.T) {
+	uow := &fakeUOW{queue: newFakeQueueRepo()}
+	scheduler := newTestScheduler(uow, &fakeProcessingJobRepo{}, nil, 1)
+	ctx := context.Background()
+
+	for _, id := range []string{"bulk-1", "bulk-2", "bulk-3"} {
+		require.NoError(t, scheduler.Enqueue(ctx, &model.ProcessingQueueEntry{ImageID: id, WsID: "ws-bulk"}))
+	}
+	single := &model.ProcessingQueueEntry{ImageID: "single", WsID: "ws-other"}
+	require.NoError(t, scheduler.Enqueue(ctx, single))
+	urgent := &model.ProcessingQueueEntry{ImageID: "urgent", WsID: "ws-bulk", Priority: vobj.PriorityHigh}
+	require.NoError(t, scheduler.Enqueue(ctx, urgent))
+
+	next, err := uow.queue.ListNext(ctx, 10)
+	require.NoError(t, err)
+	order := []string{}
+	for _, entry := range next {
+		order = append(order, entry.ImageID)
+	}
+	assert.Equal(t, []string{"urgent", "bulk-1", "single", "bulk-2", "bulk-3"}, order)
+	assert.Equal(t, vobj.PriorityNormal, single.Priority)
+
+	ahead, err := uow.queue.CountAhead(ctx, single)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+}
+
+func TestProcessingScheduler_StartsUpToConcurrency(t *testing.T) {
+	queued := func(eventID string) *model.Image {
+		return &model.Image{Processing: &vobj.ProcessingInfo{Status: vobj.StatusQueued, ActiveEventID: eventID, RetryCount: 1}}
+	}
+	images := &fakeImageRepo{
+		images: map[string]*model.Image{
+			"img-1":     queued("evt-1"),
+			"img-2":     queued("evt-2"),
+			"img-3":     queued("evt-3"),
+			"img-stale": queued("evt-newer"),
+		},
+		updates: map[string]map[string]interface{}{},
+	}
+	for id, image := range images.images {
+		image.ID = id
+	}
+	contents := &fakeContentRepo{contents: map[string]*model.Content{}}
+	for _, id := range []string{"origin-1", "origin-2", "origin-3", "origin-stale"} {
+		contents.contents[id] = &model.Content{Entity: vobj.Entity{ID: id}}
+	}
+	uow := &fakeUOW{images: images, contents: contents, outbox: &fakeOutboxRepo{}, queue: newFakeQueueRepo()}
+	jobs := &fakeProcessingJobRepo{running: []*model.ProcessingJob{{ID: "busy"}}}
+	worker := &fakeStartingWorker{}
+	scheduler := newTestScheduler(uow, jobs, worker, 3)
+
+	ctx := context.Background()
+	for _, id := range []string{"stale", "1", "2", "3"} {
+		eventID := "evt-" + id
+		require.NoError(t, scheduler.Enqueue(ctx, &model.ProcessingQueueEntry{
+			ImageID: "img-" + id, WsID: "ws-" + id, EventID: eventID, ContentID: "origin-" + id,
+		}))
+	}
+
+	// Two free slots: the stale entry is dropped and takes one of them
+	scheduler.Poll(ctx)
+
+	assert.Equal(t, []string{"origin-1"}, worker.started)
+	assert.NotContains(t, uow.queue.entries, "img-stale")
+	assert.NotContains(t, images.updates, "img-stale")
+	assert.Equal(t, vobj.StatusProcessing, images.updates["img-1"][fields.ImageProcessingStatus.DomainName()])
+	require.Len(t, jobs.created, 1)
+	assert.Equal(t, "evt-1", jobs.created[0].EventID)
+	assert.Equal(t, 2, jobs.created[0].Attempt)
+	assert.Len(t, uow.queue.entries, 2)
+
+	jobs.running = append(jobs.running, jobs.created...)
+	scheduler.Poll(ctx)
+	assert.Equal(t, []string{"origin-1", "origin-2"}, worker.started, "one slot was left")
+}
+
+func TestProcessingScheduler_StartsEachEntryOnce(t *testing.T) {
+	images := &fakeImageRepo{
+		images: map[string]*model.Image{
+			"img-1":       {Entity: vobj.Entity{ID: "img-1"}, Processing: &vobj.ProcessingInfo{Status: vobj.StatusQueued, ActiveEventID: "evt-1"}},
+			"img-running": {Entity: vobj.Entity{ID: "img-running"}, Processing: &vobj.ProcessingInfo{Status: vobj.StatusProcessing, ActiveEventID: "evt-running"}},
+		},
+		updates: map[string]map[string]interface{}{},
+	}
+	contents := &fakeContentRepo{contents: map[string]*model.Content{
+		"origin-1":       {Entity: vobj.Entity{ID: "origin-1"}},
+		"origin-running": {Entity: vobj.Entity{ID: "origin-running"}},
+	}}
+	uow := &fakeUOW{images: images, contents: contents, outbox: &fakeOutboxRepo{}, queue: newFakeQueueRepo()}
+	worker := &fakeStartingWorker{}
+	scheduler := newTestScheduler(uow, &fakeProcessingJobRepo{}, worker, 10)
+
+	ctx := context.Background()
+	entry := &model.ProcessingQueueEntry{ImageID: "img-1", WsID: "ws-1", EventID: "evt-1", ContentID: "origin-1"}
+	require.NoError(t, scheduler.Enqueue(ctx, entry))
+
+	// Two instances listed the same entry
+	require.NoError(t, scheduler.dispatch(ctx, entry))
+	require.NoError(t, scheduler.dispatch(ctx, entry))
+	assert.Equal(t, []string{"origin-1"}, worker.started)
+
+	// An entry replaced by a newer request stays queued
+	newer := &model.ProcessingQueueEntry{ImageID: "img-1", WsID: "ws-1", EventID: "evt-2", ContentID: "origin-1"}
+	require.NoError(t, scheduler.Enqueue(ctx, newer))
+	require.NoError(t, scheduler.dispatch(ctx, entry))
+	assert.Contains(t, uow.queue.entries, "img-1")
+
+	// Images already processing are not started again
+	running := &model.ProcessingQueueEntry{ImageID: "img-running", WsID: "ws-1", EventID: "evt-running", ContentID: "origin-running"}
+	require.NoError(t, scheduler.Enqueue(ctx, running))
+	require.NoError(t, scheduler.dispatch(ctx, running))
+	assert.Equal(t, []string{"origin-1"}, worker.started)
+	assert.NotContains(t, uow.queue.entries, "img-running")
+	assert.NotContains(t, images.updates, "img-running")
+}
+
+func TestProcessingScheduler_FailedStartRecordsEvent(t *testing.T) {
+	images := &fakeImageRepo{
+		images: map[string]*model.Image{
+			"img-1": {Entity: vobj.Entity{ID: "img-1", EntityType: vobj.EntityTypeImage}, WsID: "ws-1",
+				Processing: &vobj.ProcessingInfo{Status: vobj.StatusQueued, ActiveEventID: "evt-1"}},
+		},
+		updates: map[string]map[string]interface{}{},
+	}
+	contents := &fakeContentRepo{contents: map[string]*model.Content{"origin-1": {Entity: vobj.Entity{ID: "origin-1"}}}}
+	outbox := &fakeOutboxRepo{}
+	uow := &fakeUOW{images: images, contents: contents, outbox: outbox, queue: newFakeQueueRepo()}
+	scheduler := newTestScheduler(uow, &fakeProcessingJobRepo{}, failingStartWorker{}, 10)
+
+	ctx := context.Background()
+	entry := &model.ProcessingQueueEntry{ImageID: "img-1", WsID: "ws-1", EventID: "evt-1", ContentID: "origin-1"}
+	require.NoError(t, scheduler.Enqueue(ctx, entry))
+	require.NoError(t, scheduler.dispatch(ctx, entry))
+
+	assert.Equal(t, vobj.StatusFailed, images.updates["img-1"][fields.ImageProcessingStatus.DomainName()])
+	// Started, then failed
+	require.Len(t, outbox.added, 2)
+	failed := outbox.added[1].(*domainevent.EntityEvent)
+	assert.Equal(t, "img-1", failed.EntityID)
+	assert.Contains(t, failed.ChangedFields, fields.ImageProcessingFailureReason.DomainName())
+}
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/histopathai/main-service/internal/shared/query"
+)
+
+// Enough to cover every attempt an image can have running at once
+const abandonedJobsLookback = 20
+
+// ProcessingWatchdog sweeps images that need another processing attempt:
+// images stuck in processing past the deadline, failed images with retries
+// left and queued images whose request never reached the queue. Each gets a new ImageProcessReqEvent under a fresh
+// ActiveEventID, so results of the abandoned attempt are ignored. Once the
+// retry budget is spent the image becomes failed_permanent. Executions of a
+// stuck attempt are cancelled where the backend allows it.
+type ProcessingWatchdog struct {
+	uow          port.UnitOfWorkFactory
+	jobRepo      port.ProcessingJobRepository
+	worker       port.ImageProcessingWorker
+	backend      string
+	policy       RetryPolicy
+	deadline     time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	logger       *slog.Logger
+	stop         chan struct{}
+
+	// Pages through queued images across sweeps, so a long queue does not
+	// hide the lost requests behind it
+	queuedCursor time.Time
+}
+
+func NewProcessingWatchdog(
+	uow port.UnitOfWorkFactory,
+	jobRepo port.ProcessingJobRepository,
+	worker port.ImageProcessingWorker,
+	backend string,
+	policy RetryPolicy,
+	deadline time.Duration,
+	pollInterval time.Duration,
+	batchSize int,
+	logger *slog.Logger,
+) *ProcessingWatchdog {
+	return &ProcessingWatchdog{
+		uow:          uow,
+		jobRepo:      jobRepo,
+		worker:       worker,
+		backend:      backend,
+		policy:       policy,
+		deadline:     deadline,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		logger:       logger,
+		stop:         make(chan struct{}),
+	}
+}
+
+func (w *ProcessingWatchdog) Start(ctx context.Context) error {
+	w.logger.Info("ProcessingWatchdog started",
+		slog.Duration("poll_interval", w.pollInterval),
+		slog.Duration("deadline", w.deadline))
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.stop:
+			return nil
+		case <-ticker.C:
+			w.Sweep(ctx)
+		}
+	}
+}
+
+func (w *ProcessingWatchdog) Stop() error {
+	w.logger.Info("ProcessingWatchdog stopping...")
+	close(w.stop)
+	return nil
+}
+
+// Sweep handles one batch each of stuck, failed and queued images.
+func (w *ProcessingWatchdog) Sweep(ctx context.Context) {
+	now := time.Now()
+
+	stuck, err := w.find(ctx, vobj.StatusProcessing, time.Time{}, now.Add(-w.deadline))
+	if err != nil {
+		w.logger.Error("ProcessingWatchdog: failed to find stuck images", slog.String("error", err.Error()))
+	}
+	for _, image := range stuck {
+		w.recover(ctx, image)
+	}
+
+	failed, err := w.find(ctx, vobj.StatusFailed, time.Time{}, now.Add(-w.policy.BaseBackoff))
+	if err != nil {
+		w.logger.Error("ProcessingWatchdog: failed to find failed images", slog.String("error", err.Error()))
+	}
+	for _, image := range failed {
+		// Later retries wait longer
+		if now.Sub(image.Processing.LastProcessedAt) < w.policy.Backoff(image.Processing.RetryCount+1) {
+			continue
+		}
+		w.recover(ctx, image)
+	}
+
+	// Queued images may wait longer than the deadline; only those without a
+	// queue entry are lost
+	queued, err := w.find(ctx, vobj.StatusQueued, w.queuedCursor, now.Add(-w.deadline))
+	if err != nil {
+		w.logger.Error("ProcessingWatchdog: failed to find queued images", slog.String("error", err.Error()))
+		return
+	}
+	w.queuedCursor = time.Time{}
+	if len(queued) == w.batchSize {
+		w.queuedCursor = queued[len(queued)-1].Processing.LastProcessedAt
+	}
+	for _, image := range queued {
+		_, err := w.uow.GetProcessingQueueRepo().Read(ctx, image.ID)
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			w.logger.Error("ProcessingWatchdog: failed to read queue entry",
+				slog.String("image_id", image.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		w.recover(ctx, image)
+	}
+}
+
+// find returns images in status whose last transition lies between after
+// (exclusive, ignored when zero) and before, oldest first.
+func (w *ProcessingWatchdog) find(ctx context.Context, status vobj.ImageStatus, after, before time.Time) ([]*model.Image, error) {
+	builder := query.NewBuilder().
+		WhereEqual(fields.ImageProcessingStatus.DomainName(), status.String()).
+		Where(fields.ImageProcessingLastProcessedAt.DomainName(), query.OpLessThan, before)
+	if !after.IsZero() {
+		builder = builder.Where(fields.ImageProcessingLastProcessedAt.DomainName(), query.OpGreaterThan, after)
+	}
+	spec := builder.
+		OrderByAsc(fields.ImageProcessingLastProcessedAt.DomainName()).
+		Limit(w.batchSize).
+		Build()
+
+	result, err := w.uow.GetImageRepo().Find(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]*model.Image, 0, len(result.Data))
+	for _, image := range result.Data {
+		if image.Processing != nil && !image.IsDeleted() {
+			images = append(images, image)
+		}
+	}
+	return images, nil
+}
+
+func (w *ProcessingWatchdog) recover(ctx context.Context, image *model.Image) {
+	logger := w.logger.With(slog.String("image_id", image.ID))
+
+	wasProcessing, attemptID := image.Processing.Status == vobj.StatusProcessing, image.Processing.ActiveEventID
+
+	request, updated, err := w.retry(ctx, image)
+	if err != nil {
+		logger.Error("ProcessingWatchdog: failed to update image", slog.String("error", err.Error()))
+		return
+	}
+	if updated && wasProcessing {
+		w.closeAbandoned(ctx, logger, image.ID, attemptID)
+	}
+	if request == nil {
+		return
+	}
+
+	logger.Info("ProcessingWatchdog: image re-queued for processing",
+		slog.String("event_id", request.EventID))
+}
+
+// retry fails the image's current attempt and re-queues it or gives up. It
+// reports whether the image was updated, which it is not when the image moved
+// on since it was found.
+func (w *ProcessingWatchdog) retry(ctx context.Context, found *model.Image) (*domainevent.ImageProcessReqEvent, bool, error) {
+	var request *domainevent.ImageProcessReqEvent
+	var updated bool
+
+	err := w.uow.WithTx(ctx, func(txCtx context.Context) error {
+		request, updated = nil, false
+
+		imageRepo := w.uow.GetImageRepo()
+		image, err := imageRepo.Read(txCtx, found.ID)
+		if err != nil {
+			return err
+		}
+
+		// Picked up by a new request or finished since the query ran
+		if image.Processing == nil ||
+			image.Processing.Status != found.Processing.Status ||
+			image.Processing.ActiveEventID != found.Processing.ActiveEventID {
+			return nil
+		}
+
+		var origin *model.Content
+		if image.OriginContentID != nil {
+			origin, err = w.uow.GetContentRepo().Read(txCtx, *image.OriginContentID)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+
+		info := *image.Processing
+		switch info.Status {
+		case vobj.StatusProcessing:
+			if err := info.MarkAsFailed(fmt.Sprintf("processing did not finish within %s", w.deadline)); err != nil {
+				return err
+			}
+		case vobj.StatusQueued:
+			if err := info.MarkAsFailed("processing request was lost before it was queued"); err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{}
+		switch {
+		case origin == nil:
+			err = info.MarkAsFailedPermanent("origin content is missing")
+		case info.IsRetryable(w.policy.MaxAttempts):
+			err = info.MarkForRetry()
+			info.ActiveEventID = uuid.New().String()
+			updates[fields.ImageProcessingRetryCount.DomainName()] = info.RetryCount
+			updates[fields.ImageProcessingActiveEventID.DomainName()] = info.ActiveEventID
+		default:
+			err = info.MarkAsFailedPermanent(fmt.Sprintf("gave up after %d retries: %s", info.RetryCount, failureReason(&info)))
+		}
+		if err != nil {
+			return err
+		}
+		updates[fields.ImageProcessingStatus.DomainName()] = info.Status
+		updates[fields.ImageProcessingLastProcessedAt.DomainName()] = info.LastProcessedAt
+		if info.FailureReason != nil {
+			updates[fields.ImageProcessingFailureReason.DomainName()] = *info.FailureReason
+		}
+
+		if err := imageRepo.Update(txCtx, image.ID, updates); err != nil {
+			return err
+		}
+		updated = true
+
+		image.Processing = &info
+		if err := w.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(image, updates)); err != nil {
+			return err
+		}
+
+		if info.Status != vobj.StatusQueued {
+			w.logger.Warn("ProcessingWatchdog: image failed permanently",
+				slog.String("image_id", image.ID),
+				slog.String("reason", *info.FailureReason))
+			return nil
+		}
+
+		version := info.Version
+		if !version.IsValid() {
+			version = vobj.ProcessingV2
+		}
+		request = &domainevent.ImageProcessReqEvent{
+			BaseEvent: domainevent.BaseEvent{
+				EventID:   info.ActiveEventID,
+				EventType: domainevent.ImageProcessReqEventType,
+				Timestamp: time.Now(),
+			},
+			Content:           *origin,
+			ProcessingVersion: version,
+			ProcessingProfile: info.Profile,
+		}
+		return w.uow.GetOutboxRepo().Add(txCtx, request)
+	})
+
+	return request, updated, err
+}
+
+// closeAbandoned cancels the running executions of an attempt the watchdog
+// gave up on and closes their job records, so they stop counting towards the
+// scheduler's concurrency. It is best effort: the rotated ActiveEventID
+// already marks whatever they still report as stale.
+func (w *ProcessingWatchdog) closeAbandoned(ctx context.Context, logger *slog.Logger, imageID string, eventID string) {
+	jobs, err := w.jobRepo.ListByImage(ctx, imageID, abandonedJobsLookback)
+	if err != nil {
+		logger.Error("ProcessingWatchdog: failed to list executions", slog.String("error", err.Error()))
+		return
+	}
+
+	canceller, canCancel := w.worker.(port.ExecutionCanceller)
+	for _, job := range jobs {
+		if job.Status != model.ProcessingJobRunning || job.EventID != eventID {
+			continue
+		}
+
+		if canCancel && job.Backend == w.backend {
+			if err := canceller.CancelExecution(ctx, job.ExecutionName); err != nil {
+				logger.Warn("ProcessingWatchdog: failed to cancel execution",
+					slog.String("execution", job.ExecutionName),
+					slog.String("error", err.Error()))
+			}
+		}
+
+		job.Finish(model.ProcessingJobCancelled, fmt.Sprintf("did not finish within %s", w.deadline))
+		if err := w.jobRepo.Update(ctx, job); err != nil {
+			logger.Error("ProcessingWatchdog: failed to close job",
+				slog.String("job_id", job.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+func failureReason(info *vobj.ProcessingInfo) string {
+	if info.FailureReason == nil || *info.FailureReason == "" {
+		return "unknown error"
+	}
+	return *info.FailureReason
+}
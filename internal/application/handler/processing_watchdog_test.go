@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func watchdogImage(id string, status vobj.ImageStatus, retries int, last time.Time) *model.Image {
+	origin := "origin-" + id
+	return &model.Image{
+		Entity:          vobj.Entity{ID: id},
+		OriginContentID: &origin,
+		Processing: &vobj.ProcessingInfo{
+			Status:          status,
+			Version:         vobj.ProcessingV2,
+			RetryCount:      retries,
+			LastProcessedAt: last,
+			ActiveEventID:   "evt-" + id,
+		},
+	}
+}
+
+type fakeCancellingWorker struct {
+	port.ImageProcessingWorker
+	cancelled []string
+}
+
+func (w *fakeCancellingWorker) CancelExecution(ctx context.Context, executionName string) error {
+	w.cancelled = append(w.cancelled, executionName)
+	return nil
+}
+
+func TestProcessingWatchdog_RetriesUntilBudgetIsSpent(t *testing.T) {
+	longAgo := time.Now().Add(-5 * time.Hour)
+	images := &fakeImageRepo{
+		images: map[string]*model.Image{
+			"stuck":     watchdogImage("stuck", vobj.StatusProcessing, 0, longAgo),
+			"fresh":     watchdogImage("fresh", vobj.StatusProcessing, 0, time.Now()),
+			"failed":    watchdogImage("failed", vobj.StatusFailed, 1, longAgo),
+			"exhausted": watchdogImage("exhausted", vobj.StatusFailed, 3, longAgo),
+			"lost":      watchdogImage("lost", vobj.StatusQueued, 0, longAgo),
+			"waiting":   watchdogImage("waiting", vobj.StatusQueued, 0, longAgo),
+		},
+		updates: map[string]map[string]interface{}{},
+	}
+	contents := &fakeContentRepo{contents: map[string]*model.Content{}}
+	for id := range images.images {
+		contents.contents["origin-"+id] = &model.Content{Entity: vobj.Entity{ID: "origin-" + id}, Path: id + ".svs"}
+	}
+	outbox := &fakeOutboxRepo{}
+	queue := newFakeQueueRepo()
+	queue.entries["waiting"] = &model.ProcessingQueueEntry{ImageID: "waiting", EventID: "evt-waiting"}
+
+	jobs := &fakeProcessingJobRepo{running: []*model.ProcessingJob{
+		{ID: "job-stuck", ImageID: "stuck", EventID: "evt-stuck", Backend: "cloudrun", ExecutionName: "exec-stuck", Status: model.ProcessingJobRunning},
+		{ID: "job-stuck-local", ImageID: "stuck", EventID: "evt-stuck", Backend: "local", ExecutionName: "exec-stuck-local", Status: model.ProcessingJobRunning},
+		{ID: "job-stuck-old", ImageID: "stuck", EventID: "evt-older", Backend: "cloudrun", ExecutionName: "exec-stuck-old", Status: model.ProcessingJobRunning},
+		{ID: "job-fresh", ImageID: "fresh", EventID: "evt-fresh", Backend: "cloudrun", ExecutionName: "exec-fresh", Status: model.ProcessingJobRunning},
+	}}
+	worker := &fakeCancellingWorker{}
+
+	watchdog := NewProcessingWatchdog(
+		&fakeUOW{images: images, contents: contents, outbox: outbox, queue: queue},
+		jobs, worker, "cloudrun",
+		RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Minute, Multiplier: 2},
+		3*time.Hour, time.Minute, 10,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	watchdog.Sweep(context.Background())
+
+	stuck := images.updates["stuck"]
+	assert.Equal(t, vobj.StatusQueued, stuck[fields.ImageProcessingStatus.DomainName()])
+	assert.Equal(t, 1, stuck[fields.ImageProcessingRetryCount.DomainName()])
+	assert.Contains(t, stuck[fields.ImageProcessingFailureReason.DomainName()], "did not finish within 3h0m0s")
+	assert.NotEqual(t, "evt-stuck", stuck[fields.ImageProcessingActiveEventID.DomainName()])
+
+	// The stuck attempt is stopped and stops counting towards the concurrency
+	assert.Equal(t, []string{"exec-stuck"}, worker.cancelled)
+	var closed []string
+	for _, job := range jobs.updated {
+		assert.Equal(t, model.ProcessingJobCancelled, job.Status)
+		closed = append(closed, job.ID)
+	}
+	assert.ElementsMatch(t, []string{"job-stuck", "job-stuck-local"}, closed)
+
+	failed := images.updates["failed"]
+	assert.Equal(t, vobj.StatusQueued, failed[fields.ImageProcessingStatus.DomainName()])
+	assert.Equal(t, 2, failed[fields.ImageProcessingRetryCount.DomainName()])
+
+	exhausted := images.updates["exhausted"]
+	assert.Equal(t, vobj.StatusFailedPermanent, exhausted[fields.ImageProcessingStatus.DomainName()])
+	assert.NotContains(t, exhausted, fields.ImageProcessingActiveEventID.DomainName())
+
+	// Queued images only count as lost without a queue entry
+	lost := images.updates["lost"]
+	assert.Equal(t, vobj.StatusQueued, lost[fields.ImageProcessingStatus.DomainName()])
+	assert.Equal(t, 1, lost[fields.ImageProcessingRetryCount.DomainName()])
+
+	assert.NotContains(t, images.updates, "fresh")
+	assert.NotContains(t, images.updates, "waiting")
+	// Four status updates, plus a request for each image sent back to the queue
+	var requests []*domainevent.ImageProcessReqEvent
+	for _, event := range outbox.added {
+		if request, ok := event.(*domainevent.ImageProcessReqEvent); ok {
+			requests = append(requests, request)
+		}
+	}
+	assert.Len(t, outbox.added, 7)
+
+	// Requests carry the new active event ID so the handler accepts them
+	require.Len(t, requests, 3)
+	for _, request := range requests {
+		imageID := request.Content.Path[:len(request.Content.Path)-len(".svs")]
+		assert.Equal(t, images.updates[imageID][fields.ImageProcessingActiveEventID.DomainName()], request.EventID)
+		assert.Equal(t, vobj.ProcessingV2, request.ProcessingVersion)
+	}
+}
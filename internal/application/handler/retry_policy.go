@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"math"
+	"time"
+)
+
+// RetryPolicy is an exponential backoff schedule, used for webhook deliveries
+// and image processing retries.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Multiplier  float64
+}
+
+// Backoff returns the wait before the next attempt after `attempts` failures.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := float64(p.BaseBackoff) * math.Pow(p.Multiplier, float64(attempts-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
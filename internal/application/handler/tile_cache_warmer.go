@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/histopathai/main-service/internal/application/proxy"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
+
+// TileCacheWarmer warms the tile server caches of images as they finish
+// processing, so the first viewer does not pay for cold reads. Only the
+// caches of the instance running the outbox relay are warmed.
+type TileCacheWarmer struct {
+	prefetcher *proxy.Prefetcher
+	logger     *slog.Logger
+}
+
+func NewTileCacheWarmer(prefetcher *proxy.Prefetcher, logger *slog.Logger) *TileCacheWarmer {
+	return &TileCacheWarmer{
+		prefetcher: prefetcher,
+		logger:     logger,
+	}
+}
+
+func (w *TileCacheWarmer) Handle(ctx context.Context, event domainevent.Event) error {
+	entityEvent, ok := event.(*domainevent.EntityEvent)
+	if !ok || entityEvent.EntityType != vobj.EntityTypeImage || entityEvent.Action != domainevent.EntityProcessed {
+		return nil
+	}
+
+	// Warming is a best effort and must not hold up the relay
+	if !w.prefetcher.Warm(entityEvent.EntityID) {
+		w.logger.Warn("TileCacheWarmer: prefetch queue full, image not warmed",
+			slog.String("image_id", entityEvent.EntityID))
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/histopathai/main-service/internal/shared/netguard"
+)
+
+const (
+	WebhookHeaderEvent     = "X-Histopath-Event"
+	WebhookHeaderDelivery  = "X-Histopath-Delivery"
+	WebhookHeaderTimestamp = "X-Histopath-Timestamp"
+	WebhookHeaderSignature = "X-Histopath-Signature"
+
+	maxWebhookErrorBody = 512
+)
+
+// SignWebhookPayload returns the signature header value for a delivery:
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)).
+// Receivers recompute it with their copy of the secret and reject
+// mismatches and stale timestamps.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewWebhookHTTPClient returns the client deliveries are sent with. It only
+// connects to public addresses, checked on every dial so a subscription host
+// cannot be re-pointed at an internal service after it was validated.
+func NewWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: netguard.DialControl,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// No proxy: the dialer must see the endpoint's own address
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// WebhookDeliveryWorker POSTs pending deliveries to their subscription URL,
+// retrying failures with exponential backoff until the policy gives up.
+type WebhookDeliveryWorker struct {
+	webhookRepo  port.WebhookRepository
+	deliveryRepo port.WebhookDeliveryRepository
+	client       *http.Client
+	policy       RetryPolicy
+	pollInterval time.Duration
+	batchSize    int
+	logger       *slog.Logger
+	stop         chan struct{}
+}
+
+func NewWebhookDeliveryWorker(
+	webhookRepo port.WebhookRepository,
+	deliveryRepo port.WebhookDeliveryRepository,
+	client *http.Client,
+	policy RetryPolicy,
+	pollInterval time.Duration,
+	batchSize int,
+	logger *slog.Logger,
+) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		client:       client,
+		policy:       policy,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		logger:       logger,
+		stop:         make(chan struct{}),
+	}
+}
+
+func (w *WebhookDeliveryWorker) Start(ctx context.Context) error {
+	w.logger.Info("WebhookDeliveryWorker started", slog.Duration("poll_interval", w.pollInterval))
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.stop:
+			return nil
+		case <-ticker.C:
+			w.deliverDue(ctx)
+		}
+	}
+}
+
+func (w *WebhookDeliveryWorker) Stop() error {
+	w.logger.Info("WebhookDeliveryWorker stopping...")
+	close(w.stop)
+	return nil
+}
+
+func (w *WebhookDeliveryWorker) deliverDue(ctx context.Context) {
+	deliveries, err := w.deliveryRepo.FetchDue(ctx, time.Now(), w.batchSize)
+	if err != nil {
+		w.logger.Error("WebhookDeliveryWorker: failed to fetch due deliveries", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		if err := w.Deliver(ctx, delivery); err != nil {
+			w.logger.Error("WebhookDeliveryWorker: failed to record delivery attempt",
+				slog.String("delivery_id", delivery.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// Deliver makes one attempt and records its outcome on the delivery.
+// The returned error concerns persisting the outcome, not the HTTP call.
+func (w *WebhookDeliveryWorker) Deliver(ctx context.Context, delivery *model.WebhookDelivery) error {
+	subscription, err := w.webhookRepo.Read(ctx, delivery.SubscriptionID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.LastError = "webhook subscription no longer exists"
+			return w.deliveryRepo.Save(ctx, delivery)
+		}
+		return err
+	}
+	if !subscription.Active {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.LastError = "webhook subscription is inactive"
+		return w.deliveryRepo.Save(ctx, delivery)
+	}
+
+	now := time.Now()
+	statusCode, sendErr := w.send(ctx, subscription, delivery, now)
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= w.policy.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = now.Add(w.policy.Backoff(delivery.Attempts))
+	}
+
+	if sendErr != nil {
+		w.logger.Warn("WebhookDeliveryWorker: delivery attempt failed",
+			slog.String("delivery_id", delivery.ID),
+			slog.Int("attempt", delivery.Attempts),
+			slog.String("status", string(delivery.Status)),
+			slog.String("error", sendErr.Error()))
+	}
+
+	return w.deliveryRepo.Save(ctx, delivery)
+}
+
+func (w *WebhookDeliveryWorker) send(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	req.Header.Set(WebhookHeaderEvent, delivery.EventType)
+	req.Header.Set(WebhookHeaderDelivery, delivery.ID)
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBody))
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookRepo struct {
+	subscriptions map[string]*model.WebhookSubscription
+}
+
+func (r *fakeWebhookRepo) Create(ctx context.Context, s *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	r.subscriptions[s.ID] = s
+	return s, nil
+}
+
+func (r *fakeWebhookRepo) Read(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	s, ok := r.subscriptions[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("document not found")
+	}
+	return s, nil
+}
+
+func (r *fakeWebhookRepo) Update(ctx context.Context, s *model.WebhookSubscription) error {
+	r.subscriptions[s.ID] = s
+	return nil
+}
+
+func (r *fakeWebhookRepo) Delete(ctx context.Context, id string) error {
+	delete(r.subscriptions, id)
+	return nil
+}
+
+func (r *fakeWebhookRepo) ListByWorkspace(ctx context.Context, wsID string, activeOnly bool) ([]*model.WebhookSubscription, error) {
+	var out []*model.WebhookSubscription
+	for _, s := range r.subscriptions {
+		if s.WsID == wsID && (!activeOnly || s.Active) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+type fakeDeliveryRepo struct {
+	saved []*model.WebhookDelivery
+}
+
+func (r *fakeDeliveryRepo) Save(ctx context.Context, d *model.WebhookDelivery) error {
+	r.saved = append(r.saved, d)
+	return nil
+}
+
+func (r *fakeDeliveryRepo) Read(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	return nil, errors.NewNotFoundError("document not found")
+}
+
+func (r *fakeDeliveryRepo) FetchDue(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (r *fakeDeliveryRepo) ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func newTestDeliveryWorker(url string, maxAttempts int) (*WebhookDeliveryWorker, *fakeDeliveryRepo) {
+	webhooks := &fakeWebhookRepo{subscriptions: map[string]*model.WebhookSubscription{
+		"wh-1": {ID: "wh-1", WsID: "ws-1", URL: url, Secret: "s3cret", Active: true},
+	}}
+	deliveries := &fakeDeliveryRepo{}
+	policy := RetryPolicy{MaxAttempts: maxAttempts, BaseBackoff: time.Second, MaxBackoff: time.Minute, Multiplier: 2}
+	worker := NewWebhookDeliveryWorker(webhooks, deliveries, http.DefaultClient, policy, time.Second, 10, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return worker, deliveries
+}
+
+func newTestDelivery() *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		ID:             "wh-1_evt-1",
+		SubscriptionID: "wh-1",
+		EventID:        "evt-1",
+		EventType:      "image.processed.v1",
+		Payload:        []byte(`{"id":"evt-1"}`),
+		Status:         model.WebhookDeliveryPending,
+	}
+}
+
+func TestWebhookDeliveryWorker_SignsAndRecordsSuccess(t *testing.T) {
+	var gotSignature, gotTimestamp string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookHeaderSignature)
+		gotTimestamp = r.Header.Get(WebhookHeaderTimestamp)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	worker, deliveries := newTestDeliveryWorker(server.URL, 3)
+	delivery := newTestDelivery()
+
+	assert.NoError(t, worker.Deliver(context.Background(), delivery))
+
+	ts, err := strconv.ParseInt(gotTimestamp, 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload("s3cret", ts, gotBody), gotSignature)
+	assert.Equal(t, model.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+	assert.NotNil(t, delivery.DeliveredAt)
+	assert.Len(t, deliveries.saved, 1)
+}
+
+func TestWebhookDeliveryWorker_RetriesWithBackoffThenFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	worker, _ := newTestDeliveryWorker(server.URL, 2)
+	delivery := newTestDelivery()
+
+	before := time.Now()
+	assert.NoError(t, worker.Deliver(context.Background(), delivery))
+	assert.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+	assert.True(t, delivery.NextAttemptAt.After(before.Add(time.Second-time.Millisecond)))
+
+	assert.NoError(t, worker.Deliver(context.Background(), delivery))
+	assert.Equal(t, model.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Contains(t, delivery.LastError, "503")
+}
+
+func TestWebhookHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	worker, _ := newTestDeliveryWorker(server.URL, 3)
+	worker.client = NewWebhookHTTPClient(time.Second)
+	delivery := newTestDelivery()
+
+	assert.NoError(t, worker.Deliver(context.Background(), delivery))
+	assert.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+	assert.Zero(t, delivery.LastStatusCode)
+	assert.Contains(t, delivery.LastError, "non-public address")
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 10 * time.Second, MaxBackoff: time.Minute, Multiplier: 2}
+
+	assert.Equal(t, 10*time.Second, policy.Backoff(1))
+	assert.Equal(t, 20*time.Second, policy.Backoff(2))
+	assert.Equal(t, 40*time.Second, policy.Backoff(3))
+	assert.Equal(t, time.Minute, policy.Backoff(4))
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+)
+
+// WebhookDispatcher turns relayed domain events into pending webhook
+// deliveries for every matching subscription of the event's workspace.
+// The delivery itself is done by WebhookDeliveryWorker.
+type WebhookDispatcher struct {
+	webhookRepo  port.WebhookRepository
+	deliveryRepo port.WebhookDeliveryRepository
+	marshaler    portevent.EventMarshaler
+	logger       *slog.Logger
+}
+
+func NewWebhookDispatcher(
+	webhookRepo port.WebhookRepository,
+	deliveryRepo port.WebhookDeliveryRepository,
+	marshaler portevent.EventMarshaler,
+	logger *slog.Logger,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		marshaler:    marshaler,
+		logger:       logger,
+	}
+}
+
+func (d *WebhookDispatcher) Handle(ctx context.Context, event domainevent.Event) error {
+	entityEvent, ok := event.(*domainevent.EntityEvent)
+	if !ok || entityEvent.WsID == "" {
+		return nil
+	}
+
+	subscriptions, err := d.webhookRepo.ListByWorkspace(ctx, entityEvent.WsID, true)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(string(event.GetEventType())) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = d.marshaler.Marshal(event)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Deterministic ID: a re-relayed event overwrites instead of duplicating
+		delivery := &model.WebhookDelivery{
+			ID:             subscription.ID + "_" + event.GetEventID(),
+			SubscriptionID: subscription.ID,
+			WsID:           entityEvent.WsID,
+			EventID:        event.GetEventID(),
+			EventType:      string(event.GetEventType()),
+			Payload:        payload,
+			Status:         model.WebhookDeliveryPending,
+			NextAttemptAt:  time.Now(),
+		}
+		if err := d.deliveryRepo.Save(ctx, delivery); err != nil {
+			return err
+		}
+
+		d.logger.Debug("WebhookDispatcher: delivery queued",
+			slog.String("delivery_id", delivery.ID),
+			slog.String("event_type", delivery.EventType))
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package proxy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+type accessDecision int
+
+const (
+	accessGranted accessDecision = iota + 1
+	accessDenied
+	accessNotFound
+)
+
+// ImageAccess decides who may read an image's tiles: admins, and the creator
+// and members of the image's workspace. Deleted images and workspaces are
+// not found for everyone. It also issues signed tokens granting short-lived
+// access to a single image without credentials.
+type ImageAccess struct {
+	cache         cache.Cache
+	keyBuilder    *cache.KeyBuilder
+	imageRepo     port.ImageRepository
+	workspaceRepo port.WorkspaceRepository
+	// How long a decision is reused for the same user and image
+	decisionTTL time.Duration
+	// HMAC key of signed tokens; empty disables them
+	signingKey []byte
+	tokenTTL   time.Duration
+}
+
+func NewImageAccess(
+	cache cache.Cache,
+	keyBuilder *cache.KeyBuilder,
+	imageRepo port.ImageRepository,
+	workspaceRepo port.WorkspaceRepository,
+	decisionTTL time.Duration,
+	signingKey []byte,
+	tokenTTL time.Duration,
+) *ImageAccess {
+	return &ImageAccess{
+		cache:         cache,
+		keyBuilder:    keyBuilder,
+		imageRepo:     imageRepo,
+		workspaceRepo: workspaceRepo,
+		decisionTTL:   decisionTTL,
+		signingKey:    signingKey,
+		tokenTTL:      tokenTTL,
+	}
+}
+
+// Authorize checks that userID may read imageID's tiles.
+func (a *ImageAccess) Authorize(ctx context.Context, userID string, admin bool, imageID string) error {
+	role := "user"
+	if admin {
+		role = "admin"
+	}
+	cacheKey := a.keyBuilder.Build("access", role, userID, imageID)
+
+	decision, cached := accessDecision(0), false
+	if val, err := a.cache.Get(ctx, cacheKey); err == nil && val != nil {
+		decision, cached = val.(accessDecision)
+	}
+	if !cached {
+		var err error
+		decision, err = a.decide(ctx, userID, admin, imageID)
+		if err != nil {
+			return err
+		}
+		_ = a.cache.Set(ctx, cacheKey, decision, a.decisionTTL)
+	}
+
+	switch decision {
+	case accessGranted:
+		return nil
+	case accessNotFound:
+		return errors.NewNotFoundError("image not found")
+	default:
+		return errors.NewForbiddenError("no access to the image's workspace")
+	}
+}
+
+func (a *ImageAccess) decide(ctx context.Context, userID string, admin bool, imageID string) (accessDecision, error) {
+	image, err := a.imageRepo.Read(ctx, imageID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return accessNotFound, nil
+		}
+		return 0, errors.NewInternalError("failed to read image", err)
+	}
+	if image.IsDeleted() {
+		return accessNotFound, nil
+	}
+
+	workspace, err := a.workspaceRepo.Read(ctx, image.WsID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return accessNotFound, nil
+		}
+		return 0, errors.NewInternalError("failed to read workspace", err)
+	}
+	if workspace.IsDeleted() {
+		return accessNotFound, nil
+	}
+
+	if admin || workspace.HasMember(userID) {
+		return accessGranted, nil
+	}
+	return accessDenied, nil
+}
+
+// SignedToken is a URL path segment granting access to one image's tiles.
+type SignedToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// Sign issues a token for imageID valid for the configured lifetime. The
+// caller must already be authorized for the image.
+func (a *ImageAccess) Sign(imageID string, now time.Time) (*SignedToken, error) {
+	if len(a.signingKey) == 0 {
+		return nil, errors.NewBadRequestError("signed tile URLs are not enabled", nil)
+	}
+	expiresAt := now.Add(a.tokenTTL).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return &SignedToken{
+		Token:     expires + "." + a.signature(imageID, expires),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyToken checks that token was signed for imageID and has not expired.
+func (a *ImageAccess) VerifyToken(imageID, token string, now time.Time) error {
+	if len(a.signingKey) == 0 {
+		return errors.NewUnauthorizedError("signed tile URLs are not enabled")
+	}
+	expires, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(a.signature(imageID, expires))) {
+		return errors.NewUnauthorizedError("invalid tile URL signature")
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() >= expiresAt {
+		return errors.NewUnauthorizedError("tile URL has expired")
+	}
+	return nil
+}
+
+func (a *ImageAccess) signature(imageID, expires string) string {
+	mac := hmac.New(sha256.New, a.signingKey)
+	mac.Write([]byte(imageID + "\x00" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package proxy
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapCache struct {
+	cache.Cache
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+func (c *mapCache) Get(ctx context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key], nil
+}
+
+func (c *mapCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *mapCache) Delete(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.values[key]
+	delete(c.values, key)
+	return ok, nil
+}
+
+type fakeWorkspaceRepo struct {
+	port.WorkspaceRepository
+	workspace *model.Workspace
+	reads     int
+}
+
+func (r *fakeWorkspaceRepo) Read(ctx context.Context, id string) (*model.Workspace, error) {
+	r.reads++
+	if r.workspace == nil || r.workspace.ID != id {
+		return nil, errors.NewNotFoundError("workspace not found")
+	}
+	return r.workspace, nil
+}
+
+func TestImageAccess_Authorize(t *testing.T) {
+	ctx := context.Background()
+	image := &model.Image{Entity: vobj.Entity{ID: "img-1"}, WsID: "ws-1"}
+	workspaces := &fakeWorkspaceRepo{workspace: &model.Workspace{
+		Entity:  vobj.Entity{ID: "ws-1", CreatorID: "owner"},
+		Members: []string{"member"},
+	}}
+	access := NewImageAccess(&mapCache{values: map[string]interface{}{}}, cache.NewKeyBuilder("test"),
+		&fakeImageRepo{image: image}, workspaces, time.Minute, nil, 0)
+
+	assert.NoError(t, access.Authorize(ctx, "owner", false, "img-1"))
+	assert.NoError(t, access.Authorize(ctx, "member", false, "img-1"))
+	assert.NoError(t, access.Authorize(ctx, "someone", true, "img-1"), "admin")
+
+	err := access.Authorize(ctx, "someone", false, "img-1")
+	assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+
+	// Decisions are reused per user and image
+	reads := workspaces.reads
+	assert.NoError(t, access.Authorize(ctx, "member", false, "img-1"))
+	assert.Equal(t, reads, workspaces.reads)
+
+	image.SetDeleted(true)
+	err = access.Authorize(ctx, "new-member", true, "img-1")
+	assert.True(t, errors.IsNotFound(err), err)
+}
+
+func TestImageAccess_SignedToken(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	access := NewImageAccess(nil, nil, nil, nil, 0, []byte(strings.Repeat("k", 32)), 15*time.Minute)
+
+	signed, err := access.Sign("img-1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), signed.ExpiresAt)
+
+	assert.NoError(t, access.VerifyToken("img-1", signed.Token, now.Add(14*time.Minute)))
+	assert.Error(t, access.VerifyToken("img-2", signed.Token, now), "other image")
+	assert.Error(t, access.VerifyToken("img-1", signed.Token, now.Add(15*time.Minute)), "expired")
+
+	// Moving the expiry invalidates the signature
+	_, signature, _ := strings.Cut(signed.Token, ".")
+	assert.Error(t, access.VerifyToken("img-1", "9999999999."+signature, now))
+
+	disabled := NewImageAccess(nil, nil, nil, nil, 0, nil, time.Minute)
+	_, err = disabled.Sign("img-1", now)
+	assert.Error(t, err)
+	assert.Error(t, disabled.VerifyToken("img-1", signed.Token, now))
+}
@@ -0,0 +1,117 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// How long a workspace's de-identification is reused before it is read again
+const redactionCacheTTL = time.Minute
+
+// Fills redacted label and macro images
+var redactionColor = color.Gray{Y: 0x80}
+
+// associatedContent returns the content ID behind a label or macro request.
+func associatedContent(image *model.Image, requestType RequestType) (*string, string) {
+	if requestType == RequestTypeLabel {
+		return image.LabelContentID, "label"
+	}
+	return image.MacroContentID, "macro"
+}
+
+// serveAssociated serves an image's label or macro image. Images of
+// de-identified workspaces get a blank image of the same size and format
+// instead: the macro image shows the label too.
+func (s *TileServer) serveAssociated(ctx context.Context, imageID string, requestType RequestType) (io.ReadCloser, error) {
+	image, err := s.getImage(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	contentID, name := associatedContent(image, requestType)
+	if contentID == nil {
+		return nil, errors.NewNotFoundError(name + " image not found for image")
+	}
+
+	redacted, err := s.isRedacted(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	if !redacted {
+		return s.readThrough(ctx, imageID, *contentID, name, func() (io.ReadCloser, error) {
+			return s.getContent(ctx, *contentID)
+		})
+	}
+
+	return s.readThrough(ctx, imageID, *contentID, name+"_redacted", func() (io.ReadCloser, error) {
+		content, err := s.getContentMetadata(ctx, *contentID)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := s.storage.Get(ctx, *content)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		data, err := redact(reader, content.ContentType)
+		if err != nil {
+			return nil, errors.NewInternalError("failed to redact "+name+" image", err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// isRedacted reports whether the image's workspace is de-identified.
+func (s *TileServer) isRedacted(ctx context.Context, image *model.Image) (bool, error) {
+	cacheKey := s.keyBuilder.Build("deidentified", image.WsID)
+	if val, err := s.cache.Get(ctx, cacheKey); err == nil && val != nil {
+		if redacted, ok := val.(bool); ok {
+			return redacted, nil
+		}
+	}
+
+	workspace, err := s.workspaceRepo.Read(ctx, image.WsID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, errors.NewNotFoundError("workspace not found")
+		}
+		return false, errors.NewInternalError("failed to read workspace", err)
+	}
+
+	_ = s.cache.Set(ctx, cacheKey, workspace.DeIdentified, redactionCacheTTL)
+	return workspace.DeIdentified, nil
+}
+
+// redact returns a blank image with the size and format of the encoded one.
+func redact(r io.Reader, contentType vobj.ContentType) ([]byte, error) {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray(image.Rect(0, 0, config.Width, config.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(redactionColor), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if contentType.ToStandardType() == vobj.ContentTypeImagePNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
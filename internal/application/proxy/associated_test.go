@@ -0,0 +1,71 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTileServer_ServeAssociated(t *testing.T) {
+	ctx := context.Background()
+
+	var label bytes.Buffer
+	assert.NoError(t, png.Encode(&label, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+
+	labelID := "label.png"
+	workspace := &model.Workspace{Entity: vobj.Entity{ID: "ws-1"}}
+	server := NewTileServer(noCache{}, cache.NewKeyBuilder("test"), labelContentRepo{},
+		&fakeImageRepo{image: &model.Image{WsID: "ws-1", LabelContentID: &labelID}},
+		&fakeWorkspaceRepo{workspace: workspace},
+		&fakeStorage{files: map[string][]byte{labelID: label.Bytes()}}, nil, nil, 0)
+
+	read := func(path string) []byte {
+		reader, err := server.ServeRequest(ctx, "img-1", path)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		return data
+	}
+
+	assert.Equal(t, label.Bytes(), read("label.png"))
+	plain, err := server.Object(ctx, "img-1", "label.png")
+	assert.NoError(t, err)
+
+	_, err = server.ServeRequest(ctx, "img-1", "macro.jpg")
+	assert.True(t, errors.IsNotFound(err), err)
+
+	workspace.DeIdentified = true
+	redacted := read("label.png")
+	assert.NotEqual(t, label.Bytes(), redacted)
+	decoded, err := png.Decode(bytes.NewReader(redacted))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 40, 20), decoded.Bounds())
+	gray, _, _, _ := decoded.At(5, 5).RGBA()
+	assert.Equal(t, uint32(0x8080), gray)
+
+	// Cached copies of the label must not validate against the redaction
+	obj, err := server.Object(ctx, "img-1", "label.png")
+	assert.NoError(t, err)
+	assert.NotEqual(t, plain.ETag, obj.ETag)
+	size, err := obj.Size()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(redacted)), size)
+}
+
+type labelContentRepo struct{ fakeContentRepo }
+
+func (labelContentRepo) Read(ctx context.Context, id string) (*model.Content, error) {
+	return &model.Content{Entity: vobj.Entity{ID: id}, Path: id, ContentType: vobj.ContentTypeLabelPNG}, nil
+}
@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// e.g. image_files/12/3_4.jpeg
+var tilePathPattern = regexp.MustCompile(`(?:^|/)(\d+)/(\d+)_(\d+)\.\w+$`)
+
+// blankTiles encodes the blank tile once per format and size.
+type blankTiles struct {
+	color   color.Color
+	encoded sync.Map // "<format>/<size>" -> []byte
+}
+
+func (b *blankTiles) get(format string, size int) ([]byte, error) {
+	key := fmt.Sprintf("%s/%d", format, size)
+	if data, ok := b.encoded.Load(key); ok {
+		return data.([]byte), nil
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(b.color), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b.encoded.Store(key, buf.Bytes())
+	return buf.Bytes(), nil
+}
+
+// blankFor returns the blank tile when tilePath addresses a level, column or
+// row outside the image's pyramid. Viewers request those at the image edges.
+func (s *TileServer) blankFor(ctx context.Context, imageID, tilePath string) ([]byte, bool) {
+	if s.blankTile == nil {
+		return nil, false
+	}
+	match := tilePathPattern.FindStringSubmatch(tilePath)
+	if match == nil {
+		return nil, false
+	}
+	// Without a descriptor the bounds are unknown; let the tile lookup decide
+	pyramid, err := s.getPyramid(ctx, imageID)
+	if err != nil {
+		return nil, false
+	}
+
+	level, _ := strconv.Atoi(match[1])
+	col, _ := strconv.Atoi(match[2])
+	row, _ := strconv.Atoi(match[3])
+	if !pyramid.outside(level, col, row) {
+		return nil, false
+	}
+
+	format := "jpeg"
+	if strings.EqualFold(filepath.Ext(tilePath), ".png") {
+		format = "png"
+	}
+	data, err := s.blankTile.get(format, pyramid.TileSize)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// outside reports whether a DZI tile coordinate lies beyond the pyramid.
+func (p *Pyramid) outside(level, col, row int) bool {
+	if level > p.MaxLevel() {
+		return true
+	}
+	scale := 1 << (p.MaxLevel() - level)
+	return col*p.TileSize >= ceilDiv(p.Width, scale) || row*p.TileSize >= ceilDiv(p.Height, scale)
+}
+
+// ParseHexColor parses #rrggbb or #rrggbbaa.
+func ParseHexColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	if len(hex) == 6 {
+		v = v<<8 | 0xff
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTileServer_ServeTileOutsidePyramid(t *testing.T) {
+	server := newPyramidServer(t)
+	ctx := context.Background()
+
+	// Without a blank color, missing tiles are not found rather than failing
+	_, err := server.ServeRequest(ctx, "img-1", "image_files/10/5_0.png")
+	assert.True(t, errors.IsNotFound(err), err)
+
+	white, err := ParseHexColor("#ffffff")
+	assert.NoError(t, err)
+	server.blankTile = &blankTiles{color: white}
+
+	// Level 10 is 600x400, so column 3 starts past its right edge
+	reader, err := server.ServeRequest(ctx, "img-1", "image_files/10/3_0.png")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer reader.Close()
+	tile, err := png.Decode(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 256, 256), tile.Bounds())
+	r, g, b, a := tile.At(10, 10).RGBA()
+	assert.Equal(t, [4]uint32{0xffff, 0xffff, 0xffff, 0xffff}, [4]uint32{r, g, b, a})
+
+	_, err = server.ServeRequest(ctx, "img-1", "image_files/11/0_0.png")
+	assert.NoError(t, err, "level above the pyramid")
+
+	// Tiles inside the pyramid are still looked up
+	_, err = server.ServeRequest(ctx, "img-1", "image_files/10/2_1.png")
+	assert.NoError(t, err)
+
+	server.imageRepo.(*fakeImageRepo).image.SetDeleted(true)
+	_, err = server.ServeRequest(ctx, "img-1", "image_files/10/0_0.png")
+	assert.True(t, errors.IsNotFound(err), err)
+}
+
+func TestParseHexColor(t *testing.T) {
+	c, err := ParseHexColor("#336699")
+	assert.NoError(t, err)
+	assert.Equal(t, color.NRGBA{R: 0x33, G: 0x66, B: 0x99, A: 0xff}, c)
+
+	c, err = ParseHexColor("00000000")
+	assert.NoError(t, err)
+	assert.Equal(t, color.NRGBA{}, c)
+
+	_, err = ParseHexColor("#fff")
+	assert.Error(t, err)
+}
@@ -0,0 +1,361 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+const (
+	IIIFContext  = "http://iiif.io/api/image/3/context.json"
+	IIIFProtocol = "http://iiif.io/api/image"
+	// Only the pyramid tiles and the sizes listed in info.json are served
+	IIIFProfile = "level0"
+)
+
+// Pyramid describes an image's DZI tile pyramid. Level MaxLevel is the full
+// resolution image and every level below halves it, rounding up.
+type Pyramid struct {
+	Width    int
+	Height   int
+	TileSize int
+	Overlap  int
+	// Tile file extension, e.g. jpeg
+	Format string
+}
+
+type dziDescriptor struct {
+	XMLName  xml.Name `xml:"Image"`
+	TileSize int      `xml:"TileSize,attr"`
+	Overlap  int      `xml:"Overlap,attr"`
+	Format   string   `xml:"Format,attr"`
+	Size     struct {
+		Width  int `xml:"Width,attr"`
+		Height int `xml:"Height,attr"`
+	} `xml:"Size"`
+}
+
+func ParsePyramid(r io.Reader) (*Pyramid, error) {
+	var d dziDescriptor
+	if err := xml.NewDecoder(r).Decode(&d); err != nil {
+		return nil, err
+	}
+	if d.TileSize <= 0 || d.Size.Width <= 0 || d.Size.Height <= 0 {
+		return nil, fmt.Errorf("incomplete DZI descriptor")
+	}
+	return &Pyramid{
+		Width:    d.Size.Width,
+		Height:   d.Size.Height,
+		TileSize: d.TileSize,
+		Overlap:  d.Overlap,
+		Format:   d.Format,
+	}, nil
+}
+
+func (p *Pyramid) MaxLevel() int {
+	level := 0
+	for d := max(p.Width, p.Height); d > 1; d = (d + 1) / 2 {
+		level++
+	}
+	return level
+}
+
+// ScaleFactors lists the downsampling of each level, from full resolution
+// down to the first level that fits in a single tile.
+func (p *Pyramid) ScaleFactors() []int {
+	factors := []int{1}
+	for sf := 1; sf < 1<<p.MaxLevel() && ceilDiv(max(p.Width, p.Height), sf) > p.TileSize; {
+		sf *= 2
+		factors = append(factors, sf)
+	}
+	return factors
+}
+
+// TileRef locates an IIIF tile in the pyramid.
+type TileRef struct {
+	Level int
+	Col   int
+	Row   int
+	// Part of the DZI tile to return once its overlap is cut away; empty
+	// when the tile is returned as stored
+	Crop image.Rectangle
+}
+
+func (t TileRef) Path(format string) string {
+	return fmt.Sprintf("image_files/%d/%d_%d.%s", t.Level, t.Col, t.Row, format)
+}
+
+// Locate maps an IIIF region and size onto a pyramid tile. Only regions
+// matching a tile at one of the scale factors can be served.
+func (p *Pyramid) Locate(region, size string) (*TileRef, error) {
+	x, y, w, h, err := p.parseRegion(region)
+	if err != nil {
+		return nil, err
+	}
+
+	sf, err := p.scaleFactorFor(size, w, h)
+	if err != nil {
+		return nil, err
+	}
+
+	edge := p.TileSize * sf
+	if x%edge != 0 || y%edge != 0 || w != min(edge, p.Width-x) || h != min(edge, p.Height-y) {
+		return nil, errors.NewBadRequestError("region is not aligned to a tile", map[string]interface{}{
+			"region":    region,
+			"tile_size": p.TileSize,
+			"scale":     sf,
+		})
+	}
+
+	ref := &TileRef{Col: x / edge, Row: y / edge}
+	for f := sf; f > 1; f /= 2 {
+		ref.Level++
+	}
+	ref.Level = p.MaxLevel() - ref.Level
+
+	if p.Overlap > 0 {
+		left, top := 0, 0
+		if ref.Col > 0 {
+			left = p.Overlap
+		}
+		if ref.Row > 0 {
+			top = p.Overlap
+		}
+		ref.Crop = image.Rect(left, top, left+ceilDiv(w, sf), top+ceilDiv(h, sf))
+	}
+	return ref, nil
+}
+
+// parseRegion returns the requested region clipped to the image.
+func (p *Pyramid) parseRegion(region string) (x, y, w, h int, err error) {
+	if region == "full" {
+		return 0, 0, p.Width, p.Height, nil
+	}
+
+	parts := strings.Split(region, ",")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, errors.NewBadRequestError("unsupported region", map[string]interface{}{"region": region})
+	}
+	values := make([]int, 4)
+	for i, part := range parts {
+		values[i], err = strconv.Atoi(part)
+		if err != nil || values[i] < 0 {
+			return 0, 0, 0, 0, errors.NewBadRequestError("unsupported region", map[string]interface{}{"region": region})
+		}
+	}
+
+	x, y, w, h = values[0], values[1], values[2], values[3]
+	if w == 0 || h == 0 || x >= p.Width || y >= p.Height {
+		return 0, 0, 0, 0, errors.NewBadRequestError("region is outside the image", map[string]interface{}{"region": region})
+	}
+	return x, y, min(w, p.Width-x), min(h, p.Height-y), nil
+}
+
+// scaleFactorFor finds the scale factor that renders a w x h region at the
+// requested size.
+func (p *Pyramid) scaleFactorFor(size string, w, h int) (int, error) {
+	if size == "max" {
+		return 1, nil
+	}
+
+	width, height := -1, -1
+	parts := strings.Split(size, ",")
+	if len(parts) == 2 && !strings.HasPrefix(size, "!") && !strings.HasPrefix(size, "^") && size != "," {
+		var err error
+		if parts[0] != "" {
+			if width, err = strconv.Atoi(parts[0]); err != nil {
+				width = 0
+			}
+		}
+		if parts[1] != "" {
+			if height, err = strconv.Atoi(parts[1]); err != nil {
+				height = 0
+			}
+		}
+	}
+	if width == 0 || height == 0 || (width < 0 && height < 0) {
+		return 0, errors.NewBadRequestError("unsupported size", map[string]interface{}{"size": size})
+	}
+
+	for sf := 1; sf <= 1<<p.MaxLevel(); sf *= 2 {
+		if (width < 0 || ceilDiv(w, sf) == width) && (height < 0 || ceilDiv(h, sf) == height) {
+			return sf, nil
+		}
+	}
+	return 0, errors.NewBadRequestError("size does not match a pyramid level", map[string]interface{}{"size": size})
+}
+
+// iiifFormat is the IIIF format extension of a DZI tile format.
+func iiifFormat(dziFormat string) string {
+	switch strings.ToLower(dziFormat) {
+	case "jpeg", "jpg":
+		return "jpg"
+	default:
+		return strings.ToLower(dziFormat)
+	}
+}
+
+type IIIFTiles struct {
+	Width        int   `json:"width"`
+	ScaleFactors []int `json:"scaleFactors"`
+}
+
+type IIIFSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type IIIFInfo struct {
+	Context          string      `json:"@context"`
+	ID               string      `json:"id"`
+	Type             string      `json:"type"`
+	Protocol         string      `json:"protocol"`
+	Profile          string      `json:"profile"`
+	Width            int         `json:"width"`
+	Height           int         `json:"height"`
+	Tiles            []IIIFTiles `json:"tiles"`
+	Sizes            []IIIFSize  `json:"sizes,omitempty"`
+	PreferredFormats []string    `json:"preferredFormats"`
+}
+
+// IIIFInfo builds the info.json of an image, id being the base URI the
+// image service is reached at.
+func (s *TileServer) IIIFInfo(ctx context.Context, imageID, id string) (*IIIFInfo, error) {
+	pyramid, err := s.getPyramid(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &IIIFInfo{
+		Context:          IIIFContext,
+		ID:               id,
+		Type:             "ImageService3",
+		Protocol:         IIIFProtocol,
+		Profile:          IIIFProfile,
+		Width:            pyramid.Width,
+		Height:           pyramid.Height,
+		Tiles:            []IIIFTiles{{Width: pyramid.TileSize, ScaleFactors: pyramid.ScaleFactors()}},
+		PreferredFormats: []string{iiifFormat(pyramid.Format)},
+	}
+
+	// Whole image renditions exist for the levels that fit in one tile
+	for sf := 1 << pyramid.MaxLevel(); sf >= 1; sf /= 2 {
+		w, h := ceilDiv(pyramid.Width, sf), ceilDiv(pyramid.Height, sf)
+		if w > pyramid.TileSize || h > pyramid.TileSize {
+			break
+		}
+		info.Sizes = append(info.Sizes, IIIFSize{Width: w, Height: h})
+	}
+
+	return info, nil
+}
+
+// ServeIIIF serves an IIIF image request from the image's tiles and returns
+// the media type of the result.
+func (s *TileServer) ServeIIIF(ctx context.Context, imageID, region, size, rotation, quality, format string) (io.ReadCloser, string, error) {
+	if rotation != "0" {
+		return nil, "", errors.NewBadRequestError("unsupported rotation", map[string]interface{}{"rotation": rotation})
+	}
+	if quality != "default" && quality != "color" {
+		return nil, "", errors.NewBadRequestError("unsupported quality", map[string]interface{}{"quality": quality})
+	}
+
+	pyramid, err := s.getPyramid(ctx, imageID)
+	if err != nil {
+		return nil, "", err
+	}
+	if format != iiifFormat(pyramid.Format) {
+		return nil, "", errors.NewBadRequestError("unsupported format", map[string]interface{}{
+			"format":    format,
+			"available": iiifFormat(pyramid.Format),
+		})
+	}
+
+	ref, err := pyramid.Locate(region, size)
+	if err != nil {
+		return nil, "", err
+	}
+
+	mediaType := "image/jpeg"
+	if format == "png" {
+		mediaType = "image/png"
+	}
+
+	tile, err := s.serveTile(ctx, imageID, ref.Path(pyramid.Format))
+	if err != nil || ref.Crop.Empty() {
+		return tile, mediaType, err
+	}
+	defer tile.Close()
+
+	cropped, err := cropTile(tile, ref.Crop, format)
+	if err != nil {
+		return nil, "", errors.NewInternalError("failed to crop tile overlap", err)
+	}
+	return cropped, mediaType, nil
+}
+
+// cropTile cuts the DZI overlap off a tile, re-encoding it.
+func cropTile(r io.Reader, crop image.Rectangle, format string) (io.ReadCloser, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, fmt.Errorf("cannot crop %T", img)
+	}
+	cropped := sub.SubImage(crop.Add(img.Bounds().Min).Intersect(img.Bounds()))
+
+	var buf bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&buf, cropped)
+	} else {
+		err = jpeg.Encode(&buf, cropped, &jpeg.Options{Quality: 90})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&buf), nil
+}
+
+func (s *TileServer) getPyramid(ctx context.Context, imageID string) (*Pyramid, error) {
+	cacheKey := s.keyBuilder.Build("pyramid", imageID)
+
+	if val, err := s.cache.Get(ctx, cacheKey); err == nil && val != nil {
+		if pyramid, ok := val.(*Pyramid); ok {
+			return pyramid, nil
+		}
+	}
+
+	reader, err := s.serveDZI(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	pyramid, err := ParsePyramid(reader)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to parse DZI descriptor", err)
+	}
+
+	_ = s.cache.Set(ctx, cacheKey, pyramid, 30*time.Minute)
+
+	return pyramid, nil
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDZI = `<?xml version="1.0" encoding="UTF-8"?>
+<Image xmlns="http://schemas.microsoft.com/deepzoom/2008" Format="jpeg" Overlap="1" TileSize="256">
+  <Size Height="600" Width="1000"/>
+</Image>`
+
+func TestPyramid_Locate(t *testing.T) {
+	pyramid, err := ParsePyramid(strings.NewReader(testDZI))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, pyramid.MaxLevel())
+	assert.Equal(t, []int{1, 2, 4}, pyramid.ScaleFactors())
+
+	tests := []struct {
+		region string
+		size   string
+		want   TileRef
+	}{
+		{"256,0,256,256", "256,", TileRef{Level: 10, Col: 1, Row: 0, Crop: image.Rect(1, 0, 257, 256)}},
+		// Edge tiles are clipped to the image
+		{"768,512,232,88", "232,", TileRef{Level: 10, Col: 3, Row: 2, Crop: image.Rect(1, 1, 233, 89)}},
+		{"768,512,400,400", "232,88", TileRef{Level: 10, Col: 3, Row: 2, Crop: image.Rect(1, 1, 233, 89)}},
+		{"0,0,512,512", "256,256", TileRef{Level: 9, Col: 0, Row: 0, Crop: image.Rect(0, 0, 256, 256)}},
+		{"full", ",150", TileRef{Level: 8, Col: 0, Row: 0, Crop: image.Rect(0, 0, 250, 150)}},
+		{"full", "125,75", TileRef{Level: 7, Col: 0, Row: 0, Crop: image.Rect(0, 0, 125, 75)}},
+	}
+	for _, tt := range tests {
+		ref, err := pyramid.Locate(tt.region, tt.size)
+		if assert.NoError(t, err, tt.region) {
+			assert.Equal(t, tt.want, *ref, tt.region)
+		}
+	}
+
+	assert.Equal(t, "image_files/10/3_2.jpeg", TileRef{Level: 10, Col: 3, Row: 2}.Path("jpeg"))
+
+	for _, bad := range [][2]string{
+		{"100,0,256,256", "256,"}, // not tile aligned
+		{"0,0,256,256", "128,"},   // scaled within a level
+		{"full", "max"},           // larger than a tile
+		{"0,0,256,256", "!256,256"},
+		{"pct:0,0,10,10", "max"},
+		{"2000,0,256,256", "256,"},
+	} {
+		_, err := pyramid.Locate(bad[0], bad[1])
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,164 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// Object is a proxy object resolved without reading its body, so requests
+// can be answered from the ETag alone.
+type Object struct {
+	Type RequestType
+	// Strong validator, quoted
+	ETag string
+
+	size      func() (int64, error)
+	open      func() (io.ReadCloser, error)
+	openRange func(offset, length int64) (io.ReadCloser, error)
+
+	// Body of objects without ranged reads, kept once read for its size
+	buffered []byte
+}
+
+// Size returns the object's length in bytes, reading the body when it
+// cannot be known otherwise.
+func (o *Object) Size() (int64, error) {
+	if o.size != nil {
+		return o.size()
+	}
+	if err := o.buffer(); err != nil {
+		return 0, err
+	}
+	return int64(len(o.buffered)), nil
+}
+
+func (o *Object) Open() (io.ReadCloser, error) {
+	if o.buffered != nil {
+		return io.NopCloser(bytes.NewReader(o.buffered)), nil
+	}
+	return o.open()
+}
+
+// OpenRange reads length bytes from offset. The range must lie within Size.
+func (o *Object) OpenRange(offset, length int64) (io.ReadCloser, error) {
+	if o.openRange != nil {
+		return o.openRange(offset, length)
+	}
+	if err := o.buffer(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(o.buffered[offset : offset+length])), nil
+}
+
+func (o *Object) buffer() error {
+	if o.buffered != nil {
+		return nil
+	}
+	reader, err := o.open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return errors.NewInternalError("failed to read object", err)
+	}
+	o.buffered = data
+	return nil
+}
+
+// Object resolves a proxy path to the content backing it. The ETag changes
+// whenever reprocessing replaces that content.
+func (s *TileServer) Object(ctx context.Context, imageID, objectPath string) (*Object, error) {
+	requestType := s.determineRequestType(objectPath)
+
+	image, err := s.getImage(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	var contentID *string
+	switch requestType {
+	case RequestTypeDZI:
+		contentID = image.DziContentID
+	case RequestTypeThumbnail:
+		contentID = image.ThumbnailContentID
+	case RequestTypeIndexMap:
+		contentID = image.IndexmapContentID
+	case RequestTypeTile:
+		contentID = image.ZipTilesContentID
+		if contentID == nil {
+			contentID = image.TilesContentID
+		}
+	case RequestTypeLabel, RequestTypeMacro:
+		contentID, _ = associatedContent(image, requestType)
+	default:
+		return nil, errors.NewBadRequestError(
+			fmt.Sprintf("unknown request type for path: %s", objectPath),
+			nil,
+		)
+	}
+	if contentID == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("no content for %s", objectPath))
+	}
+
+	redacted := false
+	if requestType == RequestTypeLabel || requestType == RequestTypeMacro {
+		if redacted, err = s.isRedacted(ctx, image); err != nil {
+			return nil, err
+		}
+	}
+
+	etagSource := *contentID
+	if redacted {
+		etagSource += "\x00redacted"
+	}
+	obj := &Object{
+		Type: requestType,
+		ETag: objectETag(image, etagSource, objectPath),
+		open: func() (io.ReadCloser, error) {
+			return s.ServeRequest(ctx, imageID, objectPath)
+		},
+	}
+
+	// Whole contents can be read in ranges; tiles are small and read whole,
+	// and redacted images differ from their contents
+	if requestType != RequestTypeTile && !redacted {
+		content, err := s.getContentMetadata(ctx, *contentID)
+		if err != nil {
+			return nil, err
+		}
+		obj.size = func() (int64, error) {
+			if content.Size > 0 {
+				return content.Size, nil
+			}
+			attrs, err := s.storage.GetAttributes(ctx, *content)
+			if err != nil {
+				return 0, errors.NewInternalError("failed to read object size", err)
+			}
+			return attrs.Size, nil
+		}
+		obj.openRange = func(offset, length int64) (io.ReadCloser, error) {
+			return s.storage.GetRange(ctx, *content, offset, length)
+		}
+	}
+
+	return obj, nil
+}
+
+func objectETag(image *model.Image, contentID, objectPath string) string {
+	version := ""
+	if image.Processing != nil {
+		version = image.Processing.Version.String()
+	}
+	sum := sha256.Sum256([]byte(contentID + "\x00" + version + "\x00" + objectPath))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,351 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/histopathai/main-service/internal/shared/query"
+)
+
+const (
+	// Annotations read per page while loading an image's overlay
+	overlayPageSize = 1000
+	// Share of the outline opacity used to fill polygons
+	overlayFillOpacity = 0.35
+)
+
+// Drawn for annotation types without a color
+var defaultOverlayColor = color.NRGBA{R: 0x00, G: 0xc8, B: 0x53, A: 0xff}
+
+// overlayShape is an annotation polygon in full resolution pixels.
+type overlayShape struct {
+	typeID string
+	color  color.NRGBA
+	points [][2]float64
+	// Bounding box
+	minX, minY, maxX, maxY float64
+}
+
+// overlaySet is the polygons of one image. Version changes whenever a
+// polygon or color does, so tiles cached under it never go stale.
+type overlaySet struct {
+	version string
+	shapes  []overlayShape
+}
+
+// OverlayTile is a rendered overlay tile.
+type OverlayTile struct {
+	Data []byte
+	ETag string
+}
+
+// OverlayRenderer rasterises an image's annotation polygons into transparent
+// PNG tiles on the image's DZI grid. Polygons are drawn in their annotation
+// type's color.
+//
+// Rendered tiles are kept in the tile server's payload cache under the
+// version of the image's polygons. Annotation changes drop the polygons on
+// the instance running the outbox relay; other instances reload them after
+// setTTL.
+type OverlayRenderer struct {
+	server             *TileServer
+	cache              cache.Cache
+	keyBuilder         *cache.KeyBuilder
+	annotationRepo     port.AnnotationRepository
+	annotationTypeRepo port.AnnotationTypeRepository
+	// How long an image's polygons are reused
+	setTTL time.Duration
+}
+
+func NewOverlayRenderer(
+	server *TileServer,
+	cache cache.Cache,
+	keyBuilder *cache.KeyBuilder,
+	annotationRepo port.AnnotationRepository,
+	annotationTypeRepo port.AnnotationTypeRepository,
+	setTTL time.Duration,
+) *OverlayRenderer {
+	return &OverlayRenderer{
+		server:             server,
+		cache:              cache,
+		keyBuilder:         keyBuilder,
+		annotationRepo:     annotationRepo,
+		annotationTypeRepo: annotationTypeRepo,
+		setTTL:             setTTL,
+	}
+}
+
+// RenderTile renders the overlay tile at a DZI level, column and row.
+// typeIDs restricts the polygons to those annotation types; empty draws all.
+func (r *OverlayRenderer) RenderTile(ctx context.Context, imageID string, level, col, row int, typeIDs []string) (*OverlayTile, error) {
+	if level < 0 || col < 0 || row < 0 {
+		return nil, errors.NewBadRequestError("invalid tile coordinates", map[string]interface{}{
+			"level": level, "col": col, "row": row,
+		})
+	}
+
+	pyramid, err := r.server.getPyramid(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if pyramid.outside(level, col, row) {
+		return nil, errors.NewNotFoundError("tile is outside the image")
+	}
+
+	set, err := r.getSet(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	typeIDs = slices.Clone(typeIDs)
+	sort.Strings(typeIDs)
+	typeIDs = slices.Compact(typeIDs)
+	etag := overlayETag(set.version, typeIDs)
+
+	tileKey := r.keyBuilder.Build("tile", set.version, strings.Join(typeIDs, ","), TileRef{Level: level, Col: col, Row: row}.Path("png"))
+	if r.server.tiles != nil {
+		if data, ok := r.server.tiles.Get(ctx, imageID, tileKey); ok {
+			return &OverlayTile{Data: data, ETag: etag}, nil
+		}
+	}
+
+	data, err := set.render(pyramid, level, col, row, typeIDs)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to render overlay tile", err)
+	}
+	if r.server.tiles != nil {
+		r.server.tiles.Set(ctx, imageID, tileKey, data)
+	}
+	return &OverlayTile{Data: data, ETag: etag}, nil
+}
+
+// InvalidateImage drops an image's polygons after its annotations change.
+func (r *OverlayRenderer) InvalidateImage(ctx context.Context, imageID string) {
+	_, _ = r.cache.Delete(ctx, r.keyBuilder.Build("set", imageID))
+}
+
+// InvalidateAll drops the polygons of every image, e.g. after an annotation
+// type's color changes.
+func (r *OverlayRenderer) InvalidateAll(ctx context.Context) {
+	_, _ = r.cache.DeletePattern(ctx, r.keyBuilder.BuildPattern("set", "*"))
+}
+
+func (r *OverlayRenderer) getSet(ctx context.Context, imageID string) (*overlaySet, error) {
+	cacheKey := r.keyBuilder.Build("set", imageID)
+	if val, err := r.cache.Get(ctx, cacheKey); err == nil && val != nil {
+		if set, ok := val.(*overlaySet); ok {
+			return set, nil
+		}
+	}
+
+	set, err := r.loadSet(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+	_ = r.cache.Set(ctx, cacheKey, set, r.setTTL)
+	return set, nil
+}
+
+func (r *OverlayRenderer) loadSet(ctx context.Context, imageID string) (*overlaySet, error) {
+	var annotations []*model.Annotation
+	for offset := 0; ; offset += overlayPageSize {
+		builder := query.NewBuilder()
+		builder.Where(fields.EntityParentID.DomainName(), query.OpEqual, imageID)
+		builder.Where(fields.EntityIsDeleted.DomainName(), query.OpEqual, false)
+		builder.Paginate(overlayPageSize, offset)
+
+		result, err := r.annotationRepo.Find(ctx, builder.Build())
+		if err != nil {
+			return nil, errors.NewInternalError("failed to list annotations", err)
+		}
+		annotations = append(annotations, result.Data...)
+		if !result.HasMore {
+			break
+		}
+	}
+
+	// Listing order is not stable; the version must be
+	slices.SortFunc(annotations, func(a, b *model.Annotation) int { return strings.Compare(a.ID, b.ID) })
+
+	colors := make(map[string]color.NRGBA)
+	hash := sha256.New()
+	set := &overlaySet{}
+	for _, annotation := range annotations {
+		if annotation.Polygon == nil || len(*annotation.Polygon) < 3 {
+			continue
+		}
+
+		c, ok := colors[annotation.AnnotationTypeID]
+		if !ok {
+			var err error
+			if c, err = r.typeColor(ctx, annotation.AnnotationTypeID); err != nil {
+				return nil, err
+			}
+			colors[annotation.AnnotationTypeID] = c
+		}
+
+		shape := overlayShape{
+			typeID: annotation.AnnotationTypeID,
+			color:  c,
+			minX:   math.Inf(1),
+			minY:   math.Inf(1),
+			maxX:   math.Inf(-1),
+			maxY:   math.Inf(-1),
+		}
+		for _, p := range *annotation.Polygon {
+			shape.points = append(shape.points, [2]float64{p.X, p.Y})
+			shape.minX, shape.maxX = math.Min(shape.minX, p.X), math.Max(shape.maxX, p.X)
+			shape.minY, shape.maxY = math.Min(shape.minY, p.Y), math.Max(shape.maxY, p.Y)
+		}
+		set.shapes = append(set.shapes, shape)
+
+		hash.Write([]byte(annotation.ID + "\x00" + annotation.UpdatedAt.UTC().Format(time.RFC3339Nano) + "\x00"))
+		hash.Write([]byte{c.R, c.G, c.B, c.A})
+	}
+	set.version = hex.EncodeToString(hash.Sum(nil))[:16]
+	return set, nil
+}
+
+func (r *OverlayRenderer) typeColor(ctx context.Context, typeID string) (color.NRGBA, error) {
+	annotationType, err := r.annotationTypeRepo.Read(ctx, typeID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return defaultOverlayColor, nil
+		}
+		return color.NRGBA{}, errors.NewInternalError("failed to read annotation type", err)
+	}
+	if annotationType.Color == nil {
+		return defaultOverlayColor, nil
+	}
+	parsed, err := ParseHexColor(*annotationType.Color)
+	if err != nil {
+		return defaultOverlayColor, nil
+	}
+	return parsed.(color.NRGBA), nil
+}
+
+func overlayETag(version string, typeIDs []string) string {
+	if len(typeIDs) == 0 {
+		return `"` + version + `"`
+	}
+	sum := sha256.Sum256([]byte(strings.Join(typeIDs, ",")))
+	return `"` + version + "-" + hex.EncodeToString(sum[:4]) + `"`
+}
+
+// render draws the polygons crossing a tile, including the tile's overlap
+// so that overlay and image tiles line up.
+func (s *overlaySet) render(p *Pyramid, level, col, row int, typeIDs []string) ([]byte, error) {
+	scale := float64(int(1) << (p.MaxLevel() - level))
+	levelW := ceilDiv(p.Width, 1<<(p.MaxLevel()-level))
+	levelH := ceilDiv(p.Height, 1<<(p.MaxLevel()-level))
+	x0, y0 := max(0, col*p.TileSize-p.Overlap), max(0, row*p.TileSize-p.Overlap)
+	x1, y1 := min(levelW, (col+1)*p.TileSize+p.Overlap), min(levelH, (row+1)*p.TileSize+p.Overlap)
+
+	img := image.NewRGBA(image.Rect(0, 0, x1-x0, y1-y0))
+	for i := range s.shapes {
+		shape := &s.shapes[i]
+		if len(typeIDs) > 0 && !slices.Contains(typeIDs, shape.typeID) {
+			continue
+		}
+		if shape.maxX < float64(x0)*scale || shape.minX >= float64(x1)*scale ||
+			shape.maxY < float64(y0)*scale || shape.minY >= float64(y1)*scale {
+			continue
+		}
+
+		points := make([][2]float64, len(shape.points))
+		for j, pt := range shape.points {
+			points[j] = [2]float64{pt[0]/scale - float64(x0), pt[1]/scale - float64(y0)}
+		}
+		fill := shape.color
+		fill.A = uint8(float64(fill.A) * overlayFillOpacity)
+		fillPolygon(img, points, fill)
+		strokePolygon(img, points, shape.color)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fillPolygon fills a polygon with the even-odd rule, sampling pixel centres.
+func fillPolygon(img *image.RGBA, points [][2]float64, c color.NRGBA) {
+	bounds := img.Bounds()
+	var xs []float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		cy := float64(y) + 0.5
+		xs = xs[:0]
+		for i := range points {
+			a, b := points[i], points[(i+1)%len(points)]
+			if (a[1] <= cy) != (b[1] <= cy) {
+				xs = append(xs, a[0]+(cy-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			from := max(bounds.Min.X, int(math.Ceil(xs[i]-0.5)))
+			to := min(bounds.Max.X, int(math.Ceil(xs[i+1]-0.5)))
+			for x := from; x < to; x++ {
+				blend(img, x, y, c)
+			}
+		}
+	}
+}
+
+// strokePolygon draws a one pixel outline.
+func strokePolygon(img *image.RGBA, points [][2]float64, c color.NRGBA) {
+	for i := range points {
+		a, b := points[i], points[(i+1)%len(points)]
+		steps := int(math.Ceil(math.Max(math.Abs(b[0]-a[0]), math.Abs(b[1]-a[1]))))
+		for step := 0; step <= steps; step++ {
+			t := 0.0
+			if steps > 0 {
+				t = float64(step) / float64(steps)
+			}
+			x := int(math.Floor(a[0] + t*(b[0]-a[0])))
+			y := int(math.Floor(a[1] + t*(b[1]-a[1])))
+			if (image.Point{X: x, Y: y}).In(img.Bounds()) {
+				img.SetRGBA(x, y, premultiply(c))
+			}
+		}
+	}
+}
+
+// blend draws c over the pixel at x, y.
+func blend(img *image.RGBA, x, y int, c color.NRGBA) {
+	src := premultiply(c)
+	dst := img.RGBAAt(x, y)
+	inv := 255 - uint32(src.A)
+	img.SetRGBA(x, y, color.RGBA{
+		R: src.R + uint8(uint32(dst.R)*inv/255),
+		G: src.G + uint8(uint32(dst.G)*inv/255),
+		B: src.B + uint8(uint32(dst.B)*inv/255),
+		A: src.A + uint8(uint32(dst.A)*inv/255),
+	})
+}
+
+func premultiply(c color.NRGBA) color.RGBA {
+	a := uint32(c.A)
+	return color.RGBA{
+		R: uint8(uint32(c.R) * a / 255),
+		G: uint8(uint32(c.G) * a / 255),
+		B: uint8(uint32(c.B) * a / 255),
+		A: c.A,
+	}
+}
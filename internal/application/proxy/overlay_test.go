@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/histopathai/main-service/internal/shared/query"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAnnotationRepo struct {
+	port.AnnotationRepository
+	annotations []*model.Annotation
+}
+
+func (r *fakeAnnotationRepo) Find(ctx context.Context, spec query.Specification) (*query.Result[*model.Annotation], error) {
+	return &query.Result[*model.Annotation]{Data: r.annotations}, nil
+}
+
+type fakeAnnotationTypeRepo struct {
+	port.AnnotationTypeRepository
+	colors map[string]string
+}
+
+func (r *fakeAnnotationTypeRepo) Read(ctx context.Context, id string) (*model.AnnotationType, error) {
+	c, ok := r.colors[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("annotation type not found")
+	}
+	return &model.AnnotationType{Entity: vobj.Entity{ID: id}, Color: &c}, nil
+}
+
+func TestOverlayRenderer_RenderTile(t *testing.T) {
+	ctx := context.Background()
+	square := []vobj.Point{{X: 240, Y: 10}, {X: 300, Y: 10}, {X: 300, Y: 60}, {X: 240, Y: 60}}
+	annotations := &fakeAnnotationRepo{annotations: []*model.Annotation{
+		{Entity: vobj.Entity{ID: "a-1"}, AnnotationTypeID: "tumor", Polygon: &square},
+	}}
+	overlay := NewOverlayRenderer(newPyramidServer(t), &mapCache{values: map[string]interface{}{}},
+		cache.NewKeyBuilder("overlay"), annotations,
+		&fakeAnnotationTypeRepo{colors: map[string]string{"tumor": "#ff0000"}}, time.Minute)
+
+	pixel := func(tile *OverlayTile, x, y int) color.NRGBA {
+		decoded, err := png.Decode(bytes.NewReader(tile.Data))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+	}
+
+	// Level 10 is full resolution; tile 1_0 starts at x 255 with the overlap
+	tile, err := overlay.RenderTile(ctx, "img-1", 10, 1, 0, nil)
+	assert.NoError(t, err)
+	inside := pixel(tile, 20, 20)
+	assert.Equal(t, uint8(0xff), inside.R)
+	assert.InDelta(t, 0xff*overlayFillOpacity, float64(inside.A), 1)
+	assert.Equal(t, color.NRGBA{}, pixel(tile, 100, 100))
+
+	filtered, err := overlay.RenderTile(ctx, "img-1", 10, 1, 0, []string{"stroma"})
+	assert.NoError(t, err)
+	assert.Equal(t, color.NRGBA{}, pixel(filtered, 20, 20))
+	assert.NotEqual(t, tile.ETag, filtered.ETag)
+
+	// Polygons are reused until the image is invalidated
+	moved := []vobj.Point{{X: 400, Y: 100}, {X: 450, Y: 100}, {X: 450, Y: 150}}
+	annotations.annotations[0] = &model.Annotation{Entity: vobj.Entity{ID: "a-1", UpdatedAt: time.Now()},
+		AnnotationTypeID: "tumor", Polygon: &moved}
+	again, err := overlay.RenderTile(ctx, "img-1", 10, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tile.ETag, again.ETag)
+
+	overlay.InvalidateImage(ctx, "img-1")
+	again, err = overlay.RenderTile(ctx, "img-1", 10, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tile.ETag, again.ETag)
+	assert.Equal(t, color.NRGBA{}, pixel(again, 20, 20))
+
+	_, err = overlay.RenderTile(ctx, "img-1", 10, 5, 0, nil)
+	assert.True(t, errors.IsNotFound(err), err)
+}
@@ -0,0 +1,274 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+const (
+	// Tiles loaded for one prefetch hint
+	MaxPrefetchTiles = 64
+	// Tiles around the viewport loaded when the hint gives no margin
+	DefaultPrefetchMargin = 1
+	MaxPrefetchMargin     = 3
+	// Time a single warm or prefetch job may take
+	prefetchJobTimeout = time.Minute
+)
+
+// Warm loads what a viewer requests first when it opens an image: the DZI
+// descriptor, thumbnail and index map, and the tiles of the lowest pyramid
+// levels up to maxTiles. Entries cached while the image was still
+// processing are dropped first.
+func (s *TileServer) Warm(ctx context.Context, imageID string, maxTiles int) error {
+	if err := s.InvalidateImage(ctx, imageID); err != nil {
+		return err
+	}
+
+	image, err := s.getImage(ctx, imageID)
+	if err != nil {
+		return err
+	}
+
+	// Reads the descriptor through the payload cache
+	pyramid, err := s.getPyramid(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	if image.ThumbnailContentID != nil {
+		if err := s.drain(s.serveThumbnail(ctx, imageID)); err != nil {
+			return err
+		}
+	}
+	if image.ZipTilesContentID != nil && image.IndexmapContentID != nil {
+		if _, err := s.getIndexMap(ctx, imageID); err != nil {
+			return err
+		}
+	}
+
+	if s.tiles == nil {
+		return nil
+	}
+	for _, path := range pyramid.lowestTiles(maxTiles) {
+		if err := s.drain(s.serveTile(ctx, imageID, path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TileServer) drain(reader io.ReadCloser, err error) error {
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return errors.NewInternalError("failed to read object", err)
+	}
+	return nil
+}
+
+// lowestTiles lists the tiles of the coarsest levels, whole levels only,
+// up to maxTiles tiles.
+func (p *Pyramid) lowestTiles(maxTiles int) []string {
+	var paths []string
+	for level := 0; level <= p.MaxLevel(); level++ {
+		scale := 1 << (p.MaxLevel() - level)
+		cols := ceilDiv(ceilDiv(p.Width, scale), p.TileSize)
+		rows := ceilDiv(ceilDiv(p.Height, scale), p.TileSize)
+		if len(paths)+cols*rows > maxTiles {
+			break
+		}
+		for row := 0; row < rows; row++ {
+			for col := 0; col < cols; col++ {
+				paths = append(paths, TileRef{Level: level, Col: col, Row: row}.Path(p.Format))
+			}
+		}
+	}
+	return paths
+}
+
+// Viewport is the part of a pyramid level a viewer shows, in level pixels.
+type Viewport struct {
+	Level  int
+	X      int
+	Y      int
+	Width  int
+	Height int
+	// Rings of tiles around the viewport to load
+	Margin int
+}
+
+// PrefetchTiles lists the tiles a viewer is likely to request next: the
+// rings around the viewport, then the viewport at the next finer level.
+func (p *Pyramid) PrefetchTiles(v Viewport) ([]string, error) {
+	if v.Level < 0 || v.Level > p.MaxLevel() || v.Width <= 0 || v.Height <= 0 || v.X < 0 || v.Y < 0 {
+		return nil, errors.NewBadRequestError("viewport is outside the pyramid", map[string]interface{}{
+			"level": v.Level, "max_level": p.MaxLevel(),
+		})
+	}
+	if v.Margin < 0 || v.Margin > MaxPrefetchMargin {
+		return nil, errors.NewBadRequestError("prefetch margin out of range", map[string]interface{}{
+			"margin": v.Margin, "max_margin": MaxPrefetchMargin,
+		})
+	}
+
+	var paths []string
+	add := func(level, col0, row0, col1, row1 int, skip func(col, row int) bool) {
+		scale := 1 << (p.MaxLevel() - level)
+		lastCol := ceilDiv(ceilDiv(p.Width, scale), p.TileSize) - 1
+		lastRow := ceilDiv(ceilDiv(p.Height, scale), p.TileSize) - 1
+		for row := max(0, row0); row <= min(row1, lastRow); row++ {
+			for col := max(0, col0); col <= min(col1, lastCol); col++ {
+				if len(paths) < MaxPrefetchTiles && (skip == nil || !skip(col, row)) {
+					paths = append(paths, TileRef{Level: level, Col: col, Row: row}.Path(p.Format))
+				}
+			}
+		}
+	}
+
+	ts := p.TileSize
+	col0, row0 := v.X/ts, v.Y/ts
+	col1, row1 := (v.X+v.Width-1)/ts, (v.Y+v.Height-1)/ts
+	m := v.Margin
+	add(v.Level, col0-m, row0-m, col1+m, row1+m, func(col, row int) bool {
+		return col >= col0 && col <= col1 && row >= row0 && row <= row1
+	})
+	if v.Level < p.MaxLevel() {
+		add(v.Level+1, 2*v.X/ts, 2*v.Y/ts, (2*(v.X+v.Width)-1)/ts, (2*(v.Y+v.Height)-1)/ts, nil)
+	}
+	return paths, nil
+}
+
+type prefetchJob struct {
+	imageID string
+	// Tiles to load; the whole image is warmed when empty
+	paths []string
+}
+
+// Prefetcher loads tiles into the TileServer caches in the background. Jobs
+// beyond its queue are dropped: prefetching is only ever a hint.
+type Prefetcher struct {
+	server       *TileServer
+	jobs         chan prefetchJob
+	workers      int
+	warmMaxTiles int
+	logger       *slog.Logger
+	stop         chan struct{}
+	stopOnce     sync.Once
+}
+
+func NewPrefetcher(server *TileServer, workers, queueSize, warmMaxTiles int, logger *slog.Logger) *Prefetcher {
+	return &Prefetcher{
+		server:       server,
+		jobs:         make(chan prefetchJob, queueSize),
+		workers:      workers,
+		warmMaxTiles: warmMaxTiles,
+		logger:       logger,
+		stop:         make(chan struct{}),
+	}
+}
+
+func (p *Prefetcher) Start(ctx context.Context) error {
+	p.logger.Info("Prefetcher started", slog.Int("workers", p.workers))
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-p.stop:
+					return
+				case job := <-p.jobs:
+					p.run(ctx, job)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (p *Prefetcher) Stop() error {
+	p.logger.Info("Prefetcher stopping...")
+	p.stopOnce.Do(func() { close(p.stop) })
+	return nil
+}
+
+// Warm queues warming of an image's caches and reports whether it was queued.
+func (p *Prefetcher) Warm(imageID string) bool {
+	return p.enqueue(prefetchJob{imageID: imageID})
+}
+
+// Prefetch queues the tiles around a viewport and returns how many were
+// queued.
+func (p *Prefetcher) Prefetch(ctx context.Context, imageID string, viewport Viewport) (int, error) {
+	if p.server.tiles == nil {
+		// Nothing would keep the tiles
+		return 0, nil
+	}
+
+	pyramid, err := p.server.getPyramid(ctx, imageID)
+	if err != nil {
+		return 0, err
+	}
+	paths, err := pyramid.PrefetchTiles(viewport)
+	if err != nil {
+		return 0, err
+	}
+	if len(paths) == 0 || !p.enqueue(prefetchJob{imageID: imageID, paths: paths}) {
+		return 0, nil
+	}
+	return len(paths), nil
+}
+
+func (p *Prefetcher) enqueue(job prefetchJob) bool {
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		p.logger.Debug("Prefetch queue full, dropping job", slog.String("image_id", job.imageID))
+		return false
+	}
+}
+
+func (p *Prefetcher) run(ctx context.Context, job prefetchJob) {
+	ctx, cancel := context.WithTimeout(ctx, prefetchJobTimeout)
+	defer cancel()
+
+	if len(job.paths) == 0 {
+		if err := p.server.Warm(ctx, job.imageID, p.warmMaxTiles); err != nil {
+			p.logger.Warn("Failed to warm tile caches",
+				slog.String("image_id", job.imageID),
+				slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	for _, path := range job.paths {
+		if err := p.server.drain(p.server.serveTile(ctx, job.imageID, path)); err != nil {
+			// Tiles past the edge of sparse pyramids are expected to be missing
+			if !errors.IsNotFound(err) {
+				p.logger.Warn("Failed to prefetch tile",
+					slog.String("image_id", job.imageID),
+					slog.String("path", path),
+					slog.String("error", err.Error()))
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
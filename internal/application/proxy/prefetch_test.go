@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPyramid_LowestTiles(t *testing.T) {
+	// Levels 0-8 hold one tile each, level 9 two and level 10 six
+	pyramid := &Pyramid{Width: 600, Height: 400, TileSize: 256, Format: "png"}
+
+	paths := pyramid.lowestTiles(10)
+	assert.Len(t, paths, 9, "level 9 does not fit whole")
+	assert.Equal(t, "image_files/0/0_0.png", paths[0])
+	assert.Len(t, pyramid.lowestTiles(11), 11)
+	assert.Empty(t, pyramid.lowestTiles(0))
+}
+
+func TestPyramid_PrefetchTiles(t *testing.T) {
+	pyramid := &Pyramid{Width: 600, Height: 400, TileSize: 256, Format: "png"}
+
+	paths, err := pyramid.PrefetchTiles(Viewport{Level: 9, Width: 256, Height: 200, Margin: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"image_files/9/1_0.png",
+		"image_files/10/0_0.png",
+		"image_files/10/1_0.png",
+		"image_files/10/0_1.png",
+		"image_files/10/1_1.png",
+	}, paths)
+
+	// The finest level has nothing below it
+	paths, err = pyramid.PrefetchTiles(Viewport{Level: 10, X: 256, Y: 256, Width: 100, Height: 100})
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+
+	for _, v := range []Viewport{
+		{Level: 11, Width: 1, Height: 1},
+		{Level: 9, Width: 0, Height: 1},
+		{Level: 9, Width: 1, Height: 1, Margin: MaxPrefetchMargin + 1},
+	} {
+		_, err := pyramid.PrefetchTiles(v)
+		assert.True(t, errors.IsType(err, errors.ErrorTypeBadRequest), v)
+	}
+}
@@ -0,0 +1,298 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// Tiles fetched at once while assembling a region
+const regionFetchConcurrency = 8
+
+// Level pixels a region may read per output pixel: the level is at most 2x
+// finer than the output on each axis, plus a partial pixel at each edge
+const maxRegionSourceFactor = 9
+
+// RegionRequest selects a rectangle in full resolution pixels and how to
+// render it. Output size comes from OutputWidth and/or OutputHeight, or from
+// Magnification; the region is rendered 1:1 when none is given.
+type RegionRequest struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+
+	OutputWidth   int
+	OutputHeight  int
+	Magnification float64
+
+	// jpeg or png
+	Format  string
+	Quality int
+}
+
+// RenderedRegion is an encoded region image.
+type RenderedRegion struct {
+	Data      []byte
+	MediaType string
+	Width     int
+	Height    int
+}
+
+// RenderRegion assembles a region from the pyramid level closest to the
+// requested output size, then crops and resizes it.
+func (s *TileServer) RenderRegion(ctx context.Context, imageID string, req RegionRequest) (*RenderedRegion, error) {
+	pyramid, err := s.getPyramid(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Magnification > 0 {
+		img, err := s.getImage(ctx, imageID)
+		if err != nil {
+			return nil, err
+		}
+		if err := resolveMagnification(&req, img); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := pyramid.PlanRegion(req, s.maxRegionPixels)
+	if err != nil {
+		return nil, err
+	}
+
+	canvas, err := s.assemble(ctx, imageID, pyramid, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	out := resample(canvas, plan.Source, plan.OutputWidth, plan.OutputHeight)
+
+	var buf bytes.Buffer
+	mediaType := "image/jpeg"
+	if req.Format == "png" {
+		mediaType = "image/png"
+		err = png.Encode(&buf, out)
+	} else {
+		quality := req.Quality
+		if quality <= 0 {
+			quality = 90
+		}
+		err = jpeg.Encode(&buf, out, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, errors.NewInternalError("failed to encode region", err)
+	}
+
+	return &RenderedRegion{
+		Data:      buf.Bytes(),
+		MediaType: mediaType,
+		Width:     plan.OutputWidth,
+		Height:    plan.OutputHeight,
+	}, nil
+}
+
+// resolveMagnification turns a target magnification into an output width.
+func resolveMagnification(req *RegionRequest, img *model.Image) error {
+	var native *float64
+	if img.Magnification != nil {
+		native = img.Magnification.ScanMagnification
+		if native == nil {
+			native = img.Magnification.Objective
+		}
+	}
+	if native == nil || *native <= 0 {
+		return errors.NewBadRequestError("image has no recorded magnification", map[string]interface{}{
+			"magnification": req.Magnification,
+		})
+	}
+	if req.Magnification > *native {
+		return errors.NewBadRequestError("magnification exceeds the scan magnification", map[string]interface{}{
+			"magnification":      req.Magnification,
+			"scan_magnification": *native,
+		})
+	}
+
+	req.OutputWidth = max(1, int(math.Round(float64(req.Width)*req.Magnification / *native)))
+	req.OutputHeight = 0
+	return nil
+}
+
+// RegionPlan is the pyramid level and level pixels a region is rendered from.
+type RegionPlan struct {
+	Level int
+	Scale int
+	// Level pixels covering the region
+	Bounds image.Rectangle
+	// Exact region in Bounds-relative level pixels
+	Source       [4]float64
+	OutputWidth  int
+	OutputHeight int
+}
+
+func (p *Pyramid) PlanRegion(req RegionRequest, maxPixels int) (*RegionPlan, error) {
+	if req.Width <= 0 || req.Height <= 0 || req.X < 0 || req.Y < 0 || req.X >= p.Width || req.Y >= p.Height {
+		return nil, errors.NewBadRequestError("region is outside the image", map[string]interface{}{
+			"x": req.X, "y": req.Y, "width": req.Width, "height": req.Height,
+			"image_width": p.Width, "image_height": p.Height,
+		})
+	}
+	w, h := min(req.Width, p.Width-req.X), min(req.Height, p.Height-req.Y)
+
+	outW, outH := req.OutputWidth, req.OutputHeight
+	switch {
+	case outW <= 0 && outH <= 0:
+		outW, outH = w, h
+	case outH <= 0:
+		outH = max(1, int(math.Round(float64(h)*float64(outW)/float64(w))))
+	case outW <= 0:
+		outW = max(1, int(math.Round(float64(w)*float64(outH)/float64(h))))
+	}
+	if outW > w || outH > h {
+		return nil, errors.NewBadRequestError("output is larger than the region at full resolution", map[string]interface{}{
+			"output_width": outW, "output_height": outH, "width": w, "height": h,
+		})
+	}
+	if outW*outH > maxPixels {
+		return nil, errors.NewBadRequestError("output exceeds the pixel limit", map[string]interface{}{
+			"output_width": outW, "output_height": outH, "max_pixels": maxPixels,
+		})
+	}
+
+	// Coarsest level still at or above the output resolution on the more
+	// reduced axis. The other axis is upsampled when the output aspect ratio
+	// differs, which keeps the level pixels read proportional to the output.
+	scale := 1
+	downsample := math.Max(float64(w)/float64(outW), float64(h)/float64(outH))
+	for scale*2 <= 1<<p.MaxLevel() && float64(scale*2) <= downsample {
+		scale *= 2
+	}
+
+	level := p.MaxLevel()
+	for f := scale; f > 1; f /= 2 {
+		level--
+	}
+
+	levelW, levelH := ceilDiv(p.Width, scale), ceilDiv(p.Height, scale)
+	x0, y0 := float64(req.X)/float64(scale), float64(req.Y)/float64(scale)
+	x1, y1 := float64(req.X+w)/float64(scale), float64(req.Y+h)/float64(scale)
+	bounds := image.Rect(int(x0), int(y0), min(levelW, int(math.Ceil(x1))), min(levelH, int(math.Ceil(y1))))
+	if bounds.Dx()*bounds.Dy() > maxRegionSourceFactor*maxPixels {
+		return nil, errors.NewBadRequestError("region needs too many source pixels", map[string]interface{}{
+			"output_width": outW, "output_height": outH, "max_pixels": maxPixels,
+		})
+	}
+
+	return &RegionPlan{
+		Level:        level,
+		Scale:        scale,
+		Bounds:       bounds,
+		Source:       [4]float64{x0 - float64(bounds.Min.X), y0 - float64(bounds.Min.Y), x1 - x0, y1 - y0},
+		OutputWidth:  outW,
+		OutputHeight: outH,
+	}, nil
+}
+
+// assemble stitches the level tiles covering plan.Bounds.
+func (s *TileServer) assemble(ctx context.Context, imageID string, pyramid *Pyramid, plan *RegionPlan) (*image.RGBA, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, plan.Bounds.Dx(), plan.Bounds.Dy()))
+	ts := pyramid.TileSize
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(regionFetchConcurrency)
+
+	for row := plan.Bounds.Min.Y / ts; row <= (plan.Bounds.Max.Y-1)/ts; row++ {
+		for col := plan.Bounds.Min.X / ts; col <= (plan.Bounds.Max.X-1)/ts; col++ {
+			ref := TileRef{Level: plan.Level, Col: col, Row: row}
+			g.Go(func() error {
+				tile, err := s.decodeTile(gctx, imageID, ref.Path(pyramid.Format))
+				if err != nil {
+					return err
+				}
+
+				// Tiles past the first column or row start with the overlap
+				origin := image.Pt(ref.Col*ts, ref.Row*ts)
+				if ref.Col > 0 {
+					origin.X -= pyramid.Overlap
+				}
+				if ref.Row > 0 {
+					origin.Y -= pyramid.Overlap
+				}
+				dst := tile.Bounds().Sub(tile.Bounds().Min).Add(origin.Sub(plan.Bounds.Min))
+				// Tiles only share overlap pixels, which hold the same content
+				draw.Draw(canvas, dst, tile, tile.Bounds().Min, draw.Src)
+				return nil
+			})
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return canvas, nil
+}
+
+func (s *TileServer) decodeTile(ctx context.Context, imageID, path string) (image.Image, error) {
+	reader, err := s.serveTile(ctx, imageID, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to read tile", err)
+	}
+	tile, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.NewInternalError("failed to decode tile "+path, err)
+	}
+	return tile, nil
+}
+
+// resample renders the src rectangle (x, y, width, height) of img at
+// outW x outH with bilinear filtering. The level is chosen so the remaining
+// downscale is below 2, where bilinear sampling does not skip pixels.
+func resample(img *image.RGBA, src [4]float64, outW, outH int) *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, outW, outH))
+	b := img.Bounds()
+	scaleX, scaleY := src[2]/float64(outW), src[3]/float64(outH)
+
+	for oy := 0; oy < outH; oy++ {
+		sy := src[1] + (float64(oy)+0.5)*scaleY - 0.5
+		y0 := clampInt(int(math.Floor(sy)), b.Min.Y, b.Max.Y-1)
+		y1 := clampInt(y0+1, b.Min.Y, b.Max.Y-1)
+		fy := math.Max(0, math.Min(1, sy-float64(y0)))
+
+		for ox := 0; ox < outW; ox++ {
+			sx := src[0] + (float64(ox)+0.5)*scaleX - 0.5
+			x0 := clampInt(int(math.Floor(sx)), b.Min.X, b.Max.X-1)
+			x1 := clampInt(x0+1, b.Min.X, b.Max.X-1)
+			fx := math.Max(0, math.Min(1, sx-float64(x0)))
+
+			i00, i10 := img.PixOffset(x0, y0), img.PixOffset(x1, y0)
+			i01, i11 := img.PixOffset(x0, y1), img.PixOffset(x1, y1)
+			o := out.PixOffset(ox, oy)
+			for c := 0; c < 4; c++ {
+				top := float64(img.Pix[i00+c])*(1-fx) + float64(img.Pix[i10+c])*fx
+				bottom := float64(img.Pix[i01+c])*(1-fx) + float64(img.Pix[i11+c])*fx
+				out.Pix[o+c] = uint8(math.Round(top*(1-fy) + bottom*fy))
+			}
+		}
+	}
+	return out
+}
+
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
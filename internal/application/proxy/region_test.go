@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type noCache struct{ cache.Cache }
+
+func (noCache) Get(ctx context.Context, key string) (interface{}, error) { return nil, nil }
+func (noCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return nil
+}
+
+type fakeImageRepo struct {
+	port.ImageRepository
+	image *model.Image
+}
+
+func (r *fakeImageRepo) Read(ctx context.Context, id string) (*model.Image, error) {
+	return r.image, nil
+}
+
+type fakeContentRepo struct{ port.ContentRepository }
+
+func (fakeContentRepo) Read(ctx context.Context, id string) (*model.Content, error) {
+	return &model.Content{Entity: vobj.Entity{ID: id}, Path: id}, nil
+}
+
+type fakeStorage struct {
+	port.Storage
+	files map[string][]byte
+}
+
+func (s *fakeStorage) Get(ctx context.Context, content model.Content) (io.ReadCloser, error) {
+	data, ok := s.files[content.Path]
+	if !ok {
+		return nil, errors.NewNotFoundError("object not found: " + content.Path)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *fakeStorage) GetRange(ctx context.Context, content model.Content, offset int64, length int64) (io.ReadCloser, error) {
+	data, ok := s.files[content.Path]
+	if !ok {
+		return nil, errors.NewNotFoundError("object not found: " + content.Path)
+	}
+	end := min(offset+length, int64(len(data)))
+	return io.NopCloser(bytes.NewReader(data[offset:end])), nil
+}
+
+func (s *fakeStorage) GetAttributes(ctx context.Context, content model.Content) (*port.FileAttributes, error) {
+	data, ok := s.files[content.Path]
+	if !ok {
+		return nil, errors.NewNotFoundError("object not found: " + content.Path)
+	}
+	return &port.FileAttributes{Size: int64(len(data))}, nil
+}
+
+func gradient(x, y int) color.RGBA {
+	return color.RGBA{R: uint8(x), G: uint8(y), B: uint8((x + y) / 4), A: 255}
+}
+
+// newPyramidServer serves a 600x400 gradient as 256px PNG tiles with a 1px overlap.
+func newPyramidServer(t *testing.T) *TileServer {
+	pyramid := &Pyramid{Width: 600, Height: 400, TileSize: 256, Overlap: 1, Format: "png"}
+	files := map[string][]byte{
+		"image.dzi": []byte(`<Image xmlns="http://schemas.microsoft.com/deepzoom/2008" Format="png" Overlap="1" TileSize="256"><Size Width="600" Height="400"/></Image>`),
+	}
+
+	for level, sf := pyramid.MaxLevel(), 1; sf <= 4; level, sf = level-1, sf*2 {
+		levelW, levelH := ceilDiv(pyramid.Width, sf), ceilDiv(pyramid.Height, sf)
+		for row := 0; row*256 < levelH; row++ {
+			for col := 0; col*256 < levelW; col++ {
+				x0, y0 := max(0, col*256-1), max(0, row*256-1)
+				x1, y1 := min(levelW, (col+1)*256+1), min(levelH, (row+1)*256+1)
+				tile := image.NewRGBA(image.Rect(0, 0, x1-x0, y1-y0))
+				for y := y0; y < y1; y++ {
+					for x := x0; x < x1; x++ {
+						tile.SetRGBA(x-x0, y-y0, gradient(x*sf, y*sf))
+					}
+				}
+				var buf bytes.Buffer
+				assert.NoError(t, png.Encode(&buf, tile))
+				files[fmt.Sprintf("tiles/%d/%d_%d.png", level, col, row)] = buf.Bytes()
+			}
+		}
+	}
+
+	dzi, tiles := "image.dzi", "tiles/"
+	img := &model.Image{DziContentID: &dzi, TilesContentID: &tiles}
+	return NewTileServer(noCache{}, cache.NewKeyBuilder("test"), fakeContentRepo{}, &fakeImageRepo{image: img}, nil,
+		&fakeStorage{files: files}, nil, nil, 200*200)
+}
+
+func TestTileServer_RenderRegion(t *testing.T) {
+	server := newPyramidServer(t)
+	ctx := context.Background()
+
+	render := func(req RegionRequest) *image.RGBA {
+		req.Format = "png"
+		region, err := server.RenderRegion(ctx, "img-1", req)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		decoded, err := png.Decode(bytes.NewReader(region.Data))
+		assert.NoError(t, err)
+		return decoded.(*image.RGBA)
+	}
+
+	// Full resolution, across the tile corner at (256, 256)
+	out := render(RegionRequest{X: 200, Y: 200, Width: 120, Height: 100})
+	assert.Equal(t, image.Rect(0, 0, 120, 100), out.Bounds())
+	for _, p := range []image.Point{{0, 0}, {55, 55}, {56, 56}, {119, 99}} {
+		assert.Equal(t, gradient(200+p.X, 200+p.Y), out.RGBAAt(p.X, p.Y), p)
+	}
+
+	// Half size comes from the next level down
+	out = render(RegionRequest{X: 200, Y: 100, Width: 400, Height: 300, OutputWidth: 200})
+	assert.Equal(t, image.Rect(0, 0, 200, 150), out.Bounds())
+	for _, p := range []image.Point{{0, 0}, {27, 27}, {28, 28}, {199, 149}} {
+		assert.Equal(t, gradient(200+2*p.X, 100+2*p.Y), out.RGBAAt(p.X, p.Y), p)
+	}
+
+	_, err := server.RenderRegion(ctx, "img-1", RegionRequest{X: 0, Y: 0, Width: 600, Height: 400, OutputWidth: 1200})
+	assert.Error(t, err, "upscaling")
+	_, err = server.RenderRegion(ctx, "img-1", RegionRequest{X: 700, Y: 0, Width: 10, Height: 10})
+	assert.Error(t, err, "outside the image")
+	_, err = server.RenderRegion(ctx, "img-1", RegionRequest{X: 0, Y: 0, Width: 600, Height: 400})
+	assert.Error(t, err, "over the pixel limit")
+}
+
+func TestPyramid_PlanRegionBoundsSourcePixels(t *testing.T) {
+	pyramid := &Pyramid{Width: 100000, Height: 100000, TileSize: 256, Format: "png"}
+	maxPixels := 100000
+
+	// A thin output must not pick full resolution for the whole region
+	plan, err := pyramid.PlanRegion(RegionRequest{Width: 100000, Height: 100000, OutputWidth: 1, OutputHeight: 100000}, maxPixels)
+	assert.NoError(t, err)
+	assert.Greater(t, plan.Scale, 1)
+	assert.LessOrEqual(t, plan.Bounds.Dx()*plan.Bounds.Dy(), maxRegionSourceFactor*maxPixels)
+
+	for _, req := range []RegionRequest{
+		{Width: 100000, Height: 100000, OutputWidth: 300, OutputHeight: 300},
+		{X: 5000, Y: 7000, Width: 4000, Height: 30000, OutputWidth: 3, OutputHeight: 30000},
+		{Width: 1000, Height: 1000, OutputWidth: 1, OutputHeight: 1},
+	} {
+		plan, err := pyramid.PlanRegion(req, maxPixels)
+		assert.NoError(t, err, req)
+		assert.LessOrEqual(t, plan.Bounds.Dx()*plan.Bounds.Dy(), maxRegionSourceFactor*maxPixels, req)
+	}
+}
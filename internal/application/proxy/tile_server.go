@@ -1,10 +1,12 @@
 package proxy
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"image/color"
 	"io"
 	"path/filepath"
 	"strings"
@@ -33,6 +35,26 @@ type IndexMapEntry struct {
 type TileOffset struct {
 	Offset int64 `json:"offset"`
 	Length int64 `json:"length"`
+	// ZIP compression method: stored or deflate
+	Method int `json:"method"`
+}
+
+// buildLookup indexes the entries by tile key.
+func (m *IndexMap) buildLookup() {
+	m.Tiles = make(map[string]TileOffset, len(m.Entries))
+	for _, entry := range m.Entries {
+		// Normalize key: remove "image/" prefix if exists, and remove extension
+		// Entry name example: "image/image_files/12/2_0.jpg"
+		// Request path: "image_files/12/2_0.jpg" -> Key: "image_files/12/2_0"
+		key := strings.TrimPrefix(entry.Name, "image/")
+		key = strings.TrimSuffix(key, filepath.Ext(key))
+
+		m.Tiles[key] = TileOffset{
+			Offset: entry.Offset,
+			Length: entry.CompressedSize, // Use CompressedSize for reading from zip
+			Method: entry.Method,
+		}
+	}
 }
 
 type TileServer struct {
@@ -40,7 +62,15 @@ type TileServer struct {
 	keyBuilder  *cache.KeyBuilder
 	contentRepo port.ContentRepository
 	imageRepo   port.ImageRepository
-	storage     port.Storage
+	// Decides whether associated images are redacted
+	workspaceRepo port.WorkspaceRepository
+	storage       port.Storage
+	// Tile, descriptor and thumbnail payloads; nil disables payload caching
+	tiles cache.ByteCache
+	// Served for tile coordinates outside the pyramid; nil answers 404
+	blankTile *blankTiles
+	// Largest region rendering, in output pixels
+	maxRegionPixels int
 }
 
 func NewTileServer(
@@ -48,14 +78,27 @@ func NewTileServer(
 	keyBuilder *cache.KeyBuilder,
 	contentRepo port.ContentRepository,
 	imageRepo port.ImageRepository,
+	workspaceRepo port.WorkspaceRepository,
 	storage port.Storage,
+	tiles cache.ByteCache,
+	blankColor color.Color,
+	maxRegionPixels int,
 ) *TileServer {
+	var blank *blankTiles
+	if blankColor != nil {
+		blank = &blankTiles{color: blankColor}
+	}
+
 	return &TileServer{
-		cache:       cache,
-		keyBuilder:  keyBuilder,
-		contentRepo: contentRepo,
-		imageRepo:   imageRepo,
-		storage:     storage,
+		cache:           cache,
+		keyBuilder:      keyBuilder,
+		contentRepo:     contentRepo,
+		imageRepo:       imageRepo,
+		workspaceRepo:   workspaceRepo,
+		storage:         storage,
+		tiles:           tiles,
+		blankTile:       blank,
+		maxRegionPixels: maxRegionPixels,
 	}
 }
 
@@ -75,6 +118,9 @@ func (s *TileServer) ServeRequest(ctx context.Context, imageID, objectPath strin
 	case RequestTypeTile:
 		return s.serveTile(ctx, imageID, objectPath)
 
+	case RequestTypeLabel, RequestTypeMacro:
+		return s.serveAssociated(ctx, imageID, requestType)
+
 	default:
 		return nil, errors.NewBadRequestError(
 			fmt.Sprintf("unknown request type for path: %s", objectPath),
@@ -91,6 +137,8 @@ const (
 	RequestTypeThumbnail
 	RequestTypeIndexMap
 	RequestTypeTile
+	RequestTypeLabel
+	RequestTypeMacro
 )
 
 func (s *TileServer) determineRequestType(objectPath string) RequestType {
@@ -106,6 +154,12 @@ func (s *TileServer) determineRequestType(objectPath string) RequestType {
 	case strings.HasSuffix(lowerPath, "indexmap.json"):
 		return RequestTypeIndexMap
 
+	case strings.HasPrefix(lowerPath, "label."):
+		return RequestTypeLabel
+
+	case strings.HasPrefix(lowerPath, "macro."):
+		return RequestTypeMacro
+
 	case strings.Contains(objectPath, "/"): // Tile requests: "0/0_0.jpeg", "1/1_2.jpeg"
 		return RequestTypeTile
 
@@ -124,12 +178,9 @@ func (s *TileServer) serveDZI(ctx context.Context, imageID string) (io.ReadClose
 		return nil, errors.NewNotFoundError("DZI content not found for image")
 	}
 
-	content, err := s.getContentMetadata(ctx, *image.DziContentID)
-	if err != nil {
-		return nil, err
-	}
-
-	return s.storage.Get(ctx, *content)
+	return s.readThrough(ctx, imageID, *image.DziContentID, "dzi", func() (io.ReadCloser, error) {
+		return s.getContent(ctx, *image.DziContentID)
+	})
 }
 
 func (s *TileServer) serveThumbnail(ctx context.Context, imageID string) (io.ReadCloser, error) {
@@ -142,11 +193,16 @@ func (s *TileServer) serveThumbnail(ctx context.Context, imageID string) (io.Rea
 		return nil, errors.NewNotFoundError("thumbnail content not found for image")
 	}
 
-	content, err := s.getContentMetadata(ctx, *image.ThumbnailContentID)
+	return s.readThrough(ctx, imageID, *image.ThumbnailContentID, "thumbnail", func() (io.ReadCloser, error) {
+		return s.getContent(ctx, *image.ThumbnailContentID)
+	})
+}
+
+func (s *TileServer) getContent(ctx context.Context, contentID string) (io.ReadCloser, error) {
+	content, err := s.getContentMetadata(ctx, contentID)
 	if err != nil {
 		return nil, err
 	}
-
 	return s.storage.Get(ctx, *content)
 }
 
@@ -174,16 +230,65 @@ func (s *TileServer) serveTile(ctx context.Context, imageID, tilePath string) (i
 		return nil, err
 	}
 
-	usesArchive := image.ZipTilesContentID != nil
-	usesTilesDir := image.TilesContentID != nil
+	if blank, ok := s.blankFor(ctx, imageID, tilePath); ok {
+		return io.NopCloser(bytes.NewReader(blank)), nil
+	}
+
+	var contentID string
+	var load func() (io.ReadCloser, error)
+	switch {
+	case image.ZipTilesContentID != nil:
+		contentID = *image.ZipTilesContentID
+		load = func() (io.ReadCloser, error) {
+			return s.serveTileFromArchive(ctx, imageID, contentID, tilePath)
+		}
+	case image.TilesContentID != nil:
+		contentID = *image.TilesContentID
+		load = func() (io.ReadCloser, error) {
+			return s.serveTileFromDirectory(ctx, contentID, tilePath)
+		}
+	default:
+		return nil, errors.NewNotFoundError("no tile storage configured for image")
+	}
+
+	return s.readThrough(ctx, imageID, contentID, tilePath, load)
+}
+
+// readThrough serves name from contentID out of the payload cache, loading
+// and caching it on a miss.
+func (s *TileServer) readThrough(ctx context.Context, imageID, contentID, name string, load func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	if s.tiles == nil {
+		return load()
+	}
+
+	// Reprocessing writes new contents, so stale payloads are never hit again
+	cacheKey := s.keyBuilder.Build("tile", contentID, name)
+	if data, ok := s.tiles.Get(ctx, imageID, cacheKey); ok {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
 
-	if usesArchive {
-		return s.serveTileFromArchive(ctx, imageID, *image.ZipTilesContentID, tilePath)
-	} else if usesTilesDir {
-		return s.serveTileFromDirectory(ctx, *image.TilesContentID, tilePath)
+	reader, err := load()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to read "+name, err)
 	}
+	s.tiles.Set(ctx, imageID, cacheKey, data)
 
-	return nil, errors.NewNotFoundError("no tile storage configured for image")
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// GetTileStats returns the tile payload cache statistics, nil when tiles
+// are not cached.
+func (s *TileServer) GetTileStats(ctx context.Context) (*cache.Stats, error) {
+	if s.tiles == nil {
+		return nil, nil
+	}
+	return s.tiles.GetStats(ctx)
 }
 
 func (s *TileServer) serveTileFromDirectory(ctx context.Context, tilesContentID, tilePath string) (io.ReadCloser, error) {
@@ -212,7 +317,11 @@ func (s *TileServer) serveTileFromArchive(ctx context.Context, imageID, archiveC
 
 	indexMap, err := s.getIndexMap(ctx, imageID)
 	if err != nil {
-		return nil, err
+		// The archive's central directory lists the same entries
+		indexMap, err = s.getZipDirectory(ctx, imageID, archiveContent)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	tileKey := strings.TrimSuffix(tilePath, filepath.Ext(tilePath))
@@ -249,7 +358,11 @@ func (s *TileServer) serveTileFromArchive(ctx context.Context, imageID, archiveC
 		dataOffset += 30 + nameLen + extraLen
 	}
 
-	return s.storage.GetRange(ctx, *archiveContent, dataOffset, tileOffset.Length)
+	data, err := s.storage.GetRange(ctx, *archiveContent, dataOffset, tileOffset.Length)
+	if err != nil {
+		return nil, err
+	}
+	return entryReader(data, tileOffset.Method)
 }
 
 func (s *TileServer) getImage(ctx context.Context, imageID string) (*model.Image, error) {
@@ -263,8 +376,14 @@ func (s *TileServer) getImage(ctx context.Context, imageID string) (*model.Image
 
 	image, err := s.imageRepo.Read(ctx, imageID)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFoundError("image not found")
+		}
 		return nil, errors.NewInternalError("failed to read image", err)
 	}
+	if image.IsDeleted() {
+		return nil, errors.NewNotFoundError("image not found")
+	}
 
 	_ = s.cache.Set(ctx, cacheKey, image, 10*time.Minute)
 
@@ -282,6 +401,9 @@ func (s *TileServer) getContentMetadata(ctx context.Context, contentID string) (
 
 	content, err := s.contentRepo.Read(ctx, contentID)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFoundError("content not found")
+		}
 		return nil, errors.NewInternalError("failed to read content metadata", err)
 	}
 
@@ -324,29 +446,7 @@ func (s *TileServer) getIndexMap(ctx context.Context, imageID string) (*IndexMap
 		return nil, errors.NewInternalError("failed to parse index map JSON", err)
 	}
 
-	// Populate lookup map
-	indexMap.Tiles = make(map[string]TileOffset)
-	for _, entry := range indexMap.Entries {
-		// Normalize key: remove "image/" prefix if exists, and remove extension
-		// Entry name example: "image/image_files/12/2_0.jpg"
-		// Request path: "image_files/12/2_0.jpg" -> Key: "image_files/12/2_0"
-
-		key := entry.Name
-		if strings.HasPrefix(key, "image/") {
-			key = strings.TrimPrefix(key, "image/")
-		}
-
-		// Remove extension
-		ext := filepath.Ext(key)
-		if ext != "" {
-			key = strings.TrimSuffix(key, ext)
-		}
-
-		indexMap.Tiles[key] = TileOffset{
-			Offset: entry.Offset,
-			Length: entry.CompressedSize, // Use CompressedSize for reading from zip
-		}
-	}
+	indexMap.buildLookup()
 
 	_ = s.cache.Set(ctx, cacheKey, &indexMap, 30*time.Minute)
 
@@ -357,11 +457,16 @@ func (s *TileServer) InvalidateImage(ctx context.Context, imageID string) error
 	patterns := []string{
 		s.keyBuilder.BuildPattern("image", "*", imageID),
 		s.keyBuilder.BuildPattern("indexmap", imageID),
+		s.keyBuilder.BuildPattern("pyramid", imageID),
+		s.keyBuilder.BuildPattern("zipdir", imageID),
 	}
 
 	for _, pattern := range patterns {
 		_, _ = s.cache.DeletePattern(ctx, pattern)
 	}
+	if s.tiles != nil {
+		s.tiles.DeleteImage(ctx, imageID)
+	}
 
 	return nil
 }
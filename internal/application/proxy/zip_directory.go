@@ -0,0 +1,208 @@
+package proxy
+
+import (
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// ZIP record signatures and sizes
+const (
+	zipEOCDSignature         = 0x06054b50
+	zip64EOCDSignature       = 0x06064b50
+	zip64LocatorSignature    = 0x07064b50
+	zipCentralDirSignature   = 0x02014b50
+	zipEOCDLen               = 22
+	zip64EOCDLen             = 56
+	zip64LocatorLen          = 20
+	zipCentralHeaderLen      = 46
+	zipMaxCommentLen         = 0xffff
+	zip64ExtraID             = 0x0001
+	zipMethodStore           = 0
+	zipMethodDeflate         = 8
+	zipCentralDirectoryLimit = 512 << 20
+)
+
+// getZipDirectory builds the tile lookup from the archive's own central
+// directory, for images whose index map is missing or unreadable.
+func (s *TileServer) getZipDirectory(ctx context.Context, imageID string, archive *model.Content) (*IndexMap, error) {
+	cacheKey := s.keyBuilder.Build("zipdir", imageID)
+
+	if val, err := s.cache.Get(ctx, cacheKey); err == nil && val != nil {
+		if indexMap, ok := val.(*IndexMap); ok {
+			return indexMap, nil
+		}
+	}
+
+	size := archive.Size
+	if size <= 0 {
+		attrs, err := s.storage.GetAttributes(ctx, *archive)
+		if err != nil {
+			return nil, errors.NewInternalError("failed to read tile archive size", err)
+		}
+		size = attrs.Size
+	}
+
+	entries, err := readZipDirectory(func(offset, length int64) ([]byte, error) {
+		reader, err := s.storage.GetRange(ctx, *archive, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}, size)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to read tile archive directory", err)
+	}
+
+	indexMap := &IndexMap{Entries: entries}
+	indexMap.buildLookup()
+
+	_ = s.cache.Set(ctx, cacheKey, indexMap, 30*time.Minute)
+
+	return indexMap, nil
+}
+
+// readZipDirectory lists the entries of a zip archive of the given size,
+// reading only its tail and central directory through readAt.
+func readZipDirectory(readAt func(offset, length int64) ([]byte, error), size int64) ([]IndexMapEntry, error) {
+	tailLen := min(size, zipEOCDLen+zipMaxCommentLen+zip64LocatorLen)
+	tail, err := readAt(size-tailLen, tailLen)
+	if err != nil {
+		return nil, err
+	}
+
+	// The end of central directory record is followed only by its comment
+	eocd := -1
+	for i := len(tail) - zipEOCDLen; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) == zipEOCDSignature {
+			eocd = i
+			break
+		}
+	}
+	if eocd < 0 {
+		return nil, fmt.Errorf("end of central directory not found")
+	}
+
+	record := tail[eocd:]
+	count := int64(binary.LittleEndian.Uint16(record[10:]))
+	dirSize := int64(binary.LittleEndian.Uint32(record[12:]))
+	dirOffset := int64(binary.LittleEndian.Uint32(record[16:]))
+
+	// ZIP64 archives saturate these fields; an archive of exactly 65535
+	// entries saturates the count without having a ZIP64 record
+	locator := eocd - zip64LocatorLen
+	hasZip64 := locator >= 0 && binary.LittleEndian.Uint32(tail[locator:]) == zip64LocatorSignature
+	if hasZip64 {
+		zip64Offset := int64(binary.LittleEndian.Uint64(tail[locator+8:]))
+
+		record, err := readAt(zip64Offset, zip64EOCDLen)
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < zip64EOCDLen || binary.LittleEndian.Uint32(record) != zip64EOCDSignature {
+			return nil, fmt.Errorf("invalid zip64 end of central directory")
+		}
+		count = int64(binary.LittleEndian.Uint64(record[32:]))
+		dirSize = int64(binary.LittleEndian.Uint64(record[40:]))
+		dirOffset = int64(binary.LittleEndian.Uint64(record[48:]))
+	} else if dirSize == 0xffffffff || dirOffset == 0xffffffff {
+		return nil, fmt.Errorf("zip64 end of central directory locator not found")
+	}
+
+	if dirSize > zipCentralDirectoryLimit || dirOffset+dirSize > size {
+		return nil, fmt.Errorf("central directory out of bounds")
+	}
+	dir, err := readAt(dirOffset, dirSize)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]IndexMapEntry, 0, min(count, dirSize/zipCentralHeaderLen))
+	for pos := 0; pos+zipCentralHeaderLen <= len(dir); {
+		header := dir[pos:]
+		if binary.LittleEndian.Uint32(header) != zipCentralDirSignature {
+			return nil, fmt.Errorf("invalid central directory header at %d", pos)
+		}
+		nameLen := int(binary.LittleEndian.Uint16(header[28:]))
+		extraLen := int(binary.LittleEndian.Uint16(header[30:]))
+		commentLen := int(binary.LittleEndian.Uint16(header[32:]))
+		end := zipCentralHeaderLen + nameLen + extraLen + commentLen
+		if end > len(header) {
+			return nil, fmt.Errorf("truncated central directory header at %d", pos)
+		}
+
+		entry := IndexMapEntry{
+			Name:             string(header[zipCentralHeaderLen : zipCentralHeaderLen+nameLen]),
+			Method:           int(binary.LittleEndian.Uint16(header[10:])),
+			CompressedSize:   int64(binary.LittleEndian.Uint32(header[20:])),
+			UncompressedSize: int64(binary.LittleEndian.Uint32(header[24:])),
+			Offset:           int64(binary.LittleEndian.Uint32(header[42:])),
+		}
+		applyZip64Extra(&entry, header[zipCentralHeaderLen+nameLen:zipCentralHeaderLen+nameLen+extraLen])
+
+		entries = append(entries, entry)
+		pos += end
+	}
+
+	return entries, nil
+}
+
+// applyZip64Extra replaces the saturated 32-bit fields of an entry with
+// their values from the ZIP64 extra field, which lists only those, in order.
+func applyZip64Extra(entry *IndexMapEntry, extra []byte) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		if 4+size > len(extra) {
+			return
+		}
+		if id == zip64ExtraID {
+			field := extra[4 : 4+size]
+			for _, value := range []*int64{&entry.UncompressedSize, &entry.CompressedSize, &entry.Offset} {
+				if *value != 0xffffffff {
+					continue
+				}
+				if len(field) < 8 {
+					return
+				}
+				*value = int64(binary.LittleEndian.Uint64(field))
+				field = field[8:]
+			}
+			return
+		}
+		extra = extra[4+size:]
+	}
+}
+
+// entryReader decodes a zip entry's data according to its method.
+func entryReader(data io.ReadCloser, method int) (io.ReadCloser, error) {
+	switch method {
+	case zipMethodStore:
+		return data, nil
+	case zipMethodDeflate:
+		return &deflateReader{ReadCloser: flate.NewReader(data), source: data}, nil
+	default:
+		_ = data.Close()
+		return nil, errors.NewInternalError(fmt.Sprintf("unsupported zip compression method %d", method), nil)
+	}
+}
+
+type deflateReader struct {
+	io.ReadCloser
+	source io.Closer
+}
+
+func (r *deflateReader) Close() error {
+	err := r.ReadCloser.Close()
+	if sourceErr := r.source.Close(); err == nil {
+		err = sourceErr
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTileServer_ServeTileWithoutIndexMap(t *testing.T) {
+	tests := []struct {
+		name string
+		// Filler entries; more than 65535 forces a ZIP64 directory
+		filler int
+	}{
+		{"zip", 0},
+		{"zip64", 70000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := bytes.Repeat([]byte("stored tile "), 50)
+			deflated := bytes.Repeat([]byte("deflated tile "), 50)
+
+			var buf bytes.Buffer
+			w := zip.NewWriter(&buf)
+			for i := 0; i < tt.filler; i++ {
+				_, err := w.CreateHeader(&zip.FileHeader{Name: fmt.Sprintf("image/image_files/0/%d.txt", i), Method: zip.Store})
+				assert.NoError(t, err)
+			}
+			for _, entry := range []struct {
+				name   string
+				method uint16
+				data   []byte
+			}{
+				{"image/image_files/12/0_0.jpg", zip.Store, stored},
+				{"image/image_files/12/1_0.jpg", zip.Deflate, deflated},
+			} {
+				f, err := w.CreateHeader(&zip.FileHeader{Name: entry.name, Method: entry.method})
+				assert.NoError(t, err)
+				_, err = f.Write(entry.data)
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, w.SetComment("tiles"))
+			assert.NoError(t, w.Close())
+
+			archive, indexMap := "tiles.zip", "indexmap.json"
+			server := NewTileServer(noCache{}, cache.NewKeyBuilder("test"), fakeContentRepo{},
+				// The index map is configured but missing from storage
+				&fakeImageRepo{image: &model.Image{ZipTilesContentID: &archive, IndexmapContentID: &indexMap}}, nil,
+				&fakeStorage{files: map[string][]byte{archive: buf.Bytes()}}, nil, nil, 0)
+
+			for path, want := range map[string][]byte{
+				"image_files/12/0_0.jpg": stored,
+				"image_files/12/1_0.jpg": deflated,
+			} {
+				reader, err := server.ServeRequest(context.Background(), "img-1", path)
+				if assert.NoError(t, err, path) {
+					got, err := io.ReadAll(reader)
+					assert.NoError(t, err)
+					assert.NoError(t, reader.Close())
+					assert.Equal(t, want, got, path)
+				}
+			}
+
+			_, err := server.ServeRequest(context.Background(), "img-1", "image_files/12/9_9.jpg")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -10,10 +10,11 @@ type AnnotationQuery struct {
 	*HierarchicalQueries[*model.Annotation]
 }
 
-func NewAnnotationQuery(repo port.AnnotationRepository) *AnnotationQuery {
+func NewAnnotationQuery(repo port.AnnotationRepository, uow port.UnitOfWorkFactory) *AnnotationQuery {
 	return &AnnotationQuery{
 		BaseQuery: &BaseQuery[*model.Annotation]{
 			repo: repo,
+			uow:  uow,
 		},
 		HierarchicalQueries: &HierarchicalQueries[*model.Annotation]{
 			repo: repo,
@@ -10,10 +10,11 @@ type AnnotationTypeQuery struct {
 	*HierarchicalQueries[*model.AnnotationType]
 }
 
-func NewAnnotationTypeQuery(repo port.AnnotationTypeRepository) *AnnotationTypeQuery {
+func NewAnnotationTypeQuery(repo port.AnnotationTypeRepository, uow port.UnitOfWorkFactory) *AnnotationTypeQuery {
 	return &AnnotationTypeQuery{
 		BaseQuery: &BaseQuery[*model.AnnotationType]{
 			repo: repo,
+			uow:  uow,
 		},
 		HierarchicalQueries: &HierarchicalQueries[*model.AnnotationType]{
 			repo: repo,
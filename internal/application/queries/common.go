@@ -2,7 +2,9 @@ package queries
 
 import (
 	"context"
+	"slices"
 
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/fields"
 	"github.com/histopathai/main-service/internal/port"
 	"github.com/histopathai/main-service/internal/shared/query"
@@ -13,6 +15,8 @@ import (
 // ======================================
 type BaseQuery[T port.Entity] struct {
 	repo port.Repository[T]
+	// uow is optional; when set, soft deletes record a deleted event in the outbox
+	uow port.UnitOfWorkFactory
 }
 
 func (s *BaseQuery[T]) Get(ctx context.Context, id string) (T, error) {
@@ -20,11 +24,58 @@ func (s *BaseQuery[T]) Get(ctx context.Context, id string) (T, error) {
 }
 
 func (s *BaseQuery[T]) SoftDelete(ctx context.Context, id string) error {
-	return s.repo.SoftDelete(ctx, id)
+	if s.uow == nil {
+		return s.repo.SoftDelete(ctx, id)
+	}
+	return s.softDeleteWithEvents(ctx, []string{id})
 }
 
 func (s *BaseQuery[T]) SoftDeleteMany(ctx context.Context, ids []string) error {
-	return s.repo.SoftDeleteMany(ctx, ids)
+	if s.uow == nil {
+		return s.repo.SoftDeleteMany(ctx, ids)
+	}
+	return s.softDeleteWithEvents(ctx, ids)
+}
+
+// Entities soft deleted per transaction. Each takes an update and an outbox
+// write, which keeps a transaction under Firestore's 500 write limit.
+const softDeleteChunkSize = 200
+
+// softDeleteWithEvents deletes ids in chunks of softDeleteChunkSize, one
+// transaction each. Chunks committed before a failing one stay deleted.
+func (s *BaseQuery[T]) softDeleteWithEvents(ctx context.Context, ids []string) error {
+	for chunk := range slices.Chunk(ids, softDeleteChunkSize) {
+		if err := s.softDeleteChunk(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BaseQuery[T]) softDeleteChunk(ctx context.Context, ids []string) error {
+	return s.uow.WithTx(ctx, func(txCtx context.Context) error {
+		// Reads must come before writes in a transaction
+		entities := make([]T, 0, len(ids))
+		for _, id := range ids {
+			entity, err := s.repo.Read(txCtx, id)
+			if err != nil {
+				return err
+			}
+			entities = append(entities, entity)
+		}
+
+		if err := s.repo.SoftDeleteMany(txCtx, ids); err != nil {
+			return err
+		}
+
+		outbox := s.uow.GetOutboxRepo()
+		for _, entity := range entities {
+			if err := outbox.Add(txCtx, domainevent.NewEntityEvent(domainevent.EntityDeleted, entity)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (s *BaseQuery[T]) List(ctx context.Context, spec query.Specification) (*query.Result[T], error) {
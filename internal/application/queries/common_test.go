@@ -0,0 +1,54 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingUOW struct {
+	port.UnitOfWorkFactory
+	outbox *fakeOutbox
+	txs    int
+}
+
+func (u *countingUOW) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	u.txs++
+	return fn(ctx)
+}
+
+func (u *countingUOW) GetOutboxRepo() port.OutboxRepository { return u.outbox }
+
+type fakePatientRepo struct {
+	port.PatientRepository
+	deleteBatches []int
+}
+
+func (r *fakePatientRepo) Read(ctx context.Context, id string) (*model.Patient, error) {
+	return &model.Patient{Entity: vobj.Entity{ID: id, EntityType: vobj.EntityTypePatient}}, nil
+}
+
+func (r *fakePatientRepo) SoftDeleteMany(ctx context.Context, ids []string) error {
+	r.deleteBatches = append(r.deleteBatches, len(ids))
+	return nil
+}
+
+func TestBaseQuery_SoftDeleteManyChunksTransactions(t *testing.T) {
+	ids := make([]string, 2*softDeleteChunkSize+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("p-%d", i)
+	}
+	repo := &fakePatientRepo{}
+	uow := &countingUOW{outbox: &fakeOutbox{}}
+	q := &BaseQuery[*model.Patient]{repo: repo, uow: uow}
+
+	assert.NoError(t, q.SoftDeleteMany(context.Background(), ids))
+	assert.Equal(t, 3, uow.txs)
+	assert.Equal(t, []int{softDeleteChunkSize, softDeleteChunkSize, 1}, repo.deleteBatches)
+	assert.Len(t, uow.outbox.events, len(ids))
+}
@@ -0,0 +1,20 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+)
+
+type EventLogQuery struct {
+	repo port.EventLogRepository
+}
+
+func NewEventLogQuery(repo port.EventLogRepository) *EventLogQuery {
+	return &EventLogQuery{repo: repo}
+}
+
+func (q *EventLogQuery) List(ctx context.Context, filter port.EventLogFilter) ([]*model.EventLogEntry, error) {
+	return q.repo.Find(ctx, filter)
+}
@@ -0,0 +1,179 @@
+package queries
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+const eventStreamBatchSize = 100
+
+// How far behind the newest event a stream re-reads, i.e. how long an event
+// may take to commit after it was timestamped and still be delivered
+const eventStreamLookback = 30 * time.Second
+
+// EventStreamQuery tails the outbox of a workspace. Reading the outbox rather
+// than an in-process bus means every instance sees every event, and a client
+// can resume from any event it has already received.
+type EventStreamQuery struct {
+	outbox       port.OutboxRepository
+	pollInterval time.Duration
+}
+
+func NewEventStreamQuery(outbox port.OutboxRepository, pollInterval time.Duration) *EventStreamQuery {
+	return &EventStreamQuery{
+		outbox:       outbox,
+		pollInterval: pollInterval,
+	}
+}
+
+// workspaceEventStream implements port.EventStream.
+type workspaceEventStream struct {
+	events chan domainevent.Event
+	err    error
+}
+
+func (s *workspaceEventStream) Events() <-chan domainevent.Event { return s.events }
+
+func (s *workspaceEventStream) Err() error { return s.err }
+
+// Stream starts tailing the workspace's live events until ctx is done or a
+// read fails. With a lastEventID the stream resumes right after that event;
+// an unknown ID starts from now, as the client cannot be helped further.
+//
+// An event is timestamped before its transaction commits, so it can become
+// visible after newer ones. Every poll therefore re-reads eventStreamLookback
+// behind the newest event seen and skips the IDs it already sent.
+func (q *EventStreamQuery) Stream(ctx context.Context, wsID string, lastEventID string) (port.EventStream, error) {
+	cursor := time.Now()
+	resumeID := ""
+
+	if lastEventID != "" {
+		last, err := q.outbox.Get(ctx, lastEventID)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if entityEvent, ok := last.(*domainevent.EntityEvent); ok && entityEvent.WsID == wsID {
+			cursor = entityEvent.Timestamp
+			resumeID = lastEventID
+		}
+	}
+
+	// Events from before the starting point are not sent again
+	seen := map[string]time.Time{}
+	err := q.scan(ctx, wsID, cursor.Add(-eventStreamLookback), func(event domainevent.Event) error {
+		if event.GetTimestamp().Before(cursor) || event.GetEventID() == resumeID {
+			seen[event.GetEventID()] = event.GetTimestamp()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &workspaceEventStream{events: make(chan domainevent.Event)}
+
+	go func() {
+		defer close(stream.events)
+
+		ticker := time.NewTicker(q.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			err := q.scan(ctx, wsID, cursor.Add(-eventStreamLookback), func(event domainevent.Event) error {
+				if _, ok := seen[event.GetEventID()]; ok {
+					return nil
+				}
+				ts := event.GetTimestamp()
+				seen[event.GetEventID()] = ts
+				if ts.After(cursor) {
+					cursor = ts
+				}
+
+				if !isLiveWorkspaceEvent(event) {
+					return nil
+				}
+				select {
+				case stream.events <- event:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+			if err != nil {
+				if ctx.Err() == nil {
+					stream.err = err
+				}
+				return
+			}
+
+			// Events behind the window are not read again
+			horizon := cursor.Add(-eventStreamLookback)
+			for id, ts := range seen {
+				if ts.Before(horizon) {
+					delete(seen, id)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// scan pages through the workspace's events from since onwards, oldest first.
+func (q *EventStreamQuery) scan(ctx context.Context, wsID string, since time.Time, fn func(domainevent.Event) error) error {
+	after, afterID := since, ""
+	for {
+		events, err := q.outbox.ListByWorkspace(ctx, wsID, after, afterID, eventStreamBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+			after, afterID = event.GetTimestamp(), event.GetEventID()
+		}
+
+		if len(events) < eventStreamBatchSize {
+			return nil
+		}
+	}
+}
+
+// isLiveWorkspaceEvent selects what viewers need to refresh without polling:
+// image processing status transitions and annotation changes.
+func isLiveWorkspaceEvent(event domainevent.Event) bool {
+	e, ok := event.(*domainevent.EntityEvent)
+	if !ok {
+		return false
+	}
+
+	switch e.EntityType {
+	case vobj.EntityTypeAnnotation:
+		return e.Action == domainevent.EntityCreated ||
+			e.Action == domainevent.EntityUpdated ||
+			e.Action == domainevent.EntityDeleted
+	case vobj.EntityTypeImage:
+		if e.Action == domainevent.EntityProcessed {
+			return true
+		}
+		return e.Action == domainevent.EntityUpdated &&
+			slices.Contains(e.ChangedFields, fields.ImageProcessingStatus.DomainName())
+	default:
+		return false
+	}
+}
@@ -0,0 +1,163 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOutbox struct {
+	port.OutboxRepository
+	mu     sync.Mutex
+	events []*domainevent.EntityEvent
+}
+
+func (o *fakeOutbox) Add(ctx context.Context, event domainevent.Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event.(*domainevent.EntityEvent))
+	return nil
+}
+
+func (o *fakeOutbox) Get(ctx context.Context, eventID string) (domainevent.Event, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, e := range o.events {
+		if e.EventID == eventID {
+			return e, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("document not found")
+}
+
+func (o *fakeOutbox) ListByWorkspace(ctx context.Context, wsID string, after time.Time, afterID string, limit int) ([]domainevent.Event, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var matched []*domainevent.EntityEvent
+	for _, e := range o.events {
+		if e.WsID != wsID || e.Timestamp.Before(after) {
+			continue
+		}
+		if afterID != "" && e.Timestamp.Equal(after) && e.EventID <= afterID {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].Timestamp.Equal(matched[j].Timestamp) {
+			return matched[i].Timestamp.Before(matched[j].Timestamp)
+		}
+		return matched[i].EventID < matched[j].EventID
+	})
+	out := []domainevent.Event{}
+	for i := 0; i < len(matched) && i < limit; i++ {
+		out = append(out, matched[i])
+	}
+	return out, nil
+}
+
+func testEntityEvent(id string, ts time.Time, entityType vobj.EntityType, action domainevent.EntityAction, changed ...string) *domainevent.EntityEvent {
+	return &domainevent.EntityEvent{
+		BaseEvent:     domainevent.BaseEvent{EventID: id, EventType: domainevent.EntityEventType(entityType, action), Timestamp: ts},
+		Action:        action,
+		EntityType:    entityType,
+		EntityID:      id + "-entity",
+		WsID:          "ws-1",
+		ChangedFields: changed,
+	}
+}
+
+func TestEventStreamQuery_ResumesAfterLastEventAndFilters(t *testing.T) {
+	base := time.Now().Add(-time.Minute)
+	outbox := &fakeOutbox{events: []*domainevent.EntityEvent{
+		testEntityEvent("e1", base, vobj.EntityTypeAnnotation, domainevent.EntityCreated),
+		// Same timestamp as the resume point: must still be delivered
+		testEntityEvent("e2", base, vobj.EntityTypeAnnotation, domainevent.EntityUpdated),
+		testEntityEvent("e3", base.Add(time.Second), vobj.EntityTypePatient, domainevent.EntityCreated),
+		testEntityEvent("e4", base.Add(2*time.Second), vobj.EntityTypeImage, domainevent.EntityUpdated, fields.EntityName.DomainName()),
+		testEntityEvent("e5", base.Add(3*time.Second), vobj.EntityTypeImage, domainevent.EntityUpdated, fields.ImageProcessingStatus.DomainName()),
+		testEntityEvent("e6", base.Add(4*time.Second), vobj.EntityTypeImage, domainevent.EntityProcessed),
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := NewEventStreamQuery(outbox, 10*time.Millisecond).Stream(ctx, "ws-1", "e1")
+	assert.NoError(t, err)
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case e := <-stream.Events():
+			got = append(got, e.GetEventID())
+		case <-time.After(time.Second):
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+	assert.Equal(t, []string{"e2", "e5", "e6"}, got)
+
+	// New events are picked up live
+	_ = outbox.Add(ctx, testEntityEvent("e7", time.Now(), vobj.EntityTypeAnnotation, domainevent.EntityDeleted))
+	select {
+	case e := <-stream.Events():
+		assert.Equal(t, "e7", e.GetEventID())
+	case <-time.After(time.Second):
+		t.Fatal("live event not delivered")
+	}
+
+	cancel()
+	for range stream.Events() {
+	}
+	assert.NoError(t, stream.Err())
+}
+
+func TestEventStreamQuery_DeliversLateCommitsAndSameTimestampBatches(t *testing.T) {
+	outbox := &fakeOutbox{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := NewEventStreamQuery(outbox, 10*time.Millisecond).Stream(ctx, "ws-1", "")
+	assert.NoError(t, err)
+
+	receive := func(n int) []string {
+		var got []string
+		for len(got) < n {
+			select {
+			case e := <-stream.Events():
+				got = append(got, e.GetEventID())
+			case <-time.After(time.Second):
+				t.Fatalf("timed out, got %d of %d", len(got), n)
+			}
+		}
+		return got
+	}
+
+	// More events than a page, all with one timestamp
+	ts := time.Now()
+	for i := range eventStreamBatchSize + 5 {
+		_ = outbox.Add(ctx, testEntityEvent(fmt.Sprintf("b%03d", i), ts, vobj.EntityTypeAnnotation, domainevent.EntityCreated))
+	}
+	got := receive(eventStreamBatchSize + 5)
+	assert.Equal(t, "b000", got[0])
+	assert.Equal(t, fmt.Sprintf("b%03d", eventStreamBatchSize+4), got[len(got)-1])
+
+	// Committed after newer events were streamed, with an older timestamp
+	_ = outbox.Add(ctx, testEntityEvent("late", ts.Add(-time.Second), vobj.EntityTypeAnnotation, domainevent.EntityUpdated))
+	assert.Equal(t, []string{"late"}, receive(1))
+
+	select {
+	case e := <-stream.Events():
+		t.Fatalf("unexpected duplicate %s", e.GetEventID())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -1,6 +1,8 @@
 package queries
 
 import (
+	"context"
+
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/port"
 )
@@ -10,13 +12,28 @@ type ImageQuery struct {
 	*HierarchicalQueries[*model.Image]
 }
 
-func NewImageQuery(repo port.ImageRepository) *ImageQuery {
+func NewImageQuery(repo port.ImageRepository, uow port.UnitOfWorkFactory) *ImageQuery {
 	return &ImageQuery{
 		BaseQuery: &BaseQuery[*model.Image]{
 			repo: repo,
+			uow:  uow,
 		},
 		HierarchicalQueries: &HierarchicalQueries[*model.Image]{
 			repo: repo,
 		},
 	}
 }
+
+func (q *ImageQuery) QueuePosition(ctx context.Context, imageID string) (int, error) {
+	queue := q.uow.GetProcessingQueueRepo()
+
+	entry, err := queue.Read(ctx, imageID)
+	if err != nil {
+		return 0, err
+	}
+	ahead, err := queue.CountAhead(ctx, entry)
+	if err != nil {
+		return 0, err
+	}
+	return ahead + 1, nil
+}
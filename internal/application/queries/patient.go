@@ -10,10 +10,11 @@ type PatientQuery struct {
 	*HierarchicalQueries[*model.Patient]
 }
 
-func NewPatientQuery(repo port.PatientRepository) *PatientQuery {
+func NewPatientQuery(repo port.PatientRepository, uow port.UnitOfWorkFactory) *PatientQuery {
 	return &PatientQuery{
 		BaseQuery: &BaseQuery[*model.Patient]{
 			repo: repo,
+			uow:  uow,
 		},
 		HierarchicalQueries: &HierarchicalQueries[*model.Patient]{
 			repo: repo,
@@ -0,0 +1,134 @@
+package queries
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+)
+
+const (
+	// Attempts aggregated per stats request
+	MaxStatsJobs      = 10000
+	DefaultStatsRange = 7 * 24 * time.Hour
+)
+
+type ProcessingQuery struct {
+	jobRepo port.ProcessingJobRepository
+}
+
+func NewProcessingQuery(jobRepo port.ProcessingJobRepository) *ProcessingQuery {
+	return &ProcessingQuery{jobRepo: jobRepo}
+}
+
+func (q *ProcessingQuery) History(ctx context.Context, imageID string, limit int) ([]*model.ProcessingJob, error) {
+	return q.jobRepo.ListByImage(ctx, imageID, limit)
+}
+
+// Stats aggregates the attempts started in the filter's window, which
+// defaults to the last DefaultStatsRange.
+func (q *ProcessingQuery) Stats(ctx context.Context, filter port.ProcessingJobFilter) (*port.ProcessingStats, error) {
+	to := time.Now()
+	if filter.To != nil {
+		to = *filter.To
+	}
+	from := to.Add(-DefaultStatsRange)
+	if filter.From != nil {
+		from = *filter.From
+	}
+	filter.From, filter.To = &from, &to
+	filter.Limit = MaxStatsJobs + 1
+
+	jobs, err := q.jobRepo.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &port.ProcessingStats{From: from, To: to}
+	if len(jobs) > MaxStatsJobs {
+		stats.Truncated = true
+		jobs = jobs[:MaxStatsJobs]
+	}
+
+	stats.Total = aggregateJobs("", jobs)
+	stats.ByWorkspace = groupJobs(jobs, func(j *model.ProcessingJob) string { return j.WsID })
+	stats.ByFormat = groupJobs(jobs, func(j *model.ProcessingJob) string { return j.Format })
+	return stats, nil
+}
+
+// groupJobs aggregates jobs per key, largest groups first.
+func groupJobs(jobs []*model.ProcessingJob, key func(*model.ProcessingJob) string) []port.ProcessingStatsGroup {
+	byKey := map[string][]*model.ProcessingJob{}
+	for _, job := range jobs {
+		byKey[key(job)] = append(byKey[key(job)], job)
+	}
+
+	groups := make([]port.ProcessingStatsGroup, 0, len(byKey))
+	for k, grouped := range byKey {
+		groups = append(groups, aggregateJobs(k, grouped))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Attempts != groups[j].Attempts {
+			return groups[i].Attempts > groups[j].Attempts
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+func aggregateJobs(key string, jobs []*model.ProcessingJob) port.ProcessingStatsGroup {
+	group := port.ProcessingStatsGroup{Key: key, Attempts: len(jobs)}
+	var waits, durations []time.Duration
+
+	for _, job := range jobs {
+		switch job.Status {
+		case model.ProcessingJobSucceeded:
+			group.Succeeded++
+		case model.ProcessingJobFailed:
+			group.Failed++
+		case model.ProcessingJobCancelled:
+			group.Cancelled++
+		default:
+			group.Running++
+		}
+		if wait, ok := job.QueueWait(); ok {
+			waits = append(waits, wait)
+		}
+		// Only completed runs say how long processing takes
+		if duration, ok := job.Duration(); ok && job.Status == model.ProcessingJobSucceeded {
+			durations = append(durations, duration)
+		}
+		group.OutputBytes += job.OutputBytes()
+	}
+
+	group.QueueWait = summarize(waits)
+	group.Duration = summarize(durations)
+	return group
+}
+
+func summarize(durations []time.Duration) port.DurationStats {
+	if len(durations) == 0 {
+		return port.DurationStats{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	// Nearest rank
+	percentile := func(p int) time.Duration {
+		rank := (p*len(durations) + 99) / 100
+		return durations[rank-1]
+	}
+
+	return port.DurationStats{
+		Count: len(durations),
+		Mean:  total / time.Duration(len(durations)),
+		P50:   percentile(50),
+		P95:   percentile(95),
+		Max:   durations[len(durations)-1],
+	}
+}
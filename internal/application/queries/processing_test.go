@@ -0,0 +1,79 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobFinder struct {
+	port.ProcessingJobRepository
+	jobs   []*model.ProcessingJob
+	filter port.ProcessingJobFilter
+}
+
+func (f *fakeJobFinder) Find(ctx context.Context, filter port.ProcessingJobFilter) ([]*model.ProcessingJob, error) {
+	f.filter = filter
+	return f.jobs, nil
+}
+
+func TestProcessingQuery_Stats(t *testing.T) {
+	now := time.Now()
+	job := func(wsID, format string, status model.ProcessingJobStatus, wait, duration time.Duration, bytes int64) *model.ProcessingJob {
+		queuedAt := now.Add(-wait)
+		j := &model.ProcessingJob{
+			WsID:        wsID,
+			Format:      format,
+			Status:      status,
+			QueuedAt:    &queuedAt,
+			StartedAt:   now,
+			OutputSizes: map[vobj.ProcessingOutput]int64{vobj.OutputZipTiles: bytes},
+		}
+		if status != model.ProcessingJobRunning {
+			finishedAt := now.Add(duration)
+			j.FinishedAt = &finishedAt
+		}
+		return j
+	}
+
+	repo := &fakeJobFinder{jobs: []*model.ProcessingJob{
+		job("ws-1", "svs", model.ProcessingJobSucceeded, 10*time.Second, 100*time.Second, 300),
+		job("ws-1", "svs", model.ProcessingJobSucceeded, 30*time.Second, 300*time.Second, 500),
+		job("ws-1", "tiff", model.ProcessingJobFailed, 20*time.Second, 5*time.Second, 0),
+		job("ws-2", "svs", model.ProcessingJobRunning, 40*time.Second, 0, 0),
+	}}
+
+	stats, err := NewProcessingQuery(repo).Stats(context.Background(), port.ProcessingJobFilter{})
+	assert.NoError(t, err)
+
+	// Defaults to the last week
+	assert.Equal(t, DefaultStatsRange, repo.filter.To.Sub(*repo.filter.From))
+	assert.False(t, stats.Truncated)
+
+	assert.Equal(t, 4, stats.Total.Attempts)
+	assert.Equal(t, 2, stats.Total.Succeeded)
+	assert.Equal(t, 1, stats.Total.Failed)
+	assert.Equal(t, 1, stats.Total.Running)
+	assert.Equal(t, int64(800), stats.Total.OutputBytes)
+	assert.Equal(t, 25*time.Second, stats.Total.QueueWait.Mean)
+	assert.Equal(t, 40*time.Second, stats.Total.QueueWait.Max)
+	// Failed and running attempts don't count towards duration
+	assert.Equal(t, 2, stats.Total.Duration.Count)
+	assert.Equal(t, 200*time.Second, stats.Total.Duration.Mean)
+	assert.Equal(t, 300*time.Second, stats.Total.Duration.P95)
+
+	assert.Len(t, stats.ByWorkspace, 2)
+	assert.Equal(t, "ws-1", stats.ByWorkspace[0].Key)
+	assert.Equal(t, 3, stats.ByWorkspace[0].Attempts)
+
+	assert.Len(t, stats.ByFormat, 2)
+	assert.Equal(t, "svs", stats.ByFormat[0].Key)
+	assert.Equal(t, 3, stats.ByFormat[0].Attempts)
+	assert.Equal(t, "tiff", stats.ByFormat[1].Key)
+	assert.Equal(t, 1, stats.ByFormat[1].Failed)
+}
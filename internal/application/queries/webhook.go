@@ -0,0 +1,53 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/histopathai/main-service/internal/application/usecase/helper"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+)
+
+type WebhookQuery struct {
+	repo          port.WebhookRepository
+	deliveryRepo  port.WebhookDeliveryRepository
+	workspaceRepo port.WorkspaceRepository
+}
+
+func NewWebhookQuery(repo port.WebhookRepository, deliveryRepo port.WebhookDeliveryRepository, workspaceRepo port.WorkspaceRepository) *WebhookQuery {
+	return &WebhookQuery{
+		repo:          repo,
+		deliveryRepo:  deliveryRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+func (q *WebhookQuery) Get(ctx context.Context, id, requesterID string, requesterIsAdmin bool) (*model.WebhookSubscription, error) {
+	return q.readAuthorized(ctx, id, requesterID, requesterIsAdmin)
+}
+
+func (q *WebhookQuery) ListByWorkspace(ctx context.Context, wsID, requesterID string, requesterIsAdmin bool) ([]*model.WebhookSubscription, error) {
+	if _, err := helper.RequireWorkspaceMember(ctx, q.workspaceRepo, wsID, requesterID, requesterIsAdmin); err != nil {
+		return nil, err
+	}
+	return q.repo.ListByWorkspace(ctx, wsID, false)
+}
+
+func (q *WebhookQuery) ListDeliveries(ctx context.Context, subscriptionID, requesterID string, requesterIsAdmin bool, limit int) ([]*model.WebhookDelivery, error) {
+	if _, err := q.readAuthorized(ctx, subscriptionID, requesterID, requesterIsAdmin); err != nil {
+		return nil, err
+	}
+	return q.deliveryRepo.ListBySubscription(ctx, subscriptionID, limit)
+}
+
+// readAuthorized reads a subscription the requester may see through its workspace.
+func (q *WebhookQuery) readAuthorized(ctx context.Context, id, requesterID string, requesterIsAdmin bool) (*model.WebhookSubscription, error) {
+	subscription, err := q.repo.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := helper.RequireWorkspaceMember(ctx, q.workspaceRepo, subscription.WsID, requesterID, requesterIsAdmin); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
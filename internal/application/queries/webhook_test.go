@@ -0,0 +1,64 @@
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookWorkspaceRepo struct {
+	port.WorkspaceRepository
+}
+
+func (fakeWebhookWorkspaceRepo) Read(ctx context.Context, id string) (*model.Workspace, error) {
+	return &model.Workspace{
+		Entity:  vobj.Entity{ID: id, EntityType: vobj.EntityTypeWorkspace, CreatorID: "user-1"},
+		Members: []string{"user-2"},
+	}, nil
+}
+
+type fakeWebhookRepo struct {
+	port.WebhookRepository
+}
+
+func (fakeWebhookRepo) Read(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	return &model.WebhookSubscription{ID: id, WsID: "ws-1"}, nil
+}
+
+func (fakeWebhookRepo) ListByWorkspace(ctx context.Context, wsID string, activeOnly bool) ([]*model.WebhookSubscription, error) {
+	return []*model.WebhookSubscription{{ID: "wh-1", WsID: wsID}}, nil
+}
+
+type fakeWebhookDeliveryRepo struct {
+	port.WebhookDeliveryRepository
+}
+
+func (fakeWebhookDeliveryRepo) ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	return []*model.WebhookDelivery{{ID: "d-1", SubscriptionID: subscriptionID}}, nil
+}
+
+func TestWebhookQuery_ReadsNeedWorkspaceMembership(t *testing.T) {
+	ctx := context.Background()
+	q := NewWebhookQuery(fakeWebhookRepo{}, fakeWebhookDeliveryRepo{}, fakeWebhookWorkspaceRepo{})
+
+	_, err := q.ListByWorkspace(ctx, "ws-1", "user-3", false)
+	assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+	_, err = q.Get(ctx, "wh-1", "user-3", false)
+	assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+	_, err = q.ListDeliveries(ctx, "wh-1", "user-3", false, 10)
+	assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+
+	subscriptions, err := q.ListByWorkspace(ctx, "ws-1", "user-2", false)
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	_, err = q.Get(ctx, "wh-1", "admin-1", true)
+	assert.NoError(t, err)
+	deliveries, err := q.ListDeliveries(ctx, "wh-1", "user-1", false, 10)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+}
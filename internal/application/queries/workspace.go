@@ -9,10 +9,11 @@ type WorkspaceQuery struct {
 	*BaseQuery[*model.Workspace]
 }
 
-func NewWorkspaceQuery(repo port.WorkspaceRepository) *WorkspaceQuery {
+func NewWorkspaceQuery(repo port.WorkspaceRepository, uow port.UnitOfWorkFactory) *WorkspaceQuery {
 	return &WorkspaceQuery{
 		BaseQuery: &BaseQuery[*model.Workspace]{
 			repo: repo,
+			uow:  uow,
 		},
 	}
 }
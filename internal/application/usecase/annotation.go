@@ -6,6 +6,7 @@ import (
 	"github.com/histopathai/main-service/internal/application/command"
 	"github.com/histopathai/main-service/internal/application/usecase/helper"
 	"github.com/histopathai/main-service/internal/application/usecase/validator"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/port"
 	"github.com/histopathai/main-service/internal/shared/errors"
@@ -62,6 +63,10 @@ func (uc *AnnotationUseCase) Create(ctx context.Context, cmd command.CreateAnnot
 
 		createdAnnotation = created
 
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityEvent(domainevent.EntityCreated, created)); err != nil {
+			return errors.NewInternalError("failed to record annotation created event", err)
+		}
+
 		return nil
 	})
 
@@ -88,11 +93,20 @@ func (uc *AnnotationUseCase) Update(ctx context.Context, cmd command.UpdateAnnot
 			return err
 		}
 
+		current, err := uc.repo.Read(txCtx, id)
+		if err != nil {
+			return errors.NewInternalError("failed to read annotation", err)
+		}
+
 		// Update annotation
 		if err := uc.repo.Update(txCtx, id, updates); err != nil {
 			return errors.NewInternalError("failed to update annotation", err)
 		}
 
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(current, updates)); err != nil {
+			return errors.NewInternalError("failed to record annotation updated event", err)
+		}
+
 		return nil
 	})
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/histopathai/main-service/internal/application/command"
 	"github.com/histopathai/main-service/internal/application/usecase/validator"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/port"
 	"github.com/histopathai/main-service/internal/shared/errors"
@@ -44,6 +45,10 @@ func (uc *AnnotationTypeUseCase) Create(ctx context.Context, cmd command.CreateA
 			return errors.NewInternalError("failed to create annotation type", err)
 		}
 
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityEvent(domainevent.EntityCreated, createdAnnotationType)); err != nil {
+			return errors.NewInternalError("failed to record annotation type created event", err)
+		}
+
 		return nil
 	})
 
@@ -72,11 +77,20 @@ func (uc *AnnotationTypeUseCase) Update(ctx context.Context, cmd command.UpdateA
 			return err
 		}
 
+		current, err := uc.repo.Read(txCtx, cmd.ID)
+		if err != nil {
+			return errors.NewInternalError("failed to read annotation type", err)
+		}
+
 		err = uc.repo.Update(txCtx, cmd.ID, updates)
 		if err != nil {
 			return errors.NewInternalError("failed to update annotation type", err)
 		}
 
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(current, updates)); err != nil {
+			return errors.NewInternalError("failed to record annotation type updated event", err)
+		}
+
 		return nil
 	})
 
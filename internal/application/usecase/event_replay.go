@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/histopathai/main-service/internal/application/command"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// EventReplayUseCase re-runs logged events through the handlers that consume
+// them in normal operation. Replayed events bypass the handlers' stale and
+// duplicate checks, so they are only ever started by an admin.
+type EventReplayUseCase struct {
+	repo       port.EventLogRepository
+	serializer portevent.EventSerializer
+	handlers   map[domainevent.EventType]portevent.EventHandler
+}
+
+func NewEventReplayUseCase(
+	repo port.EventLogRepository,
+	serializer portevent.EventSerializer,
+	handlers map[domainevent.EventType]portevent.EventHandler,
+) *EventReplayUseCase {
+	return &EventReplayUseCase{
+		repo:       repo,
+		serializer: serializer,
+		handlers:   handlers,
+	}
+}
+
+func (uc *EventReplayUseCase) Replay(ctx context.Context, cmd command.ReplayEventsCommand) (*port.ReplayResult, error) {
+	if details, ok := cmd.Validate(); !ok {
+		return nil, errors.NewValidationError("invalid replay request", details)
+	}
+
+	limit := cmd.Limit
+	if limit == 0 {
+		limit = command.DefaultReplayLimit
+	}
+
+	entries, err := uc.repo.Find(ctx, port.EventLogFilter{
+		EventTypes: cmd.EventTypes,
+		ImageID:    cmd.ImageID,
+		From:       cmd.From,
+		To:         cmd.To,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &port.ReplayResult{DryRun: cmd.DryRun, Items: []port.ReplayItem{}}
+	replayCtx := portevent.WithReplay(ctx)
+	// An event published and consumed by this service is logged twice
+	seen := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		if seen[entry.EventID] {
+			continue
+		}
+		seen[entry.EventID] = true
+		result.Matched++
+
+		item := port.ReplayItem{
+			EventID:    entry.EventID,
+			EventType:  entry.EventType,
+			ImageID:    entry.ImageID,
+			OccurredAt: entry.OccurredAt,
+		}
+
+		handler, ok := uc.handlers[domainevent.EventType(entry.EventType)]
+		switch {
+		case !ok:
+			item.Status = port.ReplayNoHandler
+			result.Skipped++
+
+		case cmd.DryRun:
+			item.Status = port.ReplayWouldReplay
+
+		default:
+			if err := uc.replay(replayCtx, handler, entry.Payload, entry.EventType); err != nil {
+				item.Status = port.ReplayFailed
+				item.Error = err.Error()
+				result.Failed++
+			} else {
+				item.Status = port.ReplayReplayed
+				result.Replayed++
+			}
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+func (uc *EventReplayUseCase) replay(ctx context.Context, handler portevent.EventHandler, payload []byte, eventType string) error {
+	event, err := uc.serializer.Deserialize(payload, domainevent.EventType(eventType))
+	if err != nil {
+		return err
+	}
+	return handler.Handle(ctx, event)
+}
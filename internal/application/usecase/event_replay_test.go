@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/histopathai/main-service/internal/application/command"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	portevent "github.com/histopathai/main-service/internal/port/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEventLog struct {
+	entries []*model.EventLogEntry
+	filter  port.EventLogFilter
+}
+
+func (l *fakeEventLog) Append(ctx context.Context, entry *model.EventLogEntry) error {
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (l *fakeEventLog) Find(ctx context.Context, filter port.EventLogFilter) ([]*model.EventLogEntry, error) {
+	l.filter = filter
+	return l.entries, nil
+}
+
+// fakeSerializer treats the payload as the event ID.
+type fakeSerializer struct{}
+
+func (fakeSerializer) Serialize(event domainevent.Event) ([]byte, error) {
+	return []byte(event.GetEventID()), nil
+}
+
+func (fakeSerializer) Deserialize(data []byte, eventType domainevent.EventType) (domainevent.Event, error) {
+	return &domainevent.ImageProcessReqEvent{
+		BaseEvent: domainevent.BaseEvent{EventID: string(data), EventType: eventType},
+	}, nil
+}
+
+type recordingEventHandler struct {
+	handled []string
+	replay  []bool
+	failOn  string
+}
+
+func (h *recordingEventHandler) Handle(ctx context.Context, event domainevent.Event) error {
+	h.handled = append(h.handled, event.GetEventID())
+	h.replay = append(h.replay, portevent.IsReplay(ctx))
+	if event.GetEventID() == h.failOn {
+		return fmt.Errorf("boom")
+	}
+	return nil
+}
+
+func logEntry(eventID string, eventType domainevent.EventType, direction model.EventDirection) *model.EventLogEntry {
+	return &model.EventLogEntry{
+		EventID:    eventID,
+		EventType:  string(eventType),
+		Direction:  direction,
+		Payload:    []byte(eventID),
+		OccurredAt: time.Now(),
+	}
+}
+
+func TestEventReplayUseCase_Replay(t *testing.T) {
+	log := &fakeEventLog{entries: []*model.EventLogEntry{
+		logEntry("e1", domainevent.ImageProcessReqEventType, model.EventPublished),
+		logEntry("e1", domainevent.ImageProcessReqEventType, model.EventConsumed),
+		logEntry("e2", domainevent.ImageProcessReqEventType, model.EventConsumed),
+		logEntry("e3", domainevent.ImageUpdatedEventType, model.EventPublished),
+	}}
+	handler := &recordingEventHandler{failOn: "e2"}
+	uc := NewEventReplayUseCase(log, fakeSerializer{}, map[domainevent.EventType]portevent.EventHandler{
+		domainevent.ImageProcessReqEventType: handler,
+	})
+
+	t.Run("requires a filter", func(t *testing.T) {
+		_, err := uc.Replay(context.Background(), command.ReplayEventsCommand{DryRun: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("dry run does not call handlers", func(t *testing.T) {
+		result, err := uc.Replay(context.Background(), command.ReplayEventsCommand{ImageID: "img-1", DryRun: true})
+		assert.NoError(t, err)
+		assert.Equal(t, command.DefaultReplayLimit, log.filter.Limit)
+		assert.Equal(t, 3, result.Matched)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Equal(t, port.ReplayWouldReplay, result.Items[0].Status)
+		assert.Empty(t, handler.handled)
+	})
+
+	t.Run("live replay marks the context and reports failures", func(t *testing.T) {
+		result, err := uc.Replay(context.Background(), command.ReplayEventsCommand{ImageID: "img-1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"e1", "e2"}, handler.handled)
+		assert.Equal(t, []bool{true, true}, handler.replay)
+		assert.Equal(t, 1, result.Replayed)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, port.ReplayFailed, result.Items[1].Status)
+		assert.Equal(t, port.ReplayNoHandler, result.Items[2].Status)
+	})
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/histopathai/main-service/internal/domain/fields"
 	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/port"
-	"github.com/histopathai/main-service/internal/shared/errors"
 	"github.com/histopathai/main-service/internal/shared/query"
 )
 
@@ -119,48 +118,3 @@ func Contains(slice []string, item string) bool {
 	}
 	return false
 }
-
-func ValidateImageStatusTransition(currentStatus, newStatus vobj.ImageStatus) error {
-	// Define allowed transitions
-	allowedTransitions := map[vobj.ImageStatus][]vobj.ImageStatus{
-		vobj.StatusPending: {
-			vobj.StatusProcessing,
-			vobj.StatusDeleting,
-		},
-		vobj.StatusProcessing: {
-			vobj.StatusProcessed,
-			vobj.StatusFailed,
-			vobj.StatusDeleting,
-		},
-		vobj.StatusProcessed: {
-			vobj.StatusDeleting,
-			vobj.StatusProcessing, // Re-processing allowed (e.g., V1 -> V2 migration)
-		},
-		vobj.StatusFailed: {
-			vobj.StatusProcessing, // Retry
-			vobj.StatusDeleting,
-		},
-		vobj.StatusDeleting: {
-			// No transitions allowed from DELETING
-		},
-	}
-
-	allowedStates, exists := allowedTransitions[currentStatus]
-	if !exists {
-		return errors.NewValidationError("invalid current status", map[string]interface{}{
-			"current_status": currentStatus,
-		})
-	}
-
-	for _, allowed := range allowedStates {
-		if newStatus == allowed {
-			return nil
-		}
-	}
-
-	return errors.NewValidationError("invalid status transition", map[string]interface{}{
-		"current_status": currentStatus,
-		"new_status":     newStatus,
-		"allowed":        allowedStates,
-	})
-}
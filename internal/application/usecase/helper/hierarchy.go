@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/port"
 	"github.com/histopathai/main-service/internal/shared/query"
@@ -65,6 +66,17 @@ func (s *HierarchyService) getAnnotationIDsUnderImage(ctx context.Context, image
 	return fetchAllIDs(ctx, annotationRepo, builder.Build())
 }
 
+// GetAnnotationsUnderImage fetches the live annotations of an image
+func (s *HierarchyService) GetAnnotationsUnderImage(ctx context.Context, imageID string) ([]*model.Annotation, error) {
+	annotationRepo := s.uow.GetAnnotationRepo()
+
+	builder := query.NewBuilder()
+	builder.Where(fields.EntityParentID.DomainName(), query.OpEqual, imageID)
+	builder.Where(fields.EntityIsDeleted.DomainName(), query.OpEqual, false)
+
+	return fetchAll(ctx, annotationRepo, builder.Build())
+}
+
 func (s *HierarchyService) GetChildIDsByWsID(ctx context.Context, wsID string, entityType vobj.EntityType) ([]string, error) {
 	switch entityType {
 	case vobj.EntityTypePatient:
@@ -102,9 +114,27 @@ func fetchAllIDs[T port.Entity](
 	repo port.Repository[T],
 	spec query.Specification,
 ) ([]string, error) {
+	entities, err := fetchAll(ctx, repo, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var allIDs []string
+	for _, entity := range entities {
+		allIDs = append(allIDs, entity.GetID())
+	}
+
+	return allIDs, nil
+}
+
+func fetchAll[T port.Entity](
+	ctx context.Context,
+	repo port.Repository[T],
+	spec query.Specification,
+) ([]T, error) {
 	const limit = 1000
 	offset := 0
-	var allIDs []string
+	var all []T
 
 	for {
 		spec.Pagination = &query.Pagination{Limit: limit, Offset: offset}
@@ -114,9 +144,7 @@ func fetchAllIDs[T port.Entity](
 			return nil, fmt.Errorf("failed to fetch entities: %w", err)
 		}
 
-		for _, entity := range result.Data {
-			allIDs = append(allIDs, entity.GetID())
-		}
+		all = append(all, result.Data...)
 
 		if !result.HasMore {
 			break
@@ -125,5 +153,5 @@ func fetchAllIDs[T port.Entity](
 		offset += limit
 	}
 
-	return allIDs, nil
+	return all, nil
 }
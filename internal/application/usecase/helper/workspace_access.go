@@ -0,0 +1,49 @@
+package helper
+
+import (
+	"context"
+
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// RequireWorkspaceMember reads the workspace and fails unless the requester
+// is an admin or one of its members. Deleted workspaces are not found.
+func RequireWorkspaceMember(ctx context.Context, repo port.WorkspaceRepository, wsID, userID string, admin bool) (*model.Workspace, error) {
+	workspace, err := readLiveWorkspace(ctx, repo, wsID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && !workspace.HasMember(userID) {
+		return nil, errors.NewForbiddenError("no access to the workspace")
+	}
+	return workspace, nil
+}
+
+// RequireWorkspaceManager reads the workspace and fails unless the requester
+// may manage its access: its creator or an admin.
+func RequireWorkspaceManager(ctx context.Context, repo port.WorkspaceRepository, wsID, userID string, admin bool) (*model.Workspace, error) {
+	workspace, err := readLiveWorkspace(ctx, repo, wsID)
+	if err != nil {
+		return nil, err
+	}
+	if !workspace.CanManageAccess(userID, admin) {
+		return nil, errors.NewForbiddenError("only the workspace creator or an admin can do this")
+	}
+	return workspace, nil
+}
+
+func readLiveWorkspace(ctx context.Context, repo port.WorkspaceRepository, wsID string) (*model.Workspace, error) {
+	workspace, err := repo.Read(ctx, wsID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFoundError("workspace not found")
+		}
+		return nil, errors.NewInternalError("failed to read workspace", err)
+	}
+	if workspace == nil || workspace.IsDeleted() {
+		return nil, errors.NewNotFoundError("workspace not found")
+	}
+	return workspace, nil
+}
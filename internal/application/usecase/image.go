@@ -7,7 +7,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/histopathai/main-service/internal/application/command"
+	"github.com/histopathai/main-service/internal/application/usecase/helper"
 	"github.com/histopathai/main-service/internal/application/usecase/validator"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/port"
@@ -42,12 +45,7 @@ func (uc *ImageUseCase) Upload(ctx context.Context, cmd command.UploadImageComma
 
 	image.SetID(uuid.New().String())
 
-	image.Processing = &vobj.ProcessingInfo{
-		Status:          vobj.StatusPending,
-		Version:         vobj.ProcessingV2,
-		RetryCount:      0,
-		LastProcessedAt: time.Now(),
-	}
+	image.Processing = vobj.NewProcessingInfo(vobj.ProcessingV2)
 
 	var createdImage *model.Image
 	uowerr := uc.uow.WithTx(ctx, func(txCtx context.Context) error {
@@ -64,6 +62,10 @@ func (uc *ImageUseCase) Upload(ctx context.Context, cmd command.UploadImageComma
 		}
 		createdImage = createdEntity
 
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityEvent(domainevent.EntityCreated, createdEntity)); err != nil {
+			return errors.NewInternalError("failed to record image created event", err)
+		}
+
 		return nil
 	})
 
@@ -94,11 +96,22 @@ func (uc *ImageUseCase) Update(ctx context.Context, cmd command.UpdateImageComma
 
 	id := cmd.GetID()
 
-	if err := uc.repo.Update(ctx, id, updates); err != nil {
-		return errors.NewInternalError("failed to update image", err)
-	}
+	return uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		current, err := uc.repo.Read(txCtx, id)
+		if err != nil {
+			return errors.NewInternalError("failed to read image", err)
+		}
 
-	return nil
+		if err := uc.repo.Update(txCtx, id, updates); err != nil {
+			return errors.NewInternalError("failed to update image", err)
+		}
+
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(current, updates)); err != nil {
+			return errors.NewInternalError("failed to record image updated event", err)
+		}
+
+		return nil
+	})
 }
 
 func (uc *ImageUseCase) Transfer(ctx context.Context, cmd command.TransferCommand) error {
@@ -109,10 +122,50 @@ func (uc *ImageUseCase) Transfer(ctx context.Context, cmd command.TransferComman
 
 		id := cmd.GetID()
 
+		image, err := uc.repo.Read(txCtx, id)
+		if err != nil {
+			return errors.NewInternalError("failed to read image", err)
+		}
+
+		// Images move between patients; the workspace comes from the new patient
+		patient, err := uc.uow.GetPatientRepo().Read(txCtx, cmd.GetNewParent())
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewNotFoundError("patient not found")
+			}
+			return errors.NewInternalError("failed to read patient", err)
+		}
+		wsID := patient.Parent.ID
+
+		// Annotations carry the workspace too and follow the image into the new one
+		var annotations []*model.Annotation
+		if wsID != image.WsID {
+			annotations, err = helper.NewHierarchyService(uc.uow).GetAnnotationsUnderImage(txCtx, id)
+			if err != nil {
+				return errors.NewInternalError("failed to get child annotations", err)
+			}
+			if len(annotations) > maxWorkspaceMoveAnnotations {
+				return errors.NewConflictError("image has too many annotations to move to another workspace", map[string]interface{}{
+					"annotations": len(annotations),
+					"max":         maxWorkspaceMoveAnnotations,
+				})
+			}
+		}
+
 		if err := uc.repo.Transfer(txCtx, id, cmd.GetNewParent()); err != nil {
 			return errors.NewInternalError("failed to transfer image", err)
 		}
 
+		if wsID != image.WsID {
+			if err := uc.moveToWorkspace(txCtx, id, annotations, wsID); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityTransferredEvent(image, image.Parent, cmd.GetNewParent(), wsID)); err != nil {
+			return errors.NewInternalError("failed to record image transferred event", err)
+		}
+
 		return nil
 	})
 
@@ -139,6 +192,41 @@ func (uc *ImageUseCase) TransferMany(ctx context.Context, cmd command.TransferMa
 
 // Helper functions
 
+// Annotations an image may take along when it moves workspace. Each takes an
+// update and an outbox write, which keeps the transfer under Firestore's 500
+// write limit.
+const maxWorkspaceMoveAnnotations = 200
+
+// moveToWorkspace rewrites ws_id on a transferred image and its annotations
+// and records an updated event for each annotation in its new workspace.
+func (uc *ImageUseCase) moveToWorkspace(txCtx context.Context, imageID string, annotations []*model.Annotation, wsID string) error {
+	if err := uc.repo.Update(txCtx, imageID, map[string]any{fields.ImageWsID.DomainName(): wsID}); err != nil {
+		return errors.NewInternalError("failed to update image", err)
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	updates := map[string]any{
+		fields.AnnotationWsID.DomainName(): wsID,
+	}
+	annotationIDs := make([]string, len(annotations))
+	for i, annotation := range annotations {
+		annotationIDs[i] = annotation.ID
+	}
+	if err := uc.uow.GetAnnotationRepo().UpdateMany(txCtx, annotationIDs, updates); err != nil {
+		return errors.NewInternalError("failed to update annotations", err)
+	}
+
+	for _, annotation := range annotations {
+		annotation.WsID = wsID
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(annotation, updates)); err != nil {
+			return errors.NewInternalError("failed to record annotation updated event", err)
+		}
+	}
+	return nil
+}
+
 func (uc *ImageUseCase) generatePresignedURLS(ctx context.Context, cmd command.UploadImageCommand, imageID string) ([]port.PresignedURLPayload, error) {
 	var presignedURLs []port.PresignedURLPayload
 
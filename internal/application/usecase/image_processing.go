@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/histopathai/main-service/internal/application/command"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/port/cache"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+// Enough to cover every attempt an image can have running at once
+const runningJobsLookback = 20
+
+// ImageProcessingUseCase restarts or stops processing on user request. Both
+// rotate the image's ActiveEventID, so results still arriving from the
+// previous attempt are ignored, detach its derived contents, drop the tile
+// server's cached copies of them and cancel its running executions.
+type ImageProcessingUseCase struct {
+	uow       port.UnitOfWorkFactory
+	jobRepo   port.ProcessingJobRepository
+	worker    port.ImageProcessingWorker
+	backend   string
+	pipelines *vobj.PipelineRegistry
+	// Tile server caches still holding the detached contents; may be nil
+	tileCache cache.ImageInvalidator
+}
+
+func NewImageProcessingUseCase(
+	uow port.UnitOfWorkFactory,
+	jobRepo port.ProcessingJobRepository,
+	worker port.ImageProcessingWorker,
+	backend string,
+	pipelines *vobj.PipelineRegistry,
+	tileCache cache.ImageInvalidator,
+) *ImageProcessingUseCase {
+	return &ImageProcessingUseCase{
+		uow:       uow,
+		jobRepo:   jobRepo,
+		worker:    worker,
+		backend:   backend,
+		pipelines: pipelines,
+		tileCache: tileCache,
+	}
+}
+
+func (uc *ImageProcessingUseCase) Reprocess(ctx context.Context, cmd command.ReprocessImageCommand) (*port.ProcessingActionResult, error) {
+	result := &port.ProcessingActionResult{
+		ImageID:       cmd.ID,
+		Status:        vobj.StatusQueued,
+		ActiveEventID: uuid.New().String(),
+	}
+	err := uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		image, err := uc.readImage(txCtx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if image.OriginContentID == nil {
+			return errors.NewConflictError("image has no uploaded origin to process", nil)
+		}
+
+		origin, err := uc.uow.GetContentRepo().Read(txCtx, *image.OriginContentID)
+		if err != nil {
+			return err
+		}
+
+		info := vobj.NewProcessingInfo(vobj.ProcessingV2)
+		if image.Processing != nil {
+			*info = *image.Processing
+		}
+		if err := info.MarkAsQueued(); err != nil {
+			return err
+		}
+
+		version := vobj.ProcessingVersion(cmd.ProcessingVersion)
+		if version == "" {
+			version = info.Version
+		}
+		if !version.IsValid() {
+			version = vobj.ProcessingV2
+		}
+
+		// Re-resolved in case the pipelines changed since the upload
+		profile := uc.pipelines.Resolve(origin.ContentType, origin.Size)
+
+		updates := map[string]interface{}{
+			fields.ImageProcessingStatus.DomainName():          info.Status,
+			fields.ImageProcessingVersion.DomainName():         version,
+			fields.ImageProcessingProfile.DomainName():         profile,
+			fields.ImageProcessingRetryCount.DomainName():      0,
+			fields.ImageProcessingFailureReason.DomainName():   "",
+			fields.ImageProcessingLastProcessedAt.DomainName(): info.LastProcessedAt,
+		}
+		if err := uc.restart(txCtx, image, updates, result); err != nil {
+			return err
+		}
+
+		request := &domainevent.ImageProcessReqEvent{
+			BaseEvent: domainevent.BaseEvent{
+				EventID:   result.ActiveEventID,
+				EventType: domainevent.ImageProcessReqEventType,
+				Timestamp: time.Now(),
+			},
+			Content:           *origin,
+			ProcessingVersion: version,
+			ProcessingProfile: profile,
+			Priority:          vobj.ProcessingPriority(cmd.Priority),
+		}
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, request); err != nil {
+			return errors.NewInternalError("failed to record image process request", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.invalidateTiles(ctx, cmd.ID)
+	result.Executions = uc.cancelRunning(ctx, cmd.ID, result.ActiveEventID)
+
+	return result, nil
+}
+
+func (uc *ImageProcessingUseCase) CancelProcessing(ctx context.Context, cmd command.CancelProcessingCommand) (*port.ProcessingActionResult, error) {
+	result := &port.ProcessingActionResult{
+		ImageID:       cmd.ID,
+		Status:        vobj.StatusCancelled,
+		ActiveEventID: uuid.New().String(),
+	}
+
+	err := uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		image, err := uc.readImage(txCtx, cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		// Failed images can be cancelled too: the watchdog would retry them
+		info := vobj.NewProcessingInfo(vobj.ProcessingV2)
+		if image.Processing != nil {
+			*info = *image.Processing
+		}
+		if err := info.MarkAsCancelled("processing cancelled by user"); err != nil {
+			return err
+		}
+
+		// The scheduler would drop the stale entry too, but until then it
+		// counts towards the queue positions of other images
+		if err := uc.uow.GetProcessingQueueRepo().Delete(txCtx, image.ID); err != nil && !errors.IsNotFound(err) {
+			return errors.NewInternalError("failed to remove queue entry", err)
+		}
+
+		updates := map[string]interface{}{
+			fields.ImageProcessingStatus.DomainName():          info.Status,
+			fields.ImageProcessingFailureReason.DomainName():   *info.FailureReason,
+			fields.ImageProcessingLastProcessedAt.DomainName(): info.LastProcessedAt,
+		}
+		return uc.restart(txCtx, image, updates, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.invalidateTiles(ctx, cmd.ID)
+	result.Executions = uc.cancelRunning(ctx, cmd.ID, result.ActiveEventID)
+	return result, nil
+}
+
+func (uc *ImageProcessingUseCase) readImage(ctx context.Context, id string) (*model.Image, error) {
+	image, err := uc.uow.GetImageRepo().Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if image.IsDeleted() || processingStatus(image) == vobj.StatusDeleting {
+		return nil, errors.NewNotFoundError("image not found")
+	}
+	return image, nil
+}
+
+// restart applies updates together with a new ActiveEventID, detaches the
+// derived contents and records the change in the outbox. It must run after
+// all reads of the transaction.
+func (uc *ImageProcessingUseCase) restart(ctx context.Context, image *model.Image, updates map[string]interface{}, result *port.ProcessingActionResult) error {
+	updates[fields.ImageProcessingActiveEventID.DomainName()] = result.ActiveEventID
+
+	contentRepo := uc.uow.GetContentRepo()
+	for field, id := range derivedContentIDs(image) {
+		if err := contentRepo.SoftDelete(ctx, id); err != nil {
+			return errors.NewInternalError("failed to remove derived content", err)
+		}
+		updates[field.DomainName()] = nil
+		result.RemovedContentIDs = append(result.RemovedContentIDs, id)
+	}
+	sort.Strings(result.RemovedContentIDs)
+
+	if err := uc.uow.GetImageRepo().Update(ctx, image.ID, updates); err != nil {
+		return errors.NewInternalError("failed to update image", err)
+	}
+
+	if err := uc.uow.GetOutboxRepo().Add(ctx, domainevent.NewEntityUpdatedEvent(image, updates)); err != nil {
+		return errors.NewInternalError("failed to record image updated event", err)
+	}
+	return nil
+}
+
+// invalidateTiles drops the tile server's cached descriptor, tiles and index
+// map of the image, which point at the contents restart detached.
+func (uc *ImageProcessingUseCase) invalidateTiles(ctx context.Context, imageID string) {
+	if uc.tileCache != nil {
+		_ = uc.tileCache.InvalidateImage(ctx, imageID)
+	}
+}
+
+// cancelRunning stops the image's running executions, other than the one
+// started for activeEventID, where the backend allows it and closes their job
+// records. It is best effort: the rotated ActiveEventID already marks
+// whatever they still report as stale.
+func (uc *ImageProcessingUseCase) cancelRunning(ctx context.Context, imageID string, activeEventID string) []port.ExecutionCancellation {
+	jobs, err := uc.jobRepo.ListByImage(ctx, imageID, runningJobsLookback)
+	if err != nil {
+		return []port.ExecutionCancellation{{Error: "failed to list executions: " + err.Error()}}
+	}
+
+	canceller, canCancel := uc.worker.(port.ExecutionCanceller)
+
+	cancellations := []port.ExecutionCancellation{}
+	for _, job := range jobs {
+		// The relay may already have started the new attempt
+		if job.Status != model.ProcessingJobRunning || job.EventID == activeEventID {
+			continue
+		}
+
+		cancellation := port.ExecutionCancellation{Backend: job.Backend, Name: job.ExecutionName}
+		switch {
+		case job.Backend != uc.backend:
+			cancellation.Error = "execution belongs to the inactive " + job.Backend + " backend"
+		case !canCancel:
+			cancellation.Error = "backend does not support cancellation"
+		default:
+			if err := canceller.CancelExecution(ctx, job.ExecutionName); err != nil {
+				cancellation.Error = err.Error()
+			} else {
+				cancellation.Cancelled = true
+			}
+		}
+
+		job.Finish(model.ProcessingJobCancelled, "superseded by a user request")
+		if err := uc.jobRepo.Update(ctx, job); err != nil && cancellation.Error == "" {
+			cancellation.Error = "failed to update job record: " + err.Error()
+		}
+		cancellations = append(cancellations, cancellation)
+	}
+	return cancellations
+}
+
+func derivedContentIDs(image *model.Image) map[fields.ImageField]string {
+	ids := map[fields.ImageField]string{}
+	refs := map[fields.ImageField]*string{
+		fields.ImageThumbnailContentID: image.ThumbnailContentID,
+		fields.ImageDziContentID:       image.DziContentID,
+		fields.ImageIndexmapContentID:  image.IndexmapContentID,
+		fields.ImageTilesContentID:     image.TilesContentID,
+		fields.ImageZipTilesContentID:  image.ZipTilesContentID,
+		fields.ImageLabelContentID:     image.LabelContentID,
+		fields.ImageMacroContentID:     image.MacroContentID,
+	}
+	for field, id := range refs {
+		if id != nil && *id != "" {
+			ids[field] = *id
+		}
+	}
+	return ids
+}
+
+func processingStatus(image *model.Image) vobj.ImageStatus {
+	if image.Processing == nil {
+		return ""
+	}
+	return image.Processing.Status
+}
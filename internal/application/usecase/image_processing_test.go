@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/application/command"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProcessingUOW struct {
+	port.UnitOfWorkFactory
+	image    *model.Image
+	updates  map[string]interface{}
+	removed  []string
+	outbox   []domainevent.Event
+	dequeued []string
+}
+
+func (u *fakeProcessingUOW) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (u *fakeProcessingUOW) GetImageRepo() port.ImageRepository {
+	return &fakeProcessingImageRepo{uow: u}
+}
+
+func (u *fakeProcessingUOW) GetContentRepo() port.ContentRepository {
+	return &fakeProcessingContentRepo{uow: u}
+}
+
+func (u *fakeProcessingUOW) GetOutboxRepo() port.OutboxRepository {
+	return &fakeProcessingOutboxRepo{uow: u}
+}
+
+func (u *fakeProcessingUOW) GetProcessingQueueRepo() port.ProcessingQueueRepository {
+	return &fakeProcessingQueueRepo{uow: u}
+}
+
+type fakeProcessingImageRepo struct {
+	port.ImageRepository
+	uow *fakeProcessingUOW
+}
+
+func (r *fakeProcessingImageRepo) Read(ctx context.Context, id string) (*model.Image, error) {
+	if r.uow.image.ID != id {
+		return nil, errors.NewNotFoundError("document not found")
+	}
+	return r.uow.image, nil
+}
+
+func (r *fakeProcessingImageRepo) Update(ctx context.Context, id string, updates map[string]interface{}) error {
+	r.uow.updates = updates
+	return nil
+}
+
+type fakeProcessingContentRepo struct {
+	port.ContentRepository
+	uow *fakeProcessingUOW
+}
+
+func (r *fakeProcessingContentRepo) Read(ctx context.Context, id string) (*model.Content, error) {
+	return &model.Content{Entity: vobj.Entity{ID: id}, Path: "slide.svs"}, nil
+}
+
+func (r *fakeProcessingContentRepo) SoftDelete(ctx context.Context, id string) error {
+	r.uow.removed = append(r.uow.removed, id)
+	return nil
+}
+
+type fakeProcessingOutboxRepo struct {
+	port.OutboxRepository
+	uow *fakeProcessingUOW
+}
+
+func (r *fakeProcessingOutboxRepo) Add(ctx context.Context, event domainevent.Event) error {
+	r.uow.outbox = append(r.uow.outbox, event)
+	return nil
+}
+
+type fakeProcessingQueueRepo struct {
+	port.ProcessingQueueRepository
+	uow *fakeProcessingUOW
+}
+
+func (r *fakeProcessingQueueRepo) Delete(ctx context.Context, imageID string) error {
+	r.uow.dequeued = append(r.uow.dequeued, imageID)
+	return nil
+}
+
+type fakeJobRepo struct {
+	port.ProcessingJobRepository
+	jobs    []*model.ProcessingJob
+	updated []*model.ProcessingJob
+}
+
+func (r *fakeJobRepo) ListByImage(ctx context.Context, imageID string, limit int) ([]*model.ProcessingJob, error) {
+	return r.jobs, nil
+}
+
+func (r *fakeJobRepo) Update(ctx context.Context, job *model.ProcessingJob) error {
+	r.updated = append(r.updated, job)
+	return nil
+}
+
+type fakeCancellingWorker struct {
+	port.ImageProcessingWorker
+	cancelled []string
+}
+
+func (w *fakeCancellingWorker) CancelExecution(ctx context.Context, executionName string) error {
+	w.cancelled = append(w.cancelled, executionName)
+	return nil
+}
+
+type fakeTileCache struct {
+	invalidated []string
+}
+
+func (c *fakeTileCache) InvalidateImage(ctx context.Context, imageID string) error {
+	c.invalidated = append(c.invalidated, imageID)
+	return nil
+}
+
+func processedImage(status vobj.ImageStatus) *model.Image {
+	origin, thumbnail, dzi := "origin-1", "thumb-1", "dzi-1"
+	return &model.Image{
+		Entity:             vobj.Entity{ID: "img-1"},
+		OriginContentID:    &origin,
+		ThumbnailContentID: &thumbnail,
+		DziContentID:       &dzi,
+		Processing: &vobj.ProcessingInfo{
+			Status:        status,
+			Version:       vobj.ProcessingV1,
+			RetryCount:    2,
+			ActiveEventID: "evt-old",
+		},
+	}
+}
+
+func TestImageProcessingUseCase_Reprocess(t *testing.T) {
+	uow := &fakeProcessingUOW{image: processedImage(vobj.StatusProcessing)}
+	jobs := &fakeJobRepo{jobs: []*model.ProcessingJob{
+		{ID: "job-1", Backend: "cloudrun", ExecutionName: "exec-1", Status: model.ProcessingJobRunning},
+		{ID: "job-0", Backend: "cloudrun", ExecutionName: "exec-0", Status: model.ProcessingJobFailed},
+		{ID: "job-2", Backend: "local", ExecutionName: "exec-2", Status: model.ProcessingJobRunning},
+	}}
+	worker := &fakeCancellingWorker{}
+	tileCache := &fakeTileCache{}
+
+	uc := NewImageProcessingUseCase(uow, jobs, worker, "cloudrun", vobj.DefaultPipelineRegistry(), tileCache)
+	result, err := uc.Reprocess(context.Background(), command.ReprocessImageCommand{ID: "img-1", ProcessingVersion: "v2", Priority: "high"})
+	require.NoError(t, err)
+
+	assert.Equal(t, vobj.StatusQueued, result.Status)
+	assert.Equal(t, vobj.StatusQueued, uow.updates[fields.ImageProcessingStatus.DomainName()])
+
+	assert.NotEqual(t, "evt-old", result.ActiveEventID)
+	assert.Equal(t, result.ActiveEventID, uow.updates[fields.ImageProcessingActiveEventID.DomainName()])
+	assert.Equal(t, vobj.ProcessingV2, uow.updates[fields.ImageProcessingVersion.DomainName()])
+	assert.Equal(t, 0, uow.updates[fields.ImageProcessingRetryCount.DomainName()])
+	assert.Nil(t, uow.updates[fields.ImageThumbnailContentID.DomainName()])
+	assert.Contains(t, uow.updates, fields.ImageDziContentID.DomainName())
+	assert.Equal(t, []string{"dzi-1", "thumb-1"}, result.RemovedContentIDs)
+	assert.ElementsMatch(t, []string{"dzi-1", "thumb-1"}, uow.removed)
+	// The status update and the new request, recorded together
+	require.Len(t, uow.outbox, 2)
+	// The tile server must not keep serving the detached pyramid
+	assert.Equal(t, []string{"img-1"}, tileCache.invalidated)
+
+	// Only running jobs of the active backend can be cancelled
+	assert.Equal(t, []string{"exec-1"}, worker.cancelled)
+	require.Len(t, result.Executions, 2)
+	assert.True(t, result.Executions[0].Cancelled)
+	assert.False(t, result.Executions[1].Cancelled)
+	assert.NotEmpty(t, result.Executions[1].Error)
+	require.Len(t, jobs.updated, 2)
+	for _, job := range jobs.updated {
+		assert.Equal(t, model.ProcessingJobCancelled, job.Status)
+	}
+
+	request := uow.outbox[1].(*domainevent.ImageProcessReqEvent)
+	assert.Equal(t, result.ActiveEventID, request.EventID)
+	assert.Equal(t, vobj.ProcessingV2, request.ProcessingVersion)
+	assert.Equal(t, "origin-1", request.Content.ID)
+	assert.Equal(t, vobj.PriorityHigh, request.Priority)
+}
+
+func TestImageProcessingUseCase_CancelProcessing(t *testing.T) {
+	uow := &fakeProcessingUOW{image: processedImage(vobj.StatusProcessed)}
+	tileCache := &fakeTileCache{}
+	uc := NewImageProcessingUseCase(uow, &fakeJobRepo{}, &fakeCancellingWorker{}, "cloudrun", vobj.DefaultPipelineRegistry(), tileCache)
+
+	_, err := uc.CancelProcessing(context.Background(), command.CancelProcessingCommand{ID: "img-1"})
+	assert.True(t, errors.IsType(err, errors.ErrorTypeConflict))
+	assert.Nil(t, uow.updates)
+	assert.Empty(t, tileCache.invalidated)
+
+	uow.image = processedImage(vobj.StatusFailed)
+	result, err := uc.CancelProcessing(context.Background(), command.CancelProcessingCommand{ID: "img-1"})
+	require.NoError(t, err)
+	assert.Equal(t, vobj.StatusCancelled, uow.updates[fields.ImageProcessingStatus.DomainName()])
+	assert.Equal(t, result.ActiveEventID, uow.updates[fields.ImageProcessingActiveEventID.DomainName()])
+	assert.Empty(t, result.Executions)
+	assert.Equal(t, []string{"img-1"}, uow.dequeued)
+	assert.Equal(t, []string{"img-1"}, tileCache.invalidated)
+}
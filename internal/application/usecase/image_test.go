@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/application/command"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/fields"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/histopathai/main-service/internal/shared/query"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTransferUOW struct {
+	port.UnitOfWorkFactory
+	image             *model.Image
+	annotations       []*model.Annotation
+	imageUpdates      map[string]interface{}
+	annotationUpdates map[string][]string
+	outbox            []domainevent.Event
+}
+
+func (u *fakeTransferUOW) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (u *fakeTransferUOW) GetImageRepo() port.ImageRepository { return &fakeTransferImageRepo{uow: u} }
+
+func (u *fakeTransferUOW) GetPatientRepo() port.PatientRepository { return fakeTransferPatientRepo{} }
+
+func (u *fakeTransferUOW) GetAnnotationRepo() port.AnnotationRepository {
+	return &fakeTransferAnnotationRepo{uow: u}
+}
+
+func (u *fakeTransferUOW) GetOutboxRepo() port.OutboxRepository {
+	return &fakeTransferOutboxRepo{uow: u}
+}
+
+type fakeTransferImageRepo struct {
+	port.ImageRepository
+	uow *fakeTransferUOW
+}
+
+func (r *fakeTransferImageRepo) Read(ctx context.Context, id string) (*model.Image, error) {
+	return r.uow.image, nil
+}
+
+func (r *fakeTransferImageRepo) Update(ctx context.Context, id string, updates map[string]interface{}) error {
+	r.uow.imageUpdates = updates
+	return nil
+}
+
+func (r *fakeTransferImageRepo) Transfer(ctx context.Context, id string, newOwnerID string) error {
+	return nil
+}
+
+// Patients "p-<ws>" belong to workspace "<ws>"
+type fakeTransferPatientRepo struct{ port.PatientRepository }
+
+func (fakeTransferPatientRepo) Read(ctx context.Context, id string) (*model.Patient, error) {
+	return &model.Patient{Entity: vobj.Entity{
+		ID:     id,
+		Parent: vobj.ParentRef{ID: id[len("p-"):], Type: vobj.ParentTypeWorkspace},
+	}}, nil
+}
+
+type fakeTransferAnnotationRepo struct {
+	port.AnnotationRepository
+	uow *fakeTransferUOW
+}
+
+func (r *fakeTransferAnnotationRepo) Find(ctx context.Context, spec query.Specification) (*query.Result[*model.Annotation], error) {
+	return &query.Result[*model.Annotation]{Data: r.uow.annotations}, nil
+}
+
+func (r *fakeTransferAnnotationRepo) UpdateMany(ctx context.Context, ids []string, updates map[string]interface{}) error {
+	for _, id := range ids {
+		r.uow.annotationUpdates[id] = append(r.uow.annotationUpdates[id], updates[fields.AnnotationWsID.DomainName()].(string))
+	}
+	return nil
+}
+
+type fakeTransferOutboxRepo struct {
+	port.OutboxRepository
+	uow *fakeTransferUOW
+}
+
+func (r *fakeTransferOutboxRepo) Add(ctx context.Context, event domainevent.Event) error {
+	r.uow.outbox = append(r.uow.outbox, event)
+	return nil
+}
+
+func newTransferUOW(annotations int) *fakeTransferUOW {
+	uow := &fakeTransferUOW{
+		image: &model.Image{
+			Entity: vobj.Entity{ID: "img-1", EntityType: vobj.EntityTypeImage, Parent: vobj.ParentRef{ID: "p-ws-1", Type: vobj.ParentTypePatient}},
+			WsID:   "ws-1",
+		},
+		annotationUpdates: map[string][]string{},
+	}
+	for i := range annotations {
+		uow.annotations = append(uow.annotations, &model.Annotation{
+			Entity: vobj.Entity{ID: fmt.Sprintf("ann-%d", i), EntityType: vobj.EntityTypeAnnotation, Parent: vobj.ParentRef{ID: "img-1", Type: vobj.ParentTypeImage}},
+			WsID:   "ws-1",
+		})
+	}
+	return uow
+}
+
+func TestImageUseCase_TransferMovesAnnotationsToNewWorkspace(t *testing.T) {
+	uow := newTransferUOW(2)
+	uc := NewImageUseCase(uow.GetImageRepo(), uow, nil, nil)
+
+	err := uc.Transfer(context.Background(), command.TransferCommand{ID: "img-1", NewParent: "p-ws-2", ParentType: vobj.EntityTypeImage.String()})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ws-2", uow.imageUpdates[fields.ImageWsID.DomainName()])
+	assert.Equal(t, map[string][]string{"ann-0": {"ws-2"}, "ann-1": {"ws-2"}}, uow.annotationUpdates)
+
+	// The image transfer and an update per annotation, all in the new workspace
+	if assert.Len(t, uow.outbox, 3) {
+		for _, e := range uow.outbox {
+			entityEvent := e.(*domainevent.EntityEvent)
+			assert.Equal(t, "ws-2", entityEvent.WsID)
+			if entityEvent.EntityType == vobj.EntityTypeAnnotation {
+				assert.Equal(t, domainevent.EntityUpdated, entityEvent.Action)
+				assert.Equal(t, []string{fields.AnnotationWsID.DomainName()}, entityEvent.ChangedFields)
+			}
+		}
+	}
+}
+
+func TestImageUseCase_TransferWithinWorkspaceLeavesAnnotations(t *testing.T) {
+	uow := newTransferUOW(2)
+	uc := NewImageUseCase(uow.GetImageRepo(), uow, nil, nil)
+
+	err := uc.Transfer(context.Background(), command.TransferCommand{ID: "img-1", NewParent: "p-ws-1", ParentType: vobj.EntityTypeImage.String()})
+	assert.NoError(t, err)
+
+	assert.Nil(t, uow.imageUpdates)
+	assert.Empty(t, uow.annotationUpdates)
+	assert.Len(t, uow.outbox, 1)
+}
+
+func TestImageUseCase_TransferRejectsTooManyAnnotations(t *testing.T) {
+	uow := newTransferUOW(maxWorkspaceMoveAnnotations + 1)
+	uc := NewImageUseCase(uow.GetImageRepo(), uow, nil, nil)
+
+	err := uc.Transfer(context.Background(), command.TransferCommand{ID: "img-1", NewParent: "p-ws-2", ParentType: vobj.EntityTypeImage.String()})
+	assert.True(t, errors.IsType(err, errors.ErrorTypeConflict), err)
+	assert.Nil(t, uow.imageUpdates)
+	assert.Empty(t, uow.outbox)
+}
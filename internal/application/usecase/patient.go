@@ -6,6 +6,7 @@ import (
 	"github.com/histopathai/main-service/internal/application/command"
 	"github.com/histopathai/main-service/internal/application/usecase/helper"
 	"github.com/histopathai/main-service/internal/application/usecase/validator"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/fields"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
@@ -45,6 +46,10 @@ func (uc *PatientUseCase) Create(ctx context.Context, cmd command.CreatePatientC
 			return errors.NewInternalError("failed to create patient", err)
 		}
 
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityEvent(domainevent.EntityCreated, createdPatient)); err != nil {
+			return errors.NewInternalError("failed to record patient created event", err)
+		}
+
 		return nil
 	})
 
@@ -63,15 +68,26 @@ func (uc *PatientUseCase) Update(ctx context.Context, cmd command.UpdatePatientC
 
 	id := cmd.GetID()
 
-	if err := uc.validator.ValidateUpdate(ctx, id, updates); err != nil {
-		return err
-	}
+	return uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		if err := uc.validator.ValidateUpdate(txCtx, id, updates); err != nil {
+			return err
+		}
 
-	if err := uc.repo.Update(ctx, id, updates); err != nil {
-		return errors.NewInternalError("failed to update patient", err)
-	}
+		current, err := uc.repo.Read(txCtx, id)
+		if err != nil {
+			return errors.NewInternalError("failed to read patient", err)
+		}
 
-	return nil
+		if err := uc.repo.Update(txCtx, id, updates); err != nil {
+			return errors.NewInternalError("failed to update patient", err)
+		}
+
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(current, updates)); err != nil {
+			return errors.NewInternalError("failed to record patient updated event", err)
+		}
+
+		return nil
+	})
 }
 
 func (uc *PatientUseCase) Transfer(ctx context.Context, cmd command.TransferCommand) error {
@@ -81,6 +97,11 @@ func (uc *PatientUseCase) Transfer(ctx context.Context, cmd command.TransferComm
 			return err
 		}
 
+		patient, err := uc.repo.Read(txCtx, cmd.GetID())
+		if err != nil {
+			return errors.NewInternalError("failed to read patient", err)
+		}
+
 		hiearachyService := helper.NewHierarchyService(uc.uow)
 
 		childImageIDs, err := hiearachyService.GetChildIDs(txCtx, vobj.EntityTypePatient, cmd.GetID())
@@ -125,6 +146,10 @@ func (uc *PatientUseCase) Transfer(ctx context.Context, cmd command.TransferComm
 			return errors.NewInternalError("failed to transfer patient", err)
 		}
 
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityTransferredEvent(patient, patient.Parent, cmd.GetNewParent(), cmd.GetNewParent())); err != nil {
+			return errors.NewInternalError("failed to record patient transferred event", err)
+		}
+
 		return nil
 
 	})
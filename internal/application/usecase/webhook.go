@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/histopathai/main-service/internal/application/command"
+	"github.com/histopathai/main-service/internal/application/usecase/helper"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
+type WebhookUseCase struct {
+	repo         port.WebhookRepository
+	deliveryRepo port.WebhookDeliveryRepository
+	uow          port.UnitOfWorkFactory
+}
+
+func NewWebhookUseCase(uow port.UnitOfWorkFactory) *WebhookUseCase {
+	return &WebhookUseCase{
+		repo:         uow.GetWebhookRepo(),
+		deliveryRepo: uow.GetWebhookDeliveryRepo(),
+		uow:          uow,
+	}
+}
+
+func (uc *WebhookUseCase) Create(ctx context.Context, cmd command.CreateWebhookCommand) (*model.WebhookSubscription, error) {
+	if details, ok := cmd.Validate(); !ok {
+		return nil, errors.NewValidationError("invalid webhook payload", details)
+	}
+
+	secret := cmd.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, errors.NewInternalError("failed to generate webhook secret", err)
+		}
+		secret = generated
+	}
+
+	subscription := &model.WebhookSubscription{
+		WsID:       cmd.WsID,
+		CreatorID:  cmd.CreatorID,
+		URL:        cmd.URL,
+		Secret:     secret,
+		Active:     true,
+		EventTypes: cmd.EventTypes,
+	}
+
+	var created *model.WebhookSubscription
+	uowerr := uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		if _, err := helper.RequireWorkspaceManager(txCtx, uc.uow.GetWorkspaceRepo(), cmd.WsID, cmd.CreatorID, cmd.RequesterIsAdmin); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = uc.repo.Create(txCtx, subscription)
+		if err != nil {
+			return errors.NewInternalError("failed to create webhook", err)
+		}
+		return nil
+	})
+	if uowerr != nil {
+		return nil, uowerr
+	}
+
+	return created, nil
+}
+
+func (uc *WebhookUseCase) Update(ctx context.Context, cmd command.UpdateWebhookCommand) error {
+	if details, ok := cmd.Validate(); !ok {
+		return errors.NewValidationError("invalid webhook payload", details)
+	}
+
+	return uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		subscription, err := uc.repo.Read(txCtx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := helper.RequireWorkspaceManager(txCtx, uc.uow.GetWorkspaceRepo(), subscription.WsID, cmd.RequesterID, cmd.RequesterIsAdmin); err != nil {
+			return err
+		}
+
+		if cmd.URL != nil {
+			subscription.URL = *cmd.URL
+		}
+		if cmd.EventTypes != nil {
+			subscription.EventTypes = cmd.EventTypes
+		}
+		if cmd.Secret != nil {
+			subscription.Secret = *cmd.Secret
+		}
+		if cmd.Active != nil {
+			subscription.Active = *cmd.Active
+		}
+
+		if err := uc.repo.Update(txCtx, subscription); err != nil {
+			return errors.NewInternalError("failed to update webhook", err)
+		}
+		return nil
+	})
+}
+
+func (uc *WebhookUseCase) Delete(ctx context.Context, cmd command.DeleteWebhookCommand) error {
+	subscription, err := uc.repo.Read(ctx, cmd.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := helper.RequireWorkspaceManager(ctx, uc.uow.GetWorkspaceRepo(), subscription.WsID, cmd.RequesterID, cmd.RequesterIsAdmin); err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, cmd.ID); err != nil {
+		return errors.NewInternalError("failed to delete webhook", err)
+	}
+	return nil
+}
+
+// Redeliver queues a fresh copy of a past delivery. The original record is
+// kept untouched so the delivery log stays intact.
+func (uc *WebhookUseCase) Redeliver(ctx context.Context, cmd command.RedeliverWebhookCommand) (*model.WebhookDelivery, error) {
+	subscription, err := uc.repo.Read(ctx, cmd.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := helper.RequireWorkspaceManager(ctx, uc.uow.GetWorkspaceRepo(), subscription.WsID, cmd.RequesterID, cmd.RequesterIsAdmin); err != nil {
+		return nil, err
+	}
+
+	original, err := uc.deliveryRepo.Read(ctx, cmd.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.SubscriptionID != subscription.ID {
+		return nil, errors.NewNotFoundError("delivery not found for this webhook")
+	}
+	if !subscription.Active {
+		return nil, errors.NewConflictError("webhook is inactive", map[string]interface{}{
+			"webhook_id": subscription.ID,
+		})
+	}
+
+	redelivery := &model.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		WsID:           original.WsID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         model.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+		RedeliveryOf:   original.ID,
+	}
+	if err := uc.deliveryRepo.Save(ctx, redelivery); err != nil {
+		return nil, errors.NewInternalError("failed to queue redelivery", err)
+	}
+
+	return redelivery, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
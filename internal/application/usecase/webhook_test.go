@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/application/command"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookUOW struct {
+	port.UnitOfWorkFactory
+	workspaces *fakeWorkspaceRepo
+	webhooks   *fakeWebhookRepo
+	deliveries *fakeWebhookDeliveryRepo
+}
+
+func (u *fakeWebhookUOW) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (u *fakeWebhookUOW) GetWorkspaceRepo() port.WorkspaceRepository { return u.workspaces }
+
+func (u *fakeWebhookUOW) GetWebhookRepo() port.WebhookRepository { return u.webhooks }
+
+func (u *fakeWebhookUOW) GetWebhookDeliveryRepo() port.WebhookDeliveryRepository {
+	return u.deliveries
+}
+
+type fakeWebhookRepo struct {
+	port.WebhookRepository
+	subscription *model.WebhookSubscription
+	created      bool
+	updated      bool
+	deleted      bool
+}
+
+func (r *fakeWebhookRepo) Create(ctx context.Context, s *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	r.created = true
+	return s, nil
+}
+
+func (r *fakeWebhookRepo) Read(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	return r.subscription, nil
+}
+
+func (r *fakeWebhookRepo) Update(ctx context.Context, s *model.WebhookSubscription) error {
+	r.updated = true
+	return nil
+}
+
+func (r *fakeWebhookRepo) Delete(ctx context.Context, id string) error {
+	r.deleted = true
+	return nil
+}
+
+type fakeWebhookDeliveryRepo struct {
+	port.WebhookDeliveryRepository
+	saved []*model.WebhookDelivery
+}
+
+func (r *fakeWebhookDeliveryRepo) Read(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	return &model.WebhookDelivery{ID: id, SubscriptionID: "wh-1", WsID: "ws-1"}, nil
+}
+
+func (r *fakeWebhookDeliveryRepo) Save(ctx context.Context, d *model.WebhookDelivery) error {
+	r.saved = append(r.saved, d)
+	return nil
+}
+
+func newWebhookTestUseCase() (*WebhookUseCase, *fakeWebhookUOW) {
+	uow := &fakeWebhookUOW{
+		workspaces: &fakeWorkspaceRepo{workspace: &model.Workspace{
+			Entity:  vobj.Entity{ID: "ws-1", EntityType: vobj.EntityTypeWorkspace, CreatorID: "user-1"},
+			Members: []string{"user-2"},
+		}},
+		webhooks:   &fakeWebhookRepo{subscription: &model.WebhookSubscription{ID: "wh-1", WsID: "ws-1", Active: true}},
+		deliveries: &fakeWebhookDeliveryRepo{},
+	}
+	return NewWebhookUseCase(uow), uow
+}
+
+func TestWebhookUseCase_ManagingNeedsCreatorOrAdmin(t *testing.T) {
+	ctx := context.Background()
+	url := "https://8.8.8.8/hook"
+
+	for _, requester := range []string{"user-2", "user-3"} {
+		uc, uow := newWebhookTestUseCase()
+
+		_, err := uc.Create(ctx, command.CreateWebhookCommand{WsID: "ws-1", CreatorID: requester, URL: url})
+		assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+
+		err = uc.Update(ctx, command.UpdateWebhookCommand{ID: "wh-1", URL: &url, RequesterID: requester})
+		assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+
+		err = uc.Delete(ctx, command.DeleteWebhookCommand{ID: "wh-1", RequesterID: requester})
+		assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+
+		_, err = uc.Redeliver(ctx, command.RedeliverWebhookCommand{WebhookID: "wh-1", DeliveryID: "d-1", RequesterID: requester})
+		assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+
+		assert.False(t, uow.webhooks.created || uow.webhooks.updated || uow.webhooks.deleted)
+		assert.Empty(t, uow.deliveries.saved)
+	}
+
+	uc, uow := newWebhookTestUseCase()
+	_, err := uc.Create(ctx, command.CreateWebhookCommand{WsID: "ws-1", CreatorID: "user-1", URL: url})
+	assert.NoError(t, err)
+	assert.NoError(t, uc.Update(ctx, command.UpdateWebhookCommand{ID: "wh-1", URL: &url, RequesterID: "admin-1", RequesterIsAdmin: true}))
+	_, err = uc.Redeliver(ctx, command.RedeliverWebhookCommand{WebhookID: "wh-1", DeliveryID: "d-1", RequesterID: "user-1"})
+	assert.NoError(t, err)
+	assert.NoError(t, uc.Delete(ctx, command.DeleteWebhookCommand{ID: "wh-1", RequesterID: "user-1"}))
+	assert.True(t, uow.webhooks.created && uow.webhooks.updated && uow.webhooks.deleted)
+	assert.Len(t, uow.deliveries.saved, 1)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/histopathai/main-service/internal/application/command"
 	"github.com/histopathai/main-service/internal/application/usecase/validator"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/fields"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/port"
@@ -32,13 +33,27 @@ func (uc *WorkspaceUseCase) Create(ctx context.Context, cmd command.CreateWorksp
 		return nil, errors.NewInternalError("failed to convert command to entity", err)
 	}
 
-	if err := uc.validator.ValidateCreate(ctx, entity); err != nil {
-		return nil, err
-	}
+	// Members can be set here without a check: the requester is the creator
+	var createdWorkspace *model.Workspace
+	uowerr := uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		if err := uc.validator.ValidateCreate(txCtx, entity); err != nil {
+			return err
+		}
 
-	createdWorkspace, err := uc.repo.Create(ctx, entity)
-	if err != nil {
-		return nil, errors.NewInternalError("failed to create workspace", err)
+		createdWorkspace, err = uc.repo.Create(txCtx, entity)
+		if err != nil {
+			return errors.NewInternalError("failed to create workspace", err)
+		}
+
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityEvent(domainevent.EntityCreated, createdWorkspace)); err != nil {
+			return errors.NewInternalError("failed to record workspace created event", err)
+		}
+
+		return nil
+	})
+
+	if uowerr != nil {
+		return nil, uowerr
 	}
 
 	return createdWorkspace, nil
@@ -55,13 +70,12 @@ func (uc *WorkspaceUseCase) Update(ctx context.Context, cmd command.UpdateWorksp
 		return errors.NewNotFoundError("workspace id not provided")
 	}
 
-	if err := uc.validator.ValidateUpdate(ctx, id, updates); err != nil {
-		return err
-	}
+	return uc.uow.WithTx(ctx, func(txCtx context.Context) error {
+		if err := uc.validator.ValidateUpdate(txCtx, id, updates); err != nil {
+			return err
+		}
 
-	if updates[fields.WorkspaceAnnotationTypes.DomainName()] != nil {
-		newAnnotationTypeIDs := updates[fields.WorkspaceAnnotationTypes.DomainName()].([]string)
-		currentEntity, err := uc.repo.Read(ctx, id)
+		currentEntity, err := uc.repo.Read(txCtx, id)
 		if err != nil {
 			return errors.NewInternalError("failed to read workspace", err)
 		}
@@ -69,19 +83,47 @@ func (uc *WorkspaceUseCase) Update(ctx context.Context, cmd command.UpdateWorksp
 			return errors.NewNotFoundError("workspace not found")
 		}
 
-		if currentEntity.AnnotationTypes == nil {
-			currentEntity.AnnotationTypes = []string{}
+		if changesWorkspaceAccess(updates) && !currentEntity.CanManageAccess(cmd.RequesterID, cmd.RequesterIsAdmin) {
+			return errors.NewForbiddenError("only the workspace creator or an admin can change its access")
 		}
 
-		if err := uc.validator.ValidateAnnotationTypeRemoval(ctx, id, currentEntity.AnnotationTypes, newAnnotationTypeIDs); err != nil {
-			return err
+		if updates[fields.WorkspaceAnnotationTypes.DomainName()] != nil {
+			newAnnotationTypeIDs := updates[fields.WorkspaceAnnotationTypes.DomainName()].([]string)
+
+			if currentEntity.AnnotationTypes == nil {
+				currentEntity.AnnotationTypes = []string{}
+			}
+
+			if err := uc.validator.ValidateAnnotationTypeRemoval(txCtx, id, currentEntity.AnnotationTypes, newAnnotationTypeIDs); err != nil {
+				return err
+			}
+
 		}
 
-	}
+		if err := uc.repo.Update(txCtx, id, updates); err != nil {
+			return errors.NewInternalError("failed to update workspace", err)
+		}
 
-	if err := uc.repo.Update(ctx, id, updates); err != nil {
-		return errors.NewInternalError("failed to update workspace", err)
-	}
+		if err := uc.uow.GetOutboxRepo().Add(txCtx, domainevent.NewEntityUpdatedEvent(currentEntity, updates)); err != nil {
+			return errors.NewInternalError("failed to record workspace updated event", err)
+		}
 
-	return nil
+		return nil
+	})
+}
+
+// Fields deciding who can read a workspace's images and what they see
+var workspaceAccessFields = []string{
+	fields.EntityCreatorID.DomainName(),
+	fields.WorkspaceMembers.DomainName(),
+	fields.WorkspaceDeIdentified.DomainName(),
+}
+
+func changesWorkspaceAccess(updates map[string]interface{}) bool {
+	for _, field := range workspaceAccessFields {
+		if _, ok := updates[field]; ok {
+			return true
+		}
+	}
+	return false
 }
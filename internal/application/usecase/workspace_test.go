@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/histopathai/main-service/internal/application/command"
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+	"github.com/histopathai/main-service/internal/port"
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWorkspaceUOW struct {
+	port.UnitOfWorkFactory
+	outbox []domainevent.Event
+}
+
+func (u *fakeWorkspaceUOW) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (u *fakeWorkspaceUOW) GetOutboxRepo() port.OutboxRepository {
+	return &fakeWorkspaceOutboxRepo{uow: u}
+}
+
+type fakeWorkspaceOutboxRepo struct {
+	port.OutboxRepository
+	uow *fakeWorkspaceUOW
+}
+
+func (r *fakeWorkspaceOutboxRepo) Add(ctx context.Context, event domainevent.Event) error {
+	r.uow.outbox = append(r.uow.outbox, event)
+	return nil
+}
+
+type fakeWorkspaceRepo struct {
+	port.WorkspaceRepository
+	workspace *model.Workspace
+	updates   map[string]interface{}
+}
+
+func (r *fakeWorkspaceRepo) Read(ctx context.Context, id string) (*model.Workspace, error) {
+	return r.workspace, nil
+}
+
+func (r *fakeWorkspaceRepo) Update(ctx context.Context, id string, updates map[string]interface{}) error {
+	r.updates = updates
+	return nil
+}
+
+func TestWorkspaceUseCase_UpdateAccessNeedsCreatorOrAdmin(t *testing.T) {
+	ctx := context.Background()
+	description := "lung biopsies"
+	otherCreator := "user-2"
+	deIdentified := false
+
+	for _, tc := range []struct {
+		name      string
+		cmd       command.UpdateWorkspaceCommand
+		forbidden bool
+	}{
+		{
+			name:      "member changes members",
+			cmd:       command.UpdateWorkspaceCommand{Members: []string{"user-2", "user-3"}, RequesterID: "user-2"},
+			forbidden: true,
+		},
+		{
+			name:      "member changes creator",
+			cmd:       command.UpdateWorkspaceCommand{UpdateEntityCommand: command.UpdateEntityCommand{CreatorID: &otherCreator}, RequesterID: "user-2"},
+			forbidden: true,
+		},
+		{
+			name:      "member turns off de-identification",
+			cmd:       command.UpdateWorkspaceCommand{DeIdentified: &deIdentified, RequesterID: "user-2"},
+			forbidden: true,
+		},
+		{
+			name:      "unauthenticated changes members",
+			cmd:       command.UpdateWorkspaceCommand{Members: []string{}},
+			forbidden: true,
+		},
+		{
+			name: "member changes description",
+			cmd:  command.UpdateWorkspaceCommand{Description: &description, RequesterID: "user-2"},
+		},
+		{
+			name: "creator changes members",
+			cmd:  command.UpdateWorkspaceCommand{Members: []string{"user-3"}, RequesterID: "user-1"},
+		},
+		{
+			name: "creator turns off de-identification",
+			cmd:  command.UpdateWorkspaceCommand{DeIdentified: &deIdentified, RequesterID: "user-1"},
+		},
+		{
+			name: "admin changes members",
+			cmd:  command.UpdateWorkspaceCommand{Members: []string{"user-3"}, RequesterID: "admin-1", RequesterIsAdmin: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeWorkspaceRepo{workspace: &model.Workspace{
+				Entity:  vobj.Entity{ID: "ws-1", EntityType: vobj.EntityTypeWorkspace, CreatorID: "user-1"},
+				Members: []string{"user-2"},
+			}}
+			uow := &fakeWorkspaceUOW{}
+			uc := NewWorkspaceUseCase(repo, uow)
+
+			tc.cmd.ID = "ws-1"
+			err := uc.Update(ctx, tc.cmd)
+			if tc.forbidden {
+				assert.True(t, errors.IsType(err, errors.ErrorTypeForbidden), err)
+				assert.Nil(t, repo.updates)
+				assert.Empty(t, uow.outbox)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, repo.updates)
+			assert.Len(t, uow.outbox, 1)
+		})
+	}
+}
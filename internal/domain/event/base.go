@@ -1,6 +1,10 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
 
 type Event interface {
 	GetEventID() string
@@ -25,3 +29,29 @@ func (e BaseEvent) GetEventType() EventType {
 func (e BaseEvent) GetTimestamp() time.Time {
 	return e.Timestamp
 }
+
+// ImageIDOf returns the image an event concerns, or "" if it is not image related.
+func ImageIDOf(event Event) string {
+	switch e := event.(type) {
+	case *ImageProcessReqEvent:
+		return e.Content.Parent.ID
+	case *ImageProcessCompleteEvent:
+		return e.ImageID
+	case *NewFileExistEvent:
+		if e.Content.Parent.Type == vobj.ParentTypeImage {
+			return e.Content.Parent.ID
+		}
+	case *DeleteFileEvent:
+		if e.Content.Parent.Type == vobj.ParentTypeImage {
+			return e.Content.Parent.ID
+		}
+	case *EntityEvent:
+		if e.EntityType == vobj.EntityTypeImage {
+			return e.EntityID
+		}
+		if e.Parent.Type == vobj.ParentTypeImage {
+			return e.Parent.ID
+		}
+	}
+	return ""
+}
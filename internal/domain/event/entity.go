@@ -0,0 +1,113 @@
+package event
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
+
+type EntityAction string
+
+const (
+	EntityCreated     EntityAction = "created"
+	EntityUpdated     EntityAction = "updated"
+	EntityDeleted     EntityAction = "deleted"
+	EntityTransferred EntityAction = "transferred"
+	// EntityProcessed is emitted for images once every derived file exists.
+	EntityProcessed EntityAction = "processed"
+)
+
+// EntityEventType builds the versioned event type for an entity lifecycle action,
+// e.g. ("annotation", "created") -> "annotation.created.v1".
+func EntityEventType(entityType vobj.EntityType, action EntityAction) EventType {
+	return EventType(string(entityType) + "." + string(action) + ".v1")
+}
+
+// EntityRef is the subset of an entity needed to describe it in a lifecycle event.
+type EntityRef interface {
+	GetID() string
+	GetEntityType() vobj.EntityType
+	GetParent() *vobj.ParentRef
+}
+
+// EntityEvent is emitted when a workspace, patient, image, annotation or
+// annotation type is created, updated, deleted or transferred, and when an
+// image finishes processing.
+type EntityEvent struct {
+	BaseEvent
+	Action     EntityAction
+	EntityType vobj.EntityType
+	EntityID   string
+	WsID       string
+	Parent     vobj.ParentRef
+
+	// Set for transfers only
+	PreviousParent *vobj.ParentRef
+
+	// Set for updates only; domain names of the changed fields
+	ChangedFields []string
+}
+
+func NewEntityEvent(action EntityAction, entity EntityRef) *EntityEvent {
+	entityType := entity.GetEntityType()
+	return &EntityEvent{
+		BaseEvent: BaseEvent{
+			EventID:   uuid.New().String(),
+			EventType: EntityEventType(entityType, action),
+			Timestamp: time.Now(),
+		},
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entity.GetID(),
+		WsID:       WorkspaceIDOf(entity),
+		Parent:     *entity.GetParent(),
+	}
+}
+
+func NewEntityUpdatedEvent(entity EntityRef, updates map[string]interface{}) *EntityEvent {
+	e := NewEntityEvent(EntityUpdated, entity)
+	for field := range updates {
+		e.ChangedFields = append(e.ChangedFields, field)
+	}
+	return e
+}
+
+// NewEntityTransferredEvent records a move under newParentID. newWsID is the
+// workspace the entity ends up in, which differs from newParentID when the
+// new parent is not itself a workspace.
+func NewEntityTransferredEvent(entity EntityRef, previousParent vobj.ParentRef, newParentID string, newWsID string) *EntityEvent {
+	e := NewEntityEvent(EntityTransferred, entity)
+	e.PreviousParent = &previousParent
+	e.Parent = vobj.ParentRef{ID: newParentID, Type: previousParent.Type}
+	e.WsID = newWsID
+	return e
+}
+
+// WorkspaceIDOf returns the workspace an entity belongs to, or "" if it is not workspace scoped.
+func WorkspaceIDOf(entity EntityRef) string {
+	switch e := entity.(type) {
+	case *model.Workspace:
+		return e.ID
+	case *model.Image:
+		return e.WsID
+	case *model.Annotation:
+		return e.WsID
+	}
+
+	if parent := entity.GetParent(); parent != nil && parent.Type == vobj.ParentTypeWorkspace {
+		return parent.ID
+	}
+	return ""
+}
+
+// IsEntityEventType reports whether t is a lifecycle event type.
+func IsEntityEventType(t EventType) bool {
+	for _, et := range EntityEventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
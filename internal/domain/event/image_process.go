@@ -10,6 +10,10 @@ type ImageProcessReqEvent struct {
 	model.Content
 
 	ProcessingVersion vobj.ProcessingVersion
+	// Empty on requests issued before profiles existed; treated as pyramid
+	ProcessingProfile vobj.ProcessingProfile
+	// Empty means normal
+	Priority vobj.ProcessingPriority
 }
 
 type ProcessResult struct {
@@ -20,7 +24,10 @@ type ProcessResult struct {
 
 type ImageProcessCompleteEvent struct {
 	BaseEvent
-	ImageID           string
+	ImageID string
+	// EventID of the ImageProcessReqEvent this completes; results are only
+	// applied while it is the image's ActiveEventID
+	RequestEventID    string
 	ProcessingVersion vobj.ProcessingVersion
 	Contents          []model.Content
 
@@ -11,3 +11,37 @@ const (
 	NewFileExistEventType EventType = "new.file.exist.v1"
 	DeleteFileEventType   EventType = "delete.file.v1"
 )
+
+const (
+	WorkspaceCreatedEventType EventType = "workspace.created.v1"
+	WorkspaceUpdatedEventType EventType = "workspace.updated.v1"
+	WorkspaceDeletedEventType EventType = "workspace.deleted.v1"
+
+	PatientCreatedEventType     EventType = "patient.created.v1"
+	PatientUpdatedEventType     EventType = "patient.updated.v1"
+	PatientDeletedEventType     EventType = "patient.deleted.v1"
+	PatientTransferredEventType EventType = "patient.transferred.v1"
+
+	ImageCreatedEventType     EventType = "image.created.v1"
+	ImageUpdatedEventType     EventType = "image.updated.v1"
+	ImageDeletedEventType     EventType = "image.deleted.v1"
+	ImageTransferredEventType EventType = "image.transferred.v1"
+	ImageProcessedEventType   EventType = "image.processed.v1"
+
+	AnnotationCreatedEventType EventType = "annotation.created.v1"
+	AnnotationUpdatedEventType EventType = "annotation.updated.v1"
+	AnnotationDeletedEventType EventType = "annotation.deleted.v1"
+
+	AnnotationTypeCreatedEventType EventType = "annotation_type.created.v1"
+	AnnotationTypeUpdatedEventType EventType = "annotation_type.updated.v1"
+	AnnotationTypeDeletedEventType EventType = "annotation_type.deleted.v1"
+)
+
+// EntityEventTypes lists every entity lifecycle event type.
+var EntityEventTypes = []EventType{
+	WorkspaceCreatedEventType, WorkspaceUpdatedEventType, WorkspaceDeletedEventType,
+	PatientCreatedEventType, PatientUpdatedEventType, PatientDeletedEventType, PatientTransferredEventType,
+	ImageCreatedEventType, ImageUpdatedEventType, ImageDeletedEventType, ImageTransferredEventType, ImageProcessedEventType,
+	AnnotationCreatedEventType, AnnotationUpdatedEventType, AnnotationDeletedEventType,
+	AnnotationTypeCreatedEventType, AnnotationTypeUpdatedEventType, AnnotationTypeDeletedEventType,
+}
@@ -16,6 +16,7 @@ const (
 	ImageProcessingRetryCount      ImageField = "processing.retry_count"
 	ImageProcessingLastProcessedAt ImageField = "processing.last_processed_at"
 	ImageProcessingActiveEventID   ImageField = "processing.active_event_id"
+	ImageProcessingProfile         ImageField = "processing.profile"
 
 	// Content IDs
 	ImageOriginContentID    ImageField = "origin_content_id"
@@ -24,6 +25,8 @@ const (
 	ImageIndexmapContentID  ImageField = "indexmap_content_id"
 	ImageTilesContentID     ImageField = "tiles_content_id"
 	ImageZipTilesContentID  ImageField = "ziptiles_content_id"
+	ImageLabelContentID     ImageField = "label_content_id"
+	ImageMacroContentID     ImageField = "macro_content_id"
 
 	ImageSize          ImageField = "size"
 	ImageMagnification ImageField = "magnification"
@@ -46,6 +49,8 @@ func (f ImageField) APIName() string {
 		return "retry_count"
 	case ImageProcessingLastProcessedAt:
 		return "last_processed_at"
+	case ImageProcessingProfile:
+		return "profile"
 	case ImageMagnificationObjective:
 		return "objective"
 	case ImageMagnificationNativeLevel:
@@ -83,6 +88,8 @@ func (f ImageField) DomainName() string {
 		return "ProcessingLastProcessedAt"
 	case ImageProcessingActiveEventID:
 		return "ProcessingActiveEventID"
+	case ImageProcessingProfile:
+		return "ProcessingProfile"
 	case ImageOriginContentID:
 		return "OriginContentID"
 	case ImageThumbnailContentID:
@@ -95,6 +102,10 @@ func (f ImageField) DomainName() string {
 		return "TilesContentID"
 	case ImageZipTilesContentID:
 		return "ZipTilesContentID"
+	case ImageLabelContentID:
+		return "LabelContentID"
+	case ImageMacroContentID:
+		return "MacroContentID"
 	case ImageSize:
 		return "Size"
 	case ImageMagnification:
@@ -115,8 +126,10 @@ func (f ImageField) IsValid() bool {
 	case ImageFormat, ImageWidth, ImageHeight, ImageWsID,
 		ImageProcessingStatus, ImageProcessingVersion, ImageProcessingFailureReason,
 		ImageProcessingRetryCount, ImageProcessingLastProcessedAt, ImageProcessingActiveEventID,
+		ImageProcessingProfile,
 		ImageOriginContentID, ImageThumbnailContentID, ImageDziContentID,
 		ImageIndexmapContentID, ImageTilesContentID, ImageZipTilesContentID,
+		ImageLabelContentID, ImageMacroContentID,
 		ImageSize, ImageMagnification,
 		ImageMagnificationObjective, ImageMagnificationNativeLevel, ImageMagnificationScanMagnification:
 		return true
@@ -128,9 +141,10 @@ func (f ImageField) IsValid() bool {
 var ImageFields = []ImageField{
 	ImageFormat, ImageWidth, ImageHeight, ImageWsID,
 	ImageProcessingStatus, ImageProcessingVersion, ImageProcessingFailureReason,
-	ImageProcessingRetryCount, ImageProcessingLastProcessedAt,
+	ImageProcessingRetryCount, ImageProcessingLastProcessedAt, ImageProcessingProfile,
 	ImageOriginContentID, ImageThumbnailContentID, ImageDziContentID,
 	ImageIndexmapContentID, ImageTilesContentID, ImageZipTilesContentID,
+	ImageLabelContentID, ImageMacroContentID,
 	ImageSize, ImageMagnification,
 	ImageMagnificationObjective, ImageMagnificationNativeLevel, ImageMagnificationScanMagnification,
 }
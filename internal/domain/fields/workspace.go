@@ -10,6 +10,8 @@ const (
 	WorkspaceResourceURL     WorkspaceField = "resource_url"
 	WorkspaceReleaseYear     WorkspaceField = "release_year"
 	WorkspaceAnnotationTypes WorkspaceField = "annotation_types"
+	WorkspaceMembers         WorkspaceField = "members"
+	WorkspaceDeIdentified    WorkspaceField = "de_identified"
 )
 
 func (f WorkspaceField) APIName() string {
@@ -36,6 +38,10 @@ func (f WorkspaceField) DomainName() string {
 		return "ReleaseYear"
 	case WorkspaceAnnotationTypes:
 		return "AnnotationTypes"
+	case WorkspaceMembers:
+		return "Members"
+	case WorkspaceDeIdentified:
+		return "DeIdentified"
 	default:
 		return ""
 	}
@@ -43,7 +49,7 @@ func (f WorkspaceField) DomainName() string {
 
 func (f WorkspaceField) IsValid() bool {
 	switch f {
-	case WorkspaceOrganType, WorkspaceOrganization, WorkspaceDescription, WorkspaceLicense, WorkspaceResourceURL, WorkspaceReleaseYear, WorkspaceAnnotationTypes:
+	case WorkspaceOrganType, WorkspaceOrganization, WorkspaceDescription, WorkspaceLicense, WorkspaceResourceURL, WorkspaceReleaseYear, WorkspaceAnnotationTypes, WorkspaceMembers, WorkspaceDeIdentified:
 		return true
 	default:
 		return false
@@ -51,5 +57,5 @@ func (f WorkspaceField) IsValid() bool {
 }
 
 var WorkspaceFields = []WorkspaceField{
-	WorkspaceOrganType, WorkspaceOrganization, WorkspaceDescription, WorkspaceLicense, WorkspaceResourceURL, WorkspaceReleaseYear, WorkspaceAnnotationTypes,
+	WorkspaceOrganType, WorkspaceOrganization, WorkspaceDescription, WorkspaceLicense, WorkspaceResourceURL, WorkspaceReleaseYear, WorkspaceAnnotationTypes, WorkspaceMembers, WorkspaceDeIdentified,
 }
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type EventDirection string
+
+const (
+	EventPublished EventDirection = "published"
+	EventConsumed  EventDirection = "consumed"
+)
+
+// EventLogEntry is a published or consumed event kept for auditing and replay.
+type EventLogEntry struct {
+	ID        string
+	EventID   string
+	EventType string
+	Direction EventDirection
+	// Subscription name for consumed events, empty for published ones
+	Channel string
+	ImageID string
+	// Serialized event DTO, as understood by the event serializer
+	Payload []byte
+
+	OccurredAt time.Time
+	RecordedAt time.Time
+}
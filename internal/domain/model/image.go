@@ -24,7 +24,36 @@ type Image struct {
 	IndexmapContentID  *string
 	TilesContentID     *string
 	ZipTilesContentID  *string
+	// Associated images; only some whole-slide formats carry them
+	LabelContentID *string
+	MacroContentID *string
 
 	// Processing state
 	Processing *vobj.ProcessingInfo
 }
+
+// MissingOutputs lists the derived files the image's processing profile
+// requires but the image does not reference yet.
+func (i *Image) MissingOutputs() []vobj.ProcessingOutput {
+	var profile vobj.ProcessingProfile
+	var version vobj.ProcessingVersion
+	if i.Processing != nil {
+		profile, version = i.Processing.Profile, i.Processing.Version
+	}
+
+	refs := map[vobj.ProcessingOutput]*string{
+		vobj.OutputThumbnail: i.ThumbnailContentID,
+		vobj.OutputDZI:       i.DziContentID,
+		vobj.OutputTiles:     i.TilesContentID,
+		vobj.OutputZipTiles:  i.ZipTilesContentID,
+		vobj.OutputIndexMap:  i.IndexmapContentID,
+	}
+
+	var missing []vobj.ProcessingOutput
+	for _, output := range profile.RequiredOutputs(version) {
+		if id := refs[output]; id == nil || *id == "" {
+			missing = append(missing, output)
+		}
+	}
+	return missing
+}
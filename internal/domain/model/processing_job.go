@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
+
+type ProcessingJobStatus string
+
+const (
+	ProcessingJobRunning   ProcessingJobStatus = "running"
+	ProcessingJobSucceeded ProcessingJobStatus = "succeeded"
+	ProcessingJobFailed    ProcessingJobStatus = "failed"
+	// Superseded by a reprocess or cancel request
+	ProcessingJobCancelled ProcessingJobStatus = "cancelled"
+)
+
+// ProcessingJob records one worker execution started for an image, so its
+// state can be followed after the request that started it is gone.
+//
+// Jobs double as the image's processing history: one record per attempt.
+type ProcessingJob struct {
+	ID      string
+	ImageID string
+	WsID    string
+	// Image format, e.g. svs
+	Format string
+	// ImageProcessReqEvent that started the execution
+	EventID           string
+	ProcessingVersion vobj.ProcessingVersion
+	ProcessingProfile vobj.ProcessingProfile
+
+	Backend       string
+	ExecutionName string
+	Tier          string
+	Attempt       int
+
+	Status        ProcessingJobStatus
+	FailureReason string
+	// Bytes per output, as reported with the result
+	OutputSizes map[vobj.ProcessingOutput]int64
+
+	// Nil for attempts started before the processing queue existed
+	QueuedAt   *time.Time
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	UpdatedAt  time.Time
+}
+
+// QueueWait is how long the attempt waited for a worker.
+func (j *ProcessingJob) QueueWait() (time.Duration, bool) {
+	if j.QueuedAt == nil {
+		return 0, false
+	}
+	return j.StartedAt.Sub(*j.QueuedAt), true
+}
+
+// Duration is how long the execution ran.
+func (j *ProcessingJob) Duration() (time.Duration, bool) {
+	if j.FinishedAt == nil {
+		return 0, false
+	}
+	return j.FinishedAt.Sub(j.StartedAt), true
+}
+
+func (j *ProcessingJob) OutputBytes() int64 {
+	var total int64
+	for _, size := range j.OutputSizes {
+		total += size
+	}
+	return total
+}
+
+func (j *ProcessingJob) Finish(status ProcessingJobStatus, reason string) {
+	now := time.Now()
+	j.Status = status
+	j.FailureReason = reason
+	j.FinishedAt = &now
+	j.UpdatedAt = now
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
+
+// ProcessingQueueEntry is an image waiting for a worker. An image has at most
+// one entry; enqueueing it again replaces the entry.
+//
+// The queue is served by priority, then round, then enqueue time. A
+// workspace's entries get consecutive rounds, so workspaces with work queued
+// take turns instead of being served in arrival order.
+type ProcessingQueueEntry struct {
+	ImageID string
+	WsID    string
+	// ImageProcessReqEvent the entry was queued for; the entry is stale once
+	// the image's ActiveEventID moved on
+	EventID           string
+	ContentID         string
+	ProcessingVersion vobj.ProcessingVersion
+	ProcessingProfile vobj.ProcessingProfile
+
+	Priority   vobj.ProcessingPriority
+	Round      int64
+	EnqueuedAt time.Time
+}
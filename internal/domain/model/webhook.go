@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// WebhookSubscription registers an external endpoint that receives the
+// domain events of a single workspace.
+type WebhookSubscription struct {
+	ID        string
+	WsID      string
+	CreatorID string
+	URL       string
+	Secret    string
+	Active    bool
+
+	// Event types to deliver. Empty or "*" means every event; "image.*"
+	// matches every image event.
+	EventTypes []string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Matches reports whether the subscription's event filter accepts eventType.
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, filter := range s.EventTypes {
+		if filter == "*" || filter == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(filter, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event sent to one subscription. It doubles as the
+// delivery log: the outcome of the latest attempt is kept on the record.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	WsID           string
+	EventID        string
+	EventType      string
+	Payload        []byte
+
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+
+	// Set when the delivery was created by a manual redeliver
+	RedeliveryOf string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/histopathai/main-service/internal/domain/vobj"
+import (
+	"slices"
+
+	"github.com/histopathai/main-service/internal/domain/vobj"
+)
 
 type Workspace struct {
 	vobj.Entity
@@ -11,4 +15,22 @@ type Workspace struct {
 	ResourceURL     *string
 	ReleaseYear     *int
 	AnnotationTypes []string
+	// Users besides the creator who may read the workspace's images
+	Members []string
+	// Slide labels and macro images are redacted when served
+	DeIdentified bool
+}
+
+// HasMember reports whether userID created the workspace or is one of its members.
+func (w *Workspace) HasMember(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	return w.CreatorID == userID || slices.Contains(w.Members, userID)
+}
+
+// CanManageAccess reports whether userID may change who can read the
+// workspace: only its creator and admins may.
+func (w *Workspace) CanManageAccess(userID string, admin bool) bool {
+	return admin || (userID != "" && w.CreatorID == userID)
 }
@@ -9,7 +9,9 @@ func (ct ContentType) GetCategory() string {
 		ContentTypeImageVMS, ContentTypeImageVMU, ContentTypeImageSCN,
 		ContentTypeImageMIRAX, ContentTypeImageBIF, ContentTypeImageDNG,
 		ContentTypeImageBMP, ContentTypeImageJPEG, ContentTypeImagePNG,
-		ContentTypeThumbnailJPEG, ContentTypeThumbnailPNG:
+		ContentTypeThumbnailJPEG, ContentTypeThumbnailPNG,
+		ContentTypeLabelJPEG, ContentTypeLabelPNG,
+		ContentTypeMacroJPEG, ContentTypeMacroPNG:
 		return "image"
 	case ContentTypeApplicationZip:
 		return "archive"
@@ -27,6 +29,8 @@ func (ct ContentType) IsValid() bool {
 		ContentTypeImageMIRAX, ContentTypeImageBIF, ContentTypeImageDNG,
 		ContentTypeImageBMP, ContentTypeImageJPEG, ContentTypeImagePNG,
 		ContentTypeThumbnailJPEG, ContentTypeThumbnailPNG,
+		ContentTypeLabelJPEG, ContentTypeLabelPNG,
+		ContentTypeMacroJPEG, ContentTypeMacroPNG,
 		ContentTypeApplicationZip, ContentTypeApplicationJSON,
 		ContentTypeApplicationDZI, ContentTypeApplicationOctetStream:
 		return true
@@ -35,7 +39,7 @@ func (ct ContentType) IsValid() bool {
 	}
 }
 func (ct ContentType) IsOriginImage() bool {
-	if ct.GetCategory() == "image" && ct.IsThumbnail() == false {
+	if ct.GetCategory() == "image" && ct.IsThumbnail() == false && ct.IsAssociatedImage() == false {
 		return true
 	}
 	return false
@@ -50,6 +54,30 @@ func (ct ContentType) IsThumbnail() bool {
 	}
 }
 
+func (ct ContentType) IsLabel() bool {
+	switch ct {
+	case ContentTypeLabelJPEG, ContentTypeLabelPNG:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ct ContentType) IsMacro() bool {
+	switch ct {
+	case ContentTypeMacroJPEG, ContentTypeMacroPNG:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAssociatedImage reports whether ct is an image stored alongside the
+// slide in a whole-slide file rather than derived from it.
+func (ct ContentType) IsAssociatedImage() bool {
+	return ct.IsLabel() || ct.IsMacro()
+}
+
 func (ct ContentType) IsIndexMap() bool {
 	if ContentTypeApplicationJSON == ct {
 		return true
@@ -80,9 +108,9 @@ func (ct ContentType) IsTiles() bool {
 
 func (ct ContentType) ToStandardType() ContentType {
 	switch ct {
-	case ContentTypeThumbnailJPEG:
+	case ContentTypeThumbnailJPEG, ContentTypeLabelJPEG, ContentTypeMacroJPEG:
 		return ContentTypeImageJPEG
-	case ContentTypeThumbnailPNG:
+	case ContentTypeThumbnailPNG, ContentTypeLabelPNG, ContentTypeMacroPNG:
 		return ContentTypeImagePNG
 	default:
 		return ct
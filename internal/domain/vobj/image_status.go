@@ -1,5 +1,9 @@
 package vobj
 
+import (
+	"github.com/histopathai/main-service/internal/shared/errors"
+)
+
 type ImageStatus string
 
 func (is ImageStatus) String() string {
@@ -8,9 +12,63 @@ func (is ImageStatus) String() string {
 
 func (is ImageStatus) IsValid() bool {
 	switch is {
-	case StatusPending, StatusProcessing, StatusProcessed, StatusFailed, StatusFailedPermanent, StatusDeleting:
+	case StatusPending, StatusUploaded, StatusQueued, StatusProcessing, StatusProcessed, StatusFailed, StatusFailedPermanent, StatusDeleting, StatusCancelled:
 		return true
 	default:
 		return false
 	}
 }
+
+// imageStatusTransitions is the image processing lifecycle:
+//
+//	pending -> uploaded -> queued -> processing -> processed | failed | cancelled
+//	queued -> failed (the attempt could not be started) | cancelled
+//	failed -> queued (retry) | failed_permanent
+//	processing | processed | failed_permanent | cancelled -> queued (reprocess)
+//
+// Every attempt waits in the processing queue until the scheduler hands it to
+// a worker. queued -> queued re-enqueues and processing -> processing
+// restarts the attempt. Every state but deleting may move to deleting, which
+// is final.
+var imageStatusTransitions = map[ImageStatus][]ImageStatus{
+	StatusPending:         {StatusUploaded, StatusDeleting},
+	StatusUploaded:        {StatusQueued, StatusDeleting},
+	StatusQueued:          {StatusQueued, StatusProcessing, StatusFailed, StatusCancelled, StatusDeleting},
+	StatusProcessing:      {StatusQueued, StatusProcessing, StatusProcessed, StatusFailed, StatusCancelled, StatusDeleting},
+	StatusProcessed:       {StatusQueued, StatusDeleting},
+	StatusFailed:          {StatusQueued, StatusFailedPermanent, StatusCancelled, StatusDeleting},
+	StatusFailedPermanent: {StatusQueued, StatusDeleting},
+	StatusCancelled:       {StatusQueued, StatusDeleting},
+	StatusDeleting:        {},
+}
+
+// AllowedTransitions lists the statuses the image may move to next. Images
+// stored before statuses were tracked have none and count as pending.
+func (is ImageStatus) AllowedTransitions() []ImageStatus {
+	if is == "" {
+		is = StatusPending
+	}
+	return imageStatusTransitions[is]
+}
+
+func (is ImageStatus) CanTransitionTo(next ImageStatus) bool {
+	for _, allowed := range is.AllowedTransitions() {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns a conflict error if the lifecycle does not allow
+// moving from is to next.
+func (is ImageStatus) ValidateTransition(next ImageStatus) error {
+	if is.CanTransitionTo(next) {
+		return nil
+	}
+	return errors.NewConflictError("invalid image status transition", map[string]interface{}{
+		"current_status": is,
+		"new_status":     next,
+		"allowed":        is.AllowedTransitions(),
+	})
+}
@@ -2,6 +2,8 @@ package vobj
 
 import (
 	"time"
+
+	"github.com/histopathai/main-service/internal/shared/errors"
 )
 
 type ProcessingVersion string
@@ -27,12 +29,22 @@ func (pv ProcessingVersion) IsValid() bool {
 type ProcessingInfo struct {
 	Status          ImageStatus
 	Version         ProcessingVersion
+	Profile         ProcessingProfile
 	FailureReason   *string
 	RetryCount      int
 	LastProcessedAt time.Time
 	ActiveEventID   string
 }
 
+// NewProcessingInfo starts the lifecycle of a newly created image.
+func NewProcessingInfo(version ProcessingVersion) *ProcessingInfo {
+	return &ProcessingInfo{
+		Status:          StatusPending,
+		Version:         version,
+		LastProcessedAt: time.Now(),
+	}
+}
+
 func (pi *ProcessingInfo) IsRetryable(maxRetries int) bool {
 	if pi.Status == StatusDeleting {
 		return false
@@ -43,30 +55,88 @@ func (pi *ProcessingInfo) IsRetryable(maxRetries int) bool {
 	return false
 }
 
-func (pi *ProcessingInfo) MarkForRetry() {
-	pi.Status = StatusProcessing
-	pi.RetryCount++
-	now := time.Now()
-	pi.LastProcessedAt = now
+// The Mark methods move the image through its lifecycle and return a
+// conflict error, leaving pi unchanged, if the transition is not allowed.
+
+func (pi *ProcessingInfo) MarkAsUploaded() error {
+	if err := pi.transition(StatusUploaded); err != nil {
+		return err
+	}
+	pi.FailureReason = nil
+	return nil
+}
+
+// MarkAsQueued requests a new attempt, also for a finished image
+// (reprocessing).
+func (pi *ProcessingInfo) MarkAsQueued() error {
+	if err := pi.transition(StatusQueued); err != nil {
+		return err
+	}
+	pi.FailureReason = nil
+	return nil
+}
+
+// MarkAsProcessing starts the queued attempt on a worker.
+func (pi *ProcessingInfo) MarkAsProcessing() error {
+	if err := pi.transition(StatusProcessing); err != nil {
+		return err
+	}
+	pi.FailureReason = nil
+	return nil
 }
 
-func (pi *ProcessingInfo) MarkAsProcessing() {
-	pi.Status = StatusProcessing
-	now := time.Now()
-	pi.LastProcessedAt = now
+func (pi *ProcessingInfo) MarkForRetry() error {
+	if pi.Status != StatusFailed {
+		return errors.NewConflictError("only failed images can be retried", map[string]interface{}{
+			"current_status": pi.Status,
+		})
+	}
+	if err := pi.transition(StatusQueued); err != nil {
+		return err
+	}
+	pi.RetryCount++
+	return nil
 }
 
-func (pi *ProcessingInfo) MarkAsProcessed(version ProcessingVersion) {
-	pi.Status = StatusProcessed
+func (pi *ProcessingInfo) MarkAsProcessed(version ProcessingVersion) error {
+	if err := pi.transition(StatusProcessed); err != nil {
+		return err
+	}
 	pi.Version = version
-	now := time.Now()
-	pi.LastProcessedAt = now
 	pi.FailureReason = nil
+	return nil
+}
+
+func (pi *ProcessingInfo) MarkAsFailed(reason string) error {
+	if err := pi.transition(StatusFailed); err != nil {
+		return err
+	}
+	pi.FailureReason = &reason
+	return nil
 }
 
-func (pi *ProcessingInfo) MarkAsFailed(reason string) {
-	pi.Status = StatusFailed
+// MarkAsFailedPermanent gives up on the image; it is not retried again.
+func (pi *ProcessingInfo) MarkAsFailedPermanent(reason string) error {
+	if err := pi.transition(StatusFailedPermanent); err != nil {
+		return err
+	}
+	pi.FailureReason = &reason
+	return nil
+}
+
+func (pi *ProcessingInfo) MarkAsCancelled(reason string) error {
+	if err := pi.transition(StatusCancelled); err != nil {
+		return err
+	}
 	pi.FailureReason = &reason
-	now := time.Now()
-	pi.LastProcessedAt = now
+	return nil
+}
+
+func (pi *ProcessingInfo) transition(next ImageStatus) error {
+	if err := pi.Status.ValidateTransition(next); err != nil {
+		return err
+	}
+	pi.Status = next
+	pi.LastProcessedAt = time.Now()
+	return nil
 }
@@ -0,0 +1,51 @@
+package vobj
+
+import (
+	"testing"
+
+	"github.com/histopathai/main-service/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessingInfo_Lifecycle(t *testing.T) {
+	info := NewProcessingInfo(ProcessingV2)
+	require.Equal(t, StatusPending, info.Status)
+
+	// Processing needs an uploaded origin
+	err := info.MarkAsQueued()
+	assert.True(t, errors.IsType(err, errors.ErrorTypeConflict))
+	assert.Equal(t, StatusPending, info.Status)
+
+	require.NoError(t, info.MarkAsUploaded())
+	// Only the scheduler starts queued attempts
+	assert.Error(t, info.MarkAsProcessing())
+	require.NoError(t, info.MarkAsQueued())
+	require.NoError(t, info.MarkAsProcessing())
+	require.NoError(t, info.MarkAsFailed("tiler crashed"))
+	require.NoError(t, info.MarkForRetry())
+	assert.Equal(t, StatusQueued, info.Status)
+	assert.Equal(t, 1, info.RetryCount)
+	require.NoError(t, info.MarkAsProcessing())
+
+	require.NoError(t, info.MarkAsProcessed(ProcessingV1))
+	assert.Nil(t, info.FailureReason)
+	assert.Equal(t, ProcessingV1, info.Version)
+
+	// Finished images can only be reprocessed
+	assert.Error(t, info.MarkAsCancelled("too late"))
+	assert.Error(t, info.MarkForRetry())
+	assert.Error(t, info.MarkAsProcessing())
+	require.NoError(t, info.MarkAsQueued())
+}
+
+func TestImageStatus_DeletingIsFinal(t *testing.T) {
+	for status := range imageStatusTransitions {
+		assert.True(t, status.IsValid(), status)
+		if status != StatusDeleting {
+			assert.True(t, status.CanTransitionTo(StatusDeleting), status)
+		}
+	}
+	assert.Empty(t, StatusDeleting.AllowedTransitions())
+	assert.True(t, ImageStatus("").CanTransitionTo(StatusUploaded))
+}
@@ -0,0 +1,48 @@
+package vobj
+
+// ProcessingPriority orders the processing queue; within a priority,
+// workspaces take turns.
+type ProcessingPriority string
+
+const (
+	PriorityHigh   ProcessingPriority = "high"
+	PriorityNormal ProcessingPriority = "normal"
+	PriorityLow    ProcessingPriority = "low"
+)
+
+func (pp ProcessingPriority) String() string {
+	return string(pp)
+}
+
+func (pp ProcessingPriority) IsValid() bool {
+	switch pp {
+	case PriorityHigh, PriorityNormal, PriorityLow:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rank is higher for more urgent priorities. Unknown priorities rank as normal.
+func (pp ProcessingPriority) Rank() int {
+	switch pp {
+	case PriorityHigh:
+		return 2
+	case PriorityLow:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// ProcessingPriorityFromRank is the inverse of Rank.
+func ProcessingPriorityFromRank(rank int) ProcessingPriority {
+	switch {
+	case rank >= 2:
+		return PriorityHigh
+	case rank <= 0:
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
@@ -0,0 +1,119 @@
+package vobj
+
+// ProcessingProfile selects what a worker produces for an origin image.
+type ProcessingProfile string
+
+const (
+	// ProfileSnapshot is for plain images: a thumbnail and a single-level DZI
+	// with loose tiles.
+	ProfileSnapshot ProcessingProfile = "snapshot"
+	// ProfilePyramid is for whole slide images: a full DZI pyramid.
+	ProfilePyramid ProcessingProfile = "pyramid"
+)
+
+func (pp ProcessingProfile) String() string {
+	return string(pp)
+}
+
+func (pp ProcessingProfile) IsValid() bool {
+	switch pp {
+	case ProfileSnapshot, ProfilePyramid:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProcessingOutput is a derived file an image needs before it is processed.
+type ProcessingOutput string
+
+const (
+	OutputThumbnail ProcessingOutput = "thumbnail"
+	OutputDZI       ProcessingOutput = "dzi"
+	OutputTiles     ProcessingOutput = "tiles"
+	OutputZipTiles  ProcessingOutput = "zip_tiles"
+	OutputIndexMap  ProcessingOutput = "indexmap"
+)
+
+// OutputOf returns the output a derived content of type ct provides.
+func OutputOf(ct ContentType) (ProcessingOutput, bool) {
+	switch {
+	case ct.IsThumbnail():
+		return OutputThumbnail, true
+	case ct.IsDZI():
+		return OutputDZI, true
+	case ct.IsTiles():
+		return OutputTiles, true
+	case ct.IsArchive():
+		return OutputZipTiles, true
+	case ct.IsIndexMap():
+		return OutputIndexMap, true
+	default:
+		return "", false
+	}
+}
+
+// RequiredOutputs lists what the profile produces under the given version.
+// Images processed before profiles existed have none and count as pyramids.
+func (pp ProcessingProfile) RequiredOutputs(version ProcessingVersion) []ProcessingOutput {
+	if pp == ProfileSnapshot || version == ProcessingV1 {
+		return []ProcessingOutput{OutputThumbnail, OutputDZI, OutputTiles}
+	}
+	return []ProcessingOutput{OutputThumbnail, OutputDZI, OutputZipTiles, OutputIndexMap}
+}
+
+// PipelineRule maps origin content types up to a size to a profile.
+type PipelineRule struct {
+	ContentTypes []ContentType
+	// Inclusive; 0 means any size. Unknown (0) sizes match every rule.
+	MaxSize int64
+	Profile ProcessingProfile
+}
+
+func (r PipelineRule) matches(contentType ContentType, size int64) bool {
+	if r.MaxSize > 0 && size > r.MaxSize {
+		return false
+	}
+	for _, ct := range r.ContentTypes {
+		if ct == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// PipelineRegistry resolves the processing profile of an origin image. The
+// first matching rule wins.
+type PipelineRegistry struct {
+	rules    []PipelineRule
+	fallback ProcessingProfile
+}
+
+func NewPipelineRegistry(fallback ProcessingProfile, rules ...PipelineRule) *PipelineRegistry {
+	return &PipelineRegistry{rules: rules, fallback: fallback}
+}
+
+// SnapshotMaxSize bounds plain images processed as snapshots; larger ones
+// (e.g. stitched scans) still get a pyramid.
+const SnapshotMaxSize = 64 * 1024 * 1024
+
+// DefaultPipelineRegistry processes plain images up to SnapshotMaxSize as
+// snapshots and everything else as pyramids.
+func DefaultPipelineRegistry() *PipelineRegistry {
+	return NewPipelineRegistry(ProfilePyramid,
+		PipelineRule{
+			ContentTypes: []ContentType{ContentTypeImageJPEG, ContentTypeImagePNG, ContentTypeImageBMP, ContentTypeImageDNG},
+			MaxSize:      SnapshotMaxSize,
+			Profile:      ProfileSnapshot,
+		},
+	)
+}
+
+func (r *PipelineRegistry) Resolve(contentType ContentType, size int64) ProcessingProfile {
+	for _, rule := range r.rules {
+		if rule.matches(contentType, size) {
+			return rule.Profile
+		}
+	}
+	return r.fallback
+}
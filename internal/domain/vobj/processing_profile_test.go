@@ -0,0 +1,27 @@
+package vobj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineRegistry_Resolve(t *testing.T) {
+	registry := DefaultPipelineRegistry()
+
+	assert.Equal(t, ProfileSnapshot, registry.Resolve(ContentTypeImageJPEG, 2<<20))
+	assert.Equal(t, ProfileSnapshot, registry.Resolve(ContentTypeImagePNG, 0))
+	// Large plain images still need a pyramid
+	assert.Equal(t, ProfilePyramid, registry.Resolve(ContentTypeImagePNG, SnapshotMaxSize+1))
+	assert.Equal(t, ProfilePyramid, registry.Resolve(ContentTypeImageSVS, 1<<20))
+	assert.Equal(t, ProfilePyramid, registry.Resolve(ContentTypeImageMIRAX, 4<<30))
+}
+
+func TestProcessingProfile_RequiredOutputs(t *testing.T) {
+	assert.Equal(t, []ProcessingOutput{OutputThumbnail, OutputDZI, OutputTiles},
+		ProfileSnapshot.RequiredOutputs(ProcessingV2))
+	assert.Equal(t, []ProcessingOutput{OutputThumbnail, OutputDZI, OutputZipTiles, OutputIndexMap},
+		ProfilePyramid.RequiredOutputs(ProcessingV2))
+	// Images stored before profiles existed keep the version-based outputs
+	assert.Equal(t, ProfilePyramid.RequiredOutputs(ProcessingV1), ProcessingProfile("").RequiredOutputs(ProcessingV1))
+}
@@ -61,12 +61,14 @@ const (
 
 const (
 	StatusPending         ImageStatus = "pending"          // Initial state, waiting for processing
+	StatusQueued          ImageStatus = "queued"           // Waiting in the processing queue
 	StatusProcessing      ImageStatus = "processing"       // Currently being processed
 	StatusProcessed       ImageStatus = "processed"        // Successfully processed
 	StatusFailed          ImageStatus = "failed"           // Processing failed (retrying)
 	StatusFailedPermanent ImageStatus = "failed_permanent" // Permanent failure (DLQ)
 	StatusDeleting        ImageStatus = "deleting"         // Marked for deletion
 	StatusUploaded        ImageStatus = "uploaded"         // Successfully uploaded
+	StatusCancelled       ImageStatus = "cancelled"        // Processing cancelled by a user
 )
 
 const (
@@ -88,6 +90,13 @@ const (
 	ContentTypeThumbnailJPEG ContentType = "image/x-thumb-jpeg"
 	ContentTypeThumbnailPNG  ContentType = "image/x-thumb-png"
 
+	// Associated images of whole-slide files: the slide label and the
+	// overview (macro) photo of the whole slide
+	ContentTypeLabelJPEG ContentType = "image/x-label-jpeg"
+	ContentTypeLabelPNG  ContentType = "image/x-label-png"
+	ContentTypeMacroJPEG ContentType = "image/x-macro-jpeg"
+	ContentTypeMacroPNG  ContentType = "image/x-macro-png"
+
 	// Archive types
 	ContentTypeApplicationZip ContentType = "application/zip"
 
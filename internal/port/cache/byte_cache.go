@@ -0,0 +1,19 @@
+package cache
+
+import "context"
+
+// ByteCache holds payloads such as tiles within a byte budget, evicting the
+// least recently used entries. Entries are grouped by image so one image
+// cannot take the whole budget and all of its entries can be dropped at once.
+type ByteCache interface {
+	// Get returns the payload and whether it was cached
+	Get(ctx context.Context, imageID, key string) ([]byte, bool)
+
+	// Set stores a payload; payloads larger than the limits are not cached
+	Set(ctx context.Context, imageID, key string, data []byte)
+
+	// DeleteImage drops the image's entries and returns how many there were
+	DeleteImage(ctx context.Context, imageID string) int
+
+	GetStats(ctx context.Context) (*Stats, error)
+}
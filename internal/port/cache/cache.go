@@ -31,6 +31,7 @@ type Stats struct {
 	Misses    int64 // Number of cache misses
 	Size      int64 // Current number of items in cache
 	Evictions int64 // Number of evicted items (optional)
+	Bytes     int64 // Payload bytes held (byte caches only)
 }
 
 type CacheEntry struct {
@@ -89,3 +90,9 @@ func NewCacheError(message string) *CacheError {
 func (e *CacheError) Error() string {
 	return e.message
 }
+
+// ImageInvalidator drops everything cached for an image, such as its
+// descriptor, tiles and index map.
+type ImageInvalidator interface {
+	InvalidateImage(ctx context.Context, imageID string) error
+}
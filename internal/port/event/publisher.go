@@ -13,3 +13,15 @@ type EventPublisher interface {
 type TopicResolver interface {
 	ResolveTopic(eventType domainevent.EventType) string
 }
+
+// EventMarshaler renders an event as a self-describing JSON document for
+// transports other than the message broker (e.g. webhooks).
+type EventMarshaler interface {
+	Marshal(event domainevent.Event) ([]byte, error)
+}
+
+// EventSerializer converts events to and from their wire DTO.
+type EventSerializer interface {
+	Serialize(event domainevent.Event) ([]byte, error)
+	Deserialize(data []byte, eventType domainevent.EventType) (domainevent.Event, error)
+}
@@ -0,0 +1,16 @@
+package event
+
+import "context"
+
+type replayKey struct{}
+
+// WithReplay marks ctx as an operator-initiated replay. Handlers skip their
+// stale/duplicate-event checks for replayed events.
+func WithReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayKey{}, true)
+}
+
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
@@ -2,7 +2,9 @@ package port
 
 import (
 	"context"
+	"time"
 
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/shared/query"
 )
@@ -43,8 +45,71 @@ type AnnotationQuery interface {
 type ImageQuery interface {
 	Queries[*model.Image]
 	HierarchicalQueries[*model.Image]
+	// QueuePosition returns the 1-based position of a queued image in the
+	// processing queue, or a not found error if it has no queue entry
+	QueuePosition(ctx context.Context, imageID string) (int, error)
 }
 type ContentQuery interface {
 	Queries[*model.Content]
 	HierarchicalQueries[*model.Content]
 }
+
+type WebhookQuery interface {
+	// Reads need the requester to be an admin or a member of the workspace
+	Get(ctx context.Context, id, requesterID string, requesterIsAdmin bool) (*model.WebhookSubscription, error)
+	ListByWorkspace(ctx context.Context, wsID, requesterID string, requesterIsAdmin bool) ([]*model.WebhookSubscription, error)
+	ListDeliveries(ctx context.Context, subscriptionID, requesterID string, requesterIsAdmin bool, limit int) ([]*model.WebhookDelivery, error)
+}
+
+// EventStream delivers events until its channel is closed; Err reports why
+// it closed, or nil if the context ended.
+type EventStream interface {
+	Events() <-chan domainevent.Event
+	Err() error
+}
+
+type EventStreamQuery interface {
+	Stream(ctx context.Context, wsID string, lastEventID string) (EventStream, error)
+}
+
+type EventLogQuery interface {
+	List(ctx context.Context, filter EventLogFilter) ([]*model.EventLogEntry, error)
+}
+
+// DurationStats summarizes the durations of the attempts that have one.
+type DurationStats struct {
+	Count int
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+type ProcessingStatsGroup struct {
+	// Workspace ID or format; empty for the overall group
+	Key         string
+	Attempts    int
+	Succeeded   int
+	Failed      int
+	Cancelled   int
+	Running     int
+	QueueWait   DurationStats
+	Duration    DurationStats
+	OutputBytes int64
+}
+
+type ProcessingStats struct {
+	From time.Time
+	To   time.Time
+	// The window held more attempts than were aggregated; the oldest are left out
+	Truncated   bool
+	Total       ProcessingStatsGroup
+	ByWorkspace []ProcessingStatsGroup
+	ByFormat    []ProcessingStatsGroup
+}
+
+type ProcessingQuery interface {
+	// History returns the image's processing attempts, newest first
+	History(ctx context.Context, imageID string, limit int) ([]*model.ProcessingJob, error)
+	Stats(ctx context.Context, filter ProcessingJobFilter) (*ProcessingStats, error)
+}
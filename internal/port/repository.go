@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	domainevent "github.com/histopathai/main-service/internal/domain/event"
 	"github.com/histopathai/main-service/internal/domain/model"
 	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/shared/query"
@@ -51,6 +52,11 @@ type UnitOfWorkFactory interface {
 	GetAnnotationRepo() AnnotationRepository
 	GetAnnotationTypeRepo() AnnotationTypeRepository
 	GetContentRepo() ContentRepository
+	GetOutboxRepo() OutboxRepository
+	GetWebhookRepo() WebhookRepository
+	GetWebhookDeliveryRepo() WebhookDeliveryRepository
+	GetProcessingJobRepo() ProcessingJobRepository
+	GetProcessingQueueRepo() ProcessingQueueRepository
 }
 
 type WorkspaceRepository interface {
@@ -76,3 +82,98 @@ type AnnotationTypeRepository interface {
 type ContentRepository interface {
 	Repository[*model.Content]
 }
+
+// PendingEvent is an outbox event waiting to be relayed.
+type PendingEvent struct {
+	Event domainevent.Event
+	// Failed relay attempts so far
+	Attempts int
+}
+
+// OutboxRepository stores events in the same transaction as the state change
+// that produced them, so they can be published reliably afterwards.
+type OutboxRepository interface {
+	Add(ctx context.Context, event domainevent.Event) error
+	// FetchPending returns pending events due at now, earliest due first
+	FetchPending(ctx context.Context, now time.Time, limit int) ([]PendingEvent, error)
+	MarkPublished(ctx context.Context, eventID string) error
+	// RecordFailure stores a failed relay attempt and when to try again
+	RecordFailure(ctx context.Context, eventID string, attempts int, cause string, nextAttemptAt time.Time) error
+	// MarkDeadLettered stops relaying an event that keeps failing
+	MarkDeadLettered(ctx context.Context, eventID string, attempts int, cause string) error
+	Get(ctx context.Context, eventID string) (domainevent.Event, error)
+	// ListByWorkspace returns events of a workspace ordered by timestamp, then
+	// event ID, starting after the (after, afterID) position. An empty afterID
+	// starts at the first event with timestamp >= after.
+	ListByWorkspace(ctx context.Context, wsID string, after time.Time, afterID string, limit int) ([]domainevent.Event, error)
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	Read(ctx context.Context, id string) (*model.WebhookSubscription, error)
+	Update(ctx context.Context, subscription *model.WebhookSubscription) error
+	Delete(ctx context.Context, id string) error
+	ListByWorkspace(ctx context.Context, wsID string, activeOnly bool) ([]*model.WebhookSubscription, error)
+}
+
+// WebhookDeliveryRepository stores delivery attempts; it is also the delivery log.
+type WebhookDeliveryRepository interface {
+	// Save creates or overwrites a delivery by ID
+	Save(ctx context.Context, delivery *model.WebhookDelivery) error
+	Read(ctx context.Context, id string) (*model.WebhookDelivery, error)
+	FetchDue(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error)
+	ListBySubscription(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error)
+}
+
+type EventLogFilter struct {
+	EventTypes []string
+	ImageID    string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// EventLogRepository persists every published and consumed event.
+type EventLogRepository interface {
+	Append(ctx context.Context, entry *model.EventLogEntry) error
+	// Find returns matching entries, oldest first
+	Find(ctx context.Context, filter EventLogFilter) ([]*model.EventLogEntry, error)
+}
+
+type ProcessingJobFilter struct {
+	WsID   string
+	Format string
+	// Bounds on the start time; From is inclusive, To exclusive
+	From  *time.Time
+	To    *time.Time
+	Limit int
+}
+
+type ProcessingJobRepository interface {
+	Create(ctx context.Context, job *model.ProcessingJob) error
+	Update(ctx context.Context, job *model.ProcessingJob) error
+	// ListRunning returns running jobs, oldest first
+	ListRunning(ctx context.Context, limit int) ([]*model.ProcessingJob, error)
+	// ListByImage returns the image's jobs, newest first
+	ListByImage(ctx context.Context, imageID string, limit int) ([]*model.ProcessingJob, error)
+	CountRunning(ctx context.Context) (int, error)
+	// Find returns matching jobs, newest first
+	Find(ctx context.Context, filter ProcessingJobFilter) ([]*model.ProcessingJob, error)
+}
+
+// ProcessingQueueRepository stores images waiting for a worker, keyed by
+// image ID.
+type ProcessingQueueRepository interface {
+	// Save creates or replaces the image's entry
+	Save(ctx context.Context, entry *model.ProcessingQueueEntry) error
+	Read(ctx context.Context, imageID string) (*model.ProcessingQueueEntry, error)
+	Delete(ctx context.Context, imageID string) error
+	// ListNext returns entries in the order they are served
+	ListNext(ctx context.Context, limit int) ([]*model.ProcessingQueueEntry, error)
+	// RoundRange returns the lowest round queued at priority and the highest
+	// round queued by the workspace at priority; ok is false for an empty
+	// priority and wsLast is -1 if the workspace has nothing queued there
+	RoundRange(ctx context.Context, priority vobj.ProcessingPriority, wsID string) (first int64, wsLast int64, ok bool, err error)
+	// CountAhead returns how many entries are served before entry
+	CountAhead(ctx context.Context, entry *model.ProcessingQueueEntry) (int, error)
+}
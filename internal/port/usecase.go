@@ -2,9 +2,11 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/histopathai/main-service/internal/application/command"
 	"github.com/histopathai/main-service/internal/domain/model"
+	"github.com/histopathai/main-service/internal/domain/vobj"
 )
 
 type WorkspaceUseCase interface {
@@ -35,3 +37,64 @@ type ImageUseCase interface {
 	Transfer(ctx context.Context, cmd command.TransferCommand) error
 	TransferMany(ctx context.Context, cmd command.TransferManyCommand) error
 }
+
+// ExecutionCancellation reports on one worker execution stopped by a
+// reprocess or cancel request.
+type ExecutionCancellation struct {
+	Backend   string
+	Name      string
+	Cancelled bool
+	Error     string
+}
+
+type ProcessingActionResult struct {
+	ImageID       string
+	Status        vobj.ImageStatus
+	ActiveEventID string
+	// Derived contents detached from the image
+	RemovedContentIDs []string
+	Executions        []ExecutionCancellation
+}
+
+type ImageProcessingUseCase interface {
+	Reprocess(ctx context.Context, cmd command.ReprocessImageCommand) (*ProcessingActionResult, error)
+	CancelProcessing(ctx context.Context, cmd command.CancelProcessingCommand) (*ProcessingActionResult, error)
+}
+
+type WebhookUseCase interface {
+	Create(ctx context.Context, cmd command.CreateWebhookCommand) (*model.WebhookSubscription, error)
+	Update(ctx context.Context, cmd command.UpdateWebhookCommand) error
+	Delete(ctx context.Context, cmd command.DeleteWebhookCommand) error
+	Redeliver(ctx context.Context, cmd command.RedeliverWebhookCommand) (*model.WebhookDelivery, error)
+}
+
+type ReplayStatus string
+
+const (
+	ReplayWouldReplay ReplayStatus = "would_replay"
+	ReplayReplayed    ReplayStatus = "replayed"
+	ReplayNoHandler   ReplayStatus = "skipped_no_handler"
+	ReplayFailed      ReplayStatus = "failed"
+)
+
+type ReplayItem struct {
+	EventID    string
+	EventType  string
+	ImageID    string
+	OccurredAt time.Time
+	Status     ReplayStatus
+	Error      string
+}
+
+type ReplayResult struct {
+	DryRun   bool
+	Matched  int
+	Replayed int
+	Failed   int
+	Skipped  int
+	Items    []ReplayItem
+}
+
+type EventReplayUseCase interface {
+	Replay(ctx context.Context, cmd command.ReplayEventsCommand) (*ReplayResult, error)
+}
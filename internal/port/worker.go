@@ -8,5 +8,51 @@ import (
 )
 
 type ImageProcessingWorker interface {
-	ProcessImage(ctx context.Context, content model.Content, processingVersion vobj.ProcessingVersion) error
+	// ProcessImage starts an execution producing the outputs of profile and
+	// returns without waiting for it
+	ProcessImage(ctx context.Context, content model.Content, processingVersion vobj.ProcessingVersion, profile vobj.ProcessingProfile) (*WorkerExecution, error)
+	// GetExecutionStatus reports how a started execution is doing
+	GetExecutionStatus(ctx context.Context, executionName string) (*ExecutionStatus, error)
+}
+
+// ExecutionCanceller is implemented by workers whose backend can stop a
+// started execution. Cancelling an execution that already finished or no
+// longer exists is not an error.
+type ExecutionCanceller interface {
+	CancelExecution(ctx context.Context, executionName string) error
+}
+
+// WorkerExecution identifies a started execution in its backend.
+type WorkerExecution struct {
+	Backend string // "cloudrun", "kubernetes" or "local"
+	Name    string // Backend-specific, e.g. the Cloud Run execution resource name
+	Tier    string
+}
+
+type ExecutionState string
+
+const (
+	ExecutionRunning   ExecutionState = "running"
+	ExecutionSucceeded ExecutionState = "succeeded"
+	ExecutionFailed    ExecutionState = "failed"
+	// The backend no longer knows the execution (deleted, expired, or lost on restart)
+	ExecutionNotFound ExecutionState = "not_found"
+)
+
+type ExecutionStatus struct {
+	State  ExecutionState
+	Reason string
+}
+
+type processingEventIDKey struct{}
+
+// WithProcessingEventID attaches the ImageProcessReqEvent being served, so
+// workers can tag their executions with it.
+func WithProcessingEventID(ctx context.Context, eventID string) context.Context {
+	return context.WithValue(ctx, processingEventIDKey{}, eventID)
+}
+
+func ProcessingEventIDFrom(ctx context.Context) string {
+	eventID, _ := ctx.Value(processingEventIDKey{}).(string)
+	return eventID
 }
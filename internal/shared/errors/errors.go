@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderr "errors"
 	"fmt"
 )
 
@@ -88,3 +89,13 @@ func NewBadRequestError(message string, details map[string]interface{}) *Err {
 		Details: details,
 	}
 }
+
+// IsType reports whether err wraps an *Err of the given type.
+func IsType(err error, t ErrorType) bool {
+	var e *Err
+	return stderr.As(err, &e) && e.Type == t
+}
+
+func IsNotFound(err error) bool {
+	return IsType(err, ErrorTypeNotFound)
+}
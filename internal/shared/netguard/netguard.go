@@ -0,0 +1,74 @@
+// Package netguard keeps outbound requests to user supplied URLs, such as
+// webhooks, away from internal networks and cloud metadata endpoints.
+package netguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// Ranges that are not covered by the netip predicates but are still not
+// reachable on the public internet
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT, also used for metadata services
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 can reach any IPv4 address
+}
+
+// IsPublic reports whether addr is a globally routable unicast address.
+// Loopback, private, link-local (including 169.254.169.254) and
+// unspecified addresses are not.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves host and fails unless every address it resolves to is public.
+func CheckHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublic(addr) {
+			return fmt.Errorf("address %s is not public", addr)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr) {
+			return fmt.Errorf("%s resolves to non-public address %s", host, addr.Unmap())
+		}
+	}
+	return nil
+}
+
+// DialControl is a net.Dialer Control function that refuses connections to
+// non-public addresses. It runs after name resolution, on the address
+// actually dialed, so a host re-pointed at an internal address after
+// validation (DNS rebinding) is still refused.
+func DialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("unexpected dial address %q: %w", address, err)
+	}
+	if !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("refusing to connect to non-public address %s", addrPort.Addr().Unmap())
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package netguard
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublic(t *testing.T) {
+	for _, addr := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"100.100.100.200", "0.0.0.0", "::1", "fe80::1", "fd00:ec2::254", "::ffff:127.0.0.1",
+		"224.0.0.1",
+	} {
+		assert.False(t, IsPublic(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "2001:4860:4860::8888", "::ffff:8.8.8.8"} {
+		assert.True(t, IsPublic(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	assert.Error(t, CheckHost(context.Background(), "169.254.169.254"))
+	assert.Error(t, CheckHost(context.Background(), "localhost"))
+	assert.NoError(t, CheckHost(context.Background(), "8.8.8.8"))
+}
+
+func TestDialControl(t *testing.T) {
+	assert.Error(t, DialControl("tcp", "127.0.0.1:443", nil))
+	assert.Error(t, DialControl("tcp", "[::1]:443", nil))
+	assert.NoError(t, DialControl("tcp", "93.184.216.34:443", nil))
+}
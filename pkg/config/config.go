@@ -3,11 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"time"
 )
 
 type Environment string
 
+var hexColorPattern = regexp.MustCompile(`^#?([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
 const (
 	EnvLocal      Environment = "LOCAL"
 	EnvDev        Environment = "DEV"
@@ -42,6 +46,16 @@ type PubSubConfig struct {
 	ImageProcessingResult  TopicSubscriptionConfig
 	ImageDeletion          TopicSubscriptionConfig
 	UploadStatus           SubscriptionConfig
+	EntityEvents           EntityEventTopicsConfig
+}
+
+// EntityEventTopicsConfig holds one lifecycle event topic per entity type
+type EntityEventTopicsConfig struct {
+	Workspace      TopicConfig
+	Patient        TopicConfig
+	Image          TopicConfig
+	Annotation     TopicConfig
+	AnnotationType TopicConfig
 }
 
 // TopicSubscriptionConfig bundles topic and subscription together
@@ -71,30 +85,148 @@ type LoggingConfig struct {
 	Format string
 }
 
+// EventsConfig controls how domain events are encoded on the wire
+type EventsConfig struct {
+	Encoding          string // "legacy", "structured" or "binary"
+	Source            string // CloudEvents source attribute
+	DataSchemaBaseURL string // Base URL used to build the CloudEvents dataschema attribute
+}
+
+// OutboxConfig controls the relay that publishes events stored in the outbox
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// WebhookConfig controls the worker that delivers events to webhook subscriptions
+type WebhookConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+}
+
+// JobTrackerConfig controls the poller that follows worker executions
+type JobTrackerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// How long an execution may be unknown to its backend before the image is failed
+	NotFoundGrace time.Duration
+}
+
+// WatchdogConfig controls the sweeper that retries failed and stuck images.
+// The retry budget and backoff come from Retry.ImageProcess.
+type WatchdogConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// How long an image may stay processing before it counts as failed
+	ProcessingDeadline time.Duration
+}
+
+// QueueConfig controls the scheduler that starts queued images on the worker
+type QueueConfig struct {
+	PollInterval time.Duration
+	// Executions running at once across all workspaces; a soft limit, as
+	// instances count running executions without coordinating
+	Concurrency int
+}
+
+// TileConfig controls tile and region serving
+type TileConfig struct {
+	// Largest region rendering, in output pixels
+	MaxRegionPixels int
+	// Tile payload cache in memory; 0 disables tile caching
+	CacheMaxBytes int
+	// Share of either tier a single image may take
+	CacheMaxImageBytes int
+	// Optional second tier on local disk; empty disables it
+	CacheDiskDir      string
+	CacheDiskMaxBytes int
+	// #rrggbb[aa] of tiles served outside the pyramid; empty answers 404
+	BlankTileColor string
+	// How long a user's access to an image is reused before it is checked again
+	AccessCacheTTL time.Duration
+	// HMAC key of signed tile URLs; empty disables them
+	URLSigningKey string
+	SignedURLTTL  time.Duration
+	// Background loading of viewport neighbours and freshly processed images
+	PrefetchWorkers   int
+	PrefetchQueueSize int
+	// Tiles of the lowest pyramid levels loaded once an image is processed
+	WarmMaxTiles int
+	// How long an image's annotation polygons are reused for overlay tiles
+	// on instances that do not see the annotation change
+	OverlayCacheTTL time.Duration
+}
+
+// StreamConfig controls the server-sent events endpoint
+type StreamConfig struct {
+	PollInterval time.Duration
+	KeepAlive    time.Duration
+}
+
 // WorkerConfig contains worker configuration
 type WorkerConfig struct {
-	Type      string // "cloudrun" or "mock"
+	Type      string // "cloudrun", "local" or "kubernetes"
 	JobSmall  string // EKLENDİ
 	JobMedium string // EKLENDİ
 	JobLarge  string // EKLENDİ
+	Local     LocalWorkerConfig
+	K8s       KubernetesWorkerConfig
+}
+
+// KubernetesWorkerConfig configures the batch/v1 Job worker used when Type is "kubernetes"
+type KubernetesWorkerConfig struct {
+	Kubeconfig       string // Empty: in-cluster configuration
+	Namespace        string
+	JobTemplate      string // Path to a Job manifest; a single-container Job running Image when empty
+	Image            string
+	ActiveDeadline   time.Duration
+	TTLAfterFinished time.Duration
+	Small            KubernetesResourceTier
+	Medium           KubernetesResourceTier
+	Large            KubernetesResourceTier
+}
+
+// KubernetesResourceTier holds resource quantities, e.g. CPU "2" and Memory "8Gi"
+type KubernetesResourceTier struct {
+	CPU    string
+	Memory string
+}
+
+// LocalWorkerConfig configures the subprocess worker used when Type is "local"
+type LocalWorkerConfig struct {
+	Command     string // Tiling command; split on whitespace, run with the INPUT_* environment
+	Concurrency int
+	Timeout     time.Duration
+	LogDir      string // One log file per job
 }
 
 // Config is the main configuration struct
 type Config struct {
-	Env      Environment
-	Server   ServerConfig
-	GCP      GCPConfig
-	PubSub   PubSubConfig
-	Worker   WorkerConfig
-	Logging  LoggingConfig
-	Retry    RetryConfig
-	LocalTLS LocalTLSConfig
+	Env        Environment
+	Server     ServerConfig
+	GCP        GCPConfig
+	PubSub     PubSubConfig
+	Events     EventsConfig
+	Outbox     OutboxConfig
+	Webhook    WebhookConfig
+	Stream     StreamConfig
+	Worker     WorkerConfig
+	JobTracker JobTrackerConfig
+	Watchdog   WatchdogConfig
+	Queue      QueueConfig
+	Tile       TileConfig
+	Logging    LoggingConfig
+	Retry      RetryConfig
+	LocalTLS   LocalTLSConfig
 }
 
 // RetryConfig defines retry configuration per event type
 type RetryConfig struct {
 	ImageProcessComplete RetryPolicyConfig
 	ImageProcess         RetryPolicyConfig
+	WebhookDelivery      RetryPolicyConfig
+	Outbox               RetryPolicyConfig
 }
 
 // RetryPolicyConfig defines retry behavior for a specific event type
@@ -123,6 +255,80 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid IDLE_TIMEOUT: %w", err)
 	}
 
+	outboxPollInterval, err := time.ParseDuration(getEnv("OUTBOX_POLL_INTERVAL", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OUTBOX_POLL_INTERVAL: %w", err)
+	}
+
+	webhookPollInterval, err := time.ParseDuration(getEnv("WEBHOOK_POLL_INTERVAL", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_POLL_INTERVAL: %w", err)
+	}
+	webhookTimeout, err := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %w", err)
+	}
+
+	streamPollInterval, err := time.ParseDuration(getEnv("SSE_POLL_INTERVAL", "1s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSE_POLL_INTERVAL: %w", err)
+	}
+	streamKeepAlive, err := time.ParseDuration(getEnv("SSE_KEEPALIVE_INTERVAL", "15s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSE_KEEPALIVE_INTERVAL: %w", err)
+	}
+
+	jobTrackerPollInterval, err := time.ParseDuration(getEnv("JOB_TRACKER_POLL_INTERVAL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_TRACKER_POLL_INTERVAL: %w", err)
+	}
+	jobTrackerNotFoundGrace, err := time.ParseDuration(getEnv("JOB_TRACKER_NOT_FOUND_GRACE", "2m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_TRACKER_NOT_FOUND_GRACE: %w", err)
+	}
+
+	watchdogPollInterval, err := time.ParseDuration(getEnv("PROCESSING_WATCHDOG_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROCESSING_WATCHDOG_INTERVAL: %w", err)
+	}
+	processingDeadline, err := time.ParseDuration(getEnv("PROCESSING_DEADLINE", "3h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROCESSING_DEADLINE: %w", err)
+	}
+
+	queuePollInterval, err := time.ParseDuration(getEnv("PROCESSING_QUEUE_POLL_INTERVAL", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROCESSING_QUEUE_POLL_INTERVAL: %w", err)
+	}
+
+	localWorkerTimeout, err := time.ParseDuration(getEnv("LOCAL_WORKER_TIMEOUT", "2h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOCAL_WORKER_TIMEOUT: %w", err)
+	}
+
+	k8sActiveDeadline, err := time.ParseDuration(getEnv("K8S_JOB_ACTIVE_DEADLINE", "2h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid K8S_JOB_ACTIVE_DEADLINE: %w", err)
+	}
+	k8sTTL, err := time.ParseDuration(getEnv("K8S_JOB_TTL_AFTER_FINISHED", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid K8S_JOB_TTL_AFTER_FINISHED: %w", err)
+	}
+
+	tileAccessCacheTTL, err := time.ParseDuration(getEnv("TILE_ACCESS_CACHE_TTL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TILE_ACCESS_CACHE_TTL: %w", err)
+	}
+	tileSignedURLTTL, err := time.ParseDuration(getEnv("TILE_SIGNED_URL_TTL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TILE_SIGNED_URL_TTL: %w", err)
+	}
+
+	tileOverlayCacheTTL, err := time.ParseDuration(getEnv("TILE_OVERLAY_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TILE_OVERLAY_CACHE_TTL: %w", err)
+	}
+
 	cfg := &Config{
 		Env: Environment(env),
 		Server: ServerConfig{
@@ -180,6 +386,60 @@ func Load() (*Config, error) {
 					DLQName: getEnv("IMAGE_DELETION_SUB_DLQ", "image-deletion-requests-sub-dlq"),
 				},
 			},
+			EntityEvents: EntityEventTopicsConfig{
+				Workspace:      TopicConfig{Name: getEnv("WORKSPACE_EVENTS_TOPIC", "workspace-events")},
+				Patient:        TopicConfig{Name: getEnv("PATIENT_EVENTS_TOPIC", "patient-events")},
+				Image:          TopicConfig{Name: getEnv("IMAGE_EVENTS_TOPIC", "image-events")},
+				Annotation:     TopicConfig{Name: getEnv("ANNOTATION_EVENTS_TOPIC", "annotation-events")},
+				AnnotationType: TopicConfig{Name: getEnv("ANNOTATION_TYPE_EVENTS_TOPIC", "annotation-type-events")},
+			},
+		},
+		Events: EventsConfig{
+			Encoding:          getEnv("EVENT_ENCODING", "legacy"),
+			Source:            getEnv("EVENT_SOURCE", "//histopathai.com/main-service"),
+			DataSchemaBaseURL: getEnv("EVENT_DATASCHEMA_BASE_URL", "https://schemas.histopathai.com/events"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: outboxPollInterval,
+			BatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		},
+		Webhook: WebhookConfig{
+			PollInterval: webhookPollInterval,
+			BatchSize:    getEnvInt("WEBHOOK_BATCH_SIZE", 50),
+			Timeout:      webhookTimeout,
+		},
+		Stream: StreamConfig{
+			PollInterval: streamPollInterval,
+			KeepAlive:    streamKeepAlive,
+		},
+		JobTracker: JobTrackerConfig{
+			PollInterval:  jobTrackerPollInterval,
+			BatchSize:     getEnvInt("JOB_TRACKER_BATCH_SIZE", 50),
+			NotFoundGrace: jobTrackerNotFoundGrace,
+		},
+		Watchdog: WatchdogConfig{
+			PollInterval:       watchdogPollInterval,
+			BatchSize:          getEnvInt("PROCESSING_WATCHDOG_BATCH_SIZE", 50),
+			ProcessingDeadline: processingDeadline,
+		},
+		Queue: QueueConfig{
+			PollInterval: queuePollInterval,
+			Concurrency:  getEnvInt("PROCESSING_QUEUE_CONCURRENCY", 10),
+		},
+		Tile: TileConfig{
+			MaxRegionPixels:    getEnvInt("TILE_REGION_MAX_PIXELS", 4096*4096),
+			CacheMaxBytes:      getEnvInt("TILE_CACHE_MAX_BYTES", 256<<20),
+			CacheMaxImageBytes: getEnvInt("TILE_CACHE_MAX_IMAGE_BYTES", 64<<20),
+			CacheDiskDir:       getEnv("TILE_CACHE_DISK_DIR", ""),
+			CacheDiskMaxBytes:  getEnvInt("TILE_CACHE_DISK_MAX_BYTES", 4<<30),
+			BlankTileColor:     getEnv("TILE_BLANK_COLOR", "#ffffff"),
+			AccessCacheTTL:     tileAccessCacheTTL,
+			URLSigningKey:      getEnv("TILE_URL_SIGNING_KEY", ""),
+			SignedURLTTL:       tileSignedURLTTL,
+			PrefetchWorkers:    getEnvInt("TILE_PREFETCH_WORKERS", 4),
+			PrefetchQueueSize:  getEnvInt("TILE_PREFETCH_QUEUE_SIZE", 256),
+			WarmMaxTiles:       getEnvInt("TILE_WARM_MAX_TILES", 64),
+			OverlayCacheTTL:    tileOverlayCacheTTL,
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
@@ -191,6 +451,32 @@ func Load() (*Config, error) {
 			JobSmall:  getEnv("CLOUD_RUN_JOB_SMALL", ""),  // EKLENDİ
 			JobMedium: getEnv("CLOUD_RUN_JOB_MEDIUM", ""), // EKLENDİ
 			JobLarge:  getEnv("CLOUD_RUN_JOB_LARGE", ""),  // EKLENDİ
+			Local: LocalWorkerConfig{
+				Command:     getEnv("LOCAL_WORKER_COMMAND", ""),
+				Concurrency: getEnvInt("LOCAL_WORKER_CONCURRENCY", 2),
+				Timeout:     localWorkerTimeout,
+				LogDir:      getEnv("LOCAL_WORKER_LOG_DIR", filepath.Join(os.TempDir(), "histopath-worker-logs")),
+			},
+			K8s: KubernetesWorkerConfig{
+				Kubeconfig:       getEnv("K8S_KUBECONFIG", ""),
+				Namespace:        getEnv("K8S_NAMESPACE", "default"),
+				JobTemplate:      getEnv("K8S_JOB_TEMPLATE", ""),
+				Image:            getEnv("K8S_WORKER_IMAGE", ""),
+				ActiveDeadline:   k8sActiveDeadline,
+				TTLAfterFinished: k8sTTL,
+				Small: KubernetesResourceTier{
+					CPU:    getEnv("K8S_SMALL_CPU", "1"),
+					Memory: getEnv("K8S_SMALL_MEMORY", "2Gi"),
+				},
+				Medium: KubernetesResourceTier{
+					CPU:    getEnv("K8S_MEDIUM_CPU", "2"),
+					Memory: getEnv("K8S_MEDIUM_MEMORY", "8Gi"),
+				},
+				Large: KubernetesResourceTier{
+					CPU:    getEnv("K8S_LARGE_CPU", "4"),
+					Memory: getEnv("K8S_LARGE_MEMORY", "32Gi"),
+				},
+			},
 		},
 		Retry: RetryConfig{
 			ImageProcessComplete: RetryPolicyConfig{
@@ -205,6 +491,18 @@ func Load() (*Config, error) {
 				MaxBackoffMs:      getEnvInt("RETRY_IMAGE_PROCESS_MAX_BACKOFF_MS", 30000),
 				BackoffMultiplier: 2.0,
 			},
+			WebhookDelivery: RetryPolicyConfig{
+				MaxAttempts:       getEnvInt("RETRY_WEBHOOK_MAX_ATTEMPTS", 8),
+				BaseBackoffMs:     getEnvInt("RETRY_WEBHOOK_BASE_BACKOFF_MS", 10000),
+				MaxBackoffMs:      getEnvInt("RETRY_WEBHOOK_MAX_BACKOFF_MS", 3600000),
+				BackoffMultiplier: 2.0,
+			},
+			Outbox: RetryPolicyConfig{
+				MaxAttempts:       getEnvInt("RETRY_OUTBOX_MAX_ATTEMPTS", 10),
+				BaseBackoffMs:     getEnvInt("RETRY_OUTBOX_BASE_BACKOFF_MS", 2000),
+				MaxBackoffMs:      getEnvInt("RETRY_OUTBOX_MAX_BACKOFF_MS", 600000),
+				BackoffMultiplier: 2.0,
+			},
 		},
 
 		LocalTLS: LocalTLSConfig{
@@ -260,10 +558,132 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("IMAGE_DELETION_SUB is required")
 	}
 
+	if c.Outbox.PollInterval <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL must be positive")
+	}
+	if c.Outbox.BatchSize <= 0 {
+		return fmt.Errorf("OUTBOX_BATCH_SIZE must be positive")
+	}
+
+	if c.Webhook.PollInterval <= 0 {
+		return fmt.Errorf("WEBHOOK_POLL_INTERVAL must be positive")
+	}
+	if c.Webhook.BatchSize <= 0 {
+		return fmt.Errorf("WEBHOOK_BATCH_SIZE must be positive")
+	}
+	if c.Webhook.Timeout <= 0 {
+		return fmt.Errorf("WEBHOOK_TIMEOUT must be positive")
+	}
+	if c.JobTracker.PollInterval <= 0 {
+		return fmt.Errorf("JOB_TRACKER_POLL_INTERVAL must be positive")
+	}
+	if c.JobTracker.BatchSize <= 0 {
+		return fmt.Errorf("JOB_TRACKER_BATCH_SIZE must be positive")
+	}
+	if c.JobTracker.NotFoundGrace < 0 {
+		return fmt.Errorf("JOB_TRACKER_NOT_FOUND_GRACE must not be negative")
+	}
+	if c.Watchdog.PollInterval <= 0 {
+		return fmt.Errorf("PROCESSING_WATCHDOG_INTERVAL must be positive")
+	}
+	if c.Watchdog.BatchSize <= 0 {
+		return fmt.Errorf("PROCESSING_WATCHDOG_BATCH_SIZE must be positive")
+	}
+	if c.Watchdog.ProcessingDeadline <= 0 {
+		return fmt.Errorf("PROCESSING_DEADLINE must be positive")
+	}
+	if c.Queue.PollInterval <= 0 {
+		return fmt.Errorf("PROCESSING_QUEUE_POLL_INTERVAL must be positive")
+	}
+	if c.Queue.Concurrency <= 0 {
+		return fmt.Errorf("PROCESSING_QUEUE_CONCURRENCY must be positive")
+	}
+	if c.Tile.MaxRegionPixels <= 0 {
+		return fmt.Errorf("TILE_REGION_MAX_PIXELS must be positive")
+	}
+	if c.Tile.CacheMaxBytes < 0 || c.Tile.CacheMaxImageBytes < 0 {
+		return fmt.Errorf("TILE_CACHE_MAX_BYTES and TILE_CACHE_MAX_IMAGE_BYTES must not be negative")
+	}
+	if c.Tile.CacheDiskDir != "" && c.Tile.CacheMaxBytes == 0 {
+		return fmt.Errorf("TILE_CACHE_DISK_DIR requires TILE_CACHE_MAX_BYTES")
+	}
+	if c.Tile.CacheDiskDir != "" && c.Tile.CacheDiskMaxBytes <= 0 {
+		return fmt.Errorf("TILE_CACHE_DISK_MAX_BYTES must be positive")
+	}
+	if c.Tile.BlankTileColor != "" && !hexColorPattern.MatchString(c.Tile.BlankTileColor) {
+		return fmt.Errorf("TILE_BLANK_COLOR must be #rrggbb or #rrggbbaa")
+	}
+	if c.Tile.AccessCacheTTL < 0 {
+		return fmt.Errorf("TILE_ACCESS_CACHE_TTL must not be negative")
+	}
+	if c.Tile.URLSigningKey != "" && len(c.Tile.URLSigningKey) < 32 {
+		return fmt.Errorf("TILE_URL_SIGNING_KEY must be at least 32 characters")
+	}
+	if c.Tile.SignedURLTTL <= 0 {
+		return fmt.Errorf("TILE_SIGNED_URL_TTL must be positive")
+	}
+	if c.Tile.PrefetchWorkers < 1 {
+		return fmt.Errorf("TILE_PREFETCH_WORKERS must be at least 1")
+	}
+	if c.Tile.PrefetchQueueSize < 1 {
+		return fmt.Errorf("TILE_PREFETCH_QUEUE_SIZE must be at least 1")
+	}
+	if c.Tile.WarmMaxTiles < 0 {
+		return fmt.Errorf("TILE_WARM_MAX_TILES must not be negative")
+	}
+	if c.Tile.OverlayCacheTTL <= 0 {
+		return fmt.Errorf("TILE_OVERLAY_CACHE_TTL must be positive")
+	}
+	if c.Retry.ImageProcess.MaxAttempts < 0 {
+		return fmt.Errorf("RETRY_IMAGE_PROCESS_MAX_ATTEMPTS must not be negative")
+	}
+	if c.Retry.WebhookDelivery.MaxAttempts <= 0 {
+		return fmt.Errorf("RETRY_WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+	if c.Retry.Outbox.MaxAttempts <= 0 {
+		return fmt.Errorf("RETRY_OUTBOX_MAX_ATTEMPTS must be positive")
+	}
+
+	if c.Stream.PollInterval <= 0 {
+		return fmt.Errorf("SSE_POLL_INTERVAL must be positive")
+	}
+	if c.Stream.KeepAlive <= 0 {
+		return fmt.Errorf("SSE_KEEPALIVE_INTERVAL must be positive")
+	}
+
+	// Events Configuration
+	switch c.Events.Encoding {
+	case "legacy", "structured", "binary":
+	default:
+		return fmt.Errorf("EVENT_ENCODING must be one of legacy, structured, binary")
+	}
+
 	// Worker Configuration
 	if c.Worker.Type == "" {
 		return fmt.Errorf("WORKER_TYPE is required")
 	}
+	if c.Worker.Type == "local" {
+		if c.Worker.Local.Command == "" {
+			return fmt.Errorf("LOCAL_WORKER_COMMAND is required when WORKER_TYPE is local")
+		}
+		if c.Worker.Local.Concurrency <= 0 {
+			return fmt.Errorf("LOCAL_WORKER_CONCURRENCY must be positive")
+		}
+		if c.Worker.Local.Timeout <= 0 {
+			return fmt.Errorf("LOCAL_WORKER_TIMEOUT must be positive")
+		}
+	}
+	if c.Worker.Type == "kubernetes" {
+		if c.Worker.K8s.JobTemplate == "" && c.Worker.K8s.Image == "" {
+			return fmt.Errorf("K8S_JOB_TEMPLATE or K8S_WORKER_IMAGE is required when WORKER_TYPE is kubernetes")
+		}
+		if c.Worker.K8s.Namespace == "" {
+			return fmt.Errorf("K8S_NAMESPACE is required when WORKER_TYPE is kubernetes")
+		}
+		if c.Worker.K8s.ActiveDeadline <= 0 {
+			return fmt.Errorf("K8S_JOB_ACTIVE_DEADLINE must be positive")
+		}
+	}
 
 	return nil
 }
@@ -329,6 +749,13 @@ func (c *Config) applyDevPrefixes() {
 		c.PubSub.ImageDeletion.Subscription.DLQName = devPrefix + c.PubSub.ImageDeletion.Subscription.DLQName
 	}
 
+	// Apply prefix to Entity Event topics
+	c.PubSub.EntityEvents.Workspace.Name = devPrefix + c.PubSub.EntityEvents.Workspace.Name
+	c.PubSub.EntityEvents.Patient.Name = devPrefix + c.PubSub.EntityEvents.Patient.Name
+	c.PubSub.EntityEvents.Image.Name = devPrefix + c.PubSub.EntityEvents.Image.Name
+	c.PubSub.EntityEvents.Annotation.Name = devPrefix + c.PubSub.EntityEvents.Annotation.Name
+	c.PubSub.EntityEvents.AnnotationType.Name = devPrefix + c.PubSub.EntityEvents.AnnotationType.Name
+
 	// Apply suffix to Cloud Run Jobs
 	const devSuffix = "-dev"
 	if c.Worker.JobSmall != "" {
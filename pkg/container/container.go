@@ -3,6 +3,7 @@ package container
 import (
 	"context"
 	"fmt"
+	"image/color"
 	"log/slog"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	appquery "github.com/histopathai/main-service/internal/application/queries"
 	appusecase "github.com/histopathai/main-service/internal/application/usecase"
 	domainevent "github.com/histopathai/main-service/internal/domain/event"
+	"github.com/histopathai/main-service/internal/domain/vobj"
 	"github.com/histopathai/main-service/internal/port"
 	"github.com/histopathai/main-service/internal/port/cache"
 	portevent "github.com/histopathai/main-service/internal/port/event"
@@ -43,19 +45,30 @@ type Container struct {
 	ContentRepo        port.ContentRepository
 	AnnotationRepo     port.AnnotationRepository
 	AnnotationTypeRepo port.AnnotationTypeRepository
+	WebhookRepo        port.WebhookRepository
+	DeliveryRepo       port.WebhookDeliveryRepository
+	EventLogRepo       port.EventLogRepository
+	ProcessingJobRepo  port.ProcessingJobRepository
+	QueueRepo          port.ProcessingQueueRepository
 	UOW                port.UnitOfWorkFactory
 	TileServer         *proxy.TileServer
+	Prefetcher         *proxy.Prefetcher
+	OverlayRenderer    *proxy.OverlayRenderer
+	ImageAccess        *proxy.ImageAccess
 
 	// Storages
 	OriginStorage    port.Storage
 	ProcessedStorage port.Storage
 
 	// Use Cases
-	WorkspaceUseCase      port.WorkspaceUseCase
-	PatientUseCase        port.PatientUseCase
-	ImageUseCase          port.ImageUseCase
-	AnnotationUseCase     port.AnnotationUseCase
-	AnnotationTypeUseCase port.AnnotationTypeUseCase
+	WorkspaceUseCase       port.WorkspaceUseCase
+	PatientUseCase         port.PatientUseCase
+	ImageUseCase           port.ImageUseCase
+	AnnotationUseCase      port.AnnotationUseCase
+	AnnotationTypeUseCase  port.AnnotationTypeUseCase
+	WebhookUseCase         port.WebhookUseCase
+	EventReplayUseCase     port.EventReplayUseCase
+	ImageProcessingUseCase port.ImageProcessingUseCase
 
 	// Queries
 	WorkspaceQuery      port.WorkspaceQuery
@@ -64,31 +77,53 @@ type Container struct {
 	ContentQuery        port.ContentQuery
 	AnnotationQuery     port.AnnotationQuery
 	AnnotationTypeQuery port.AnnotationTypeQuery
+	WebhookQuery        port.WebhookQuery
+	EventStreamQuery    port.EventStreamQuery
+	EventLogQuery       port.EventLogQuery
+	ProcessingQuery     port.ProcessingQuery
 
 	// Event Infrastructure
 	EventPublisher     portevent.EventPublisher
 	UploadSubscriber   portevent.EventSubscriber
 	ProcessSubscriber  portevent.EventSubscriber
 	CompleteSubscriber portevent.EventSubscriber
+	EventRecorder      *apphandler.EventRecorder
 
 	// Event Handlers
 	NewFileHandler              *apphandler.NewFileHandler
 	ImageProcessHandler         *apphandler.ImageProcessHandler
 	ImageProcessCompleteHandler *apphandler.ImageProcessCompleteHandler
+	OutboxRelay                 *apphandler.OutboxRelay
+	WebhookDispatcher           *apphandler.WebhookDispatcher
+	TileCacheWarmer             *apphandler.TileCacheWarmer
+	OverlayInvalidator          *apphandler.OverlayInvalidator
+	WebhookDeliveryWorker       *apphandler.WebhookDeliveryWorker
+	ProcessingJobTracker        *apphandler.ProcessingJobTracker
+	ProcessingWatchdog          *apphandler.ProcessingWatchdog
+	ProcessingScheduler         *apphandler.ProcessingScheduler
 
 	// Worker
 	ImageProcessingWorker port.ImageProcessingWorker
+	ProcessingPipelines   *vobj.PipelineRegistry
 
 	// HTTP Layer
-	WorkspaceHandler      *handler.WorkspaceHandler
-	PatientHandler        *handler.PatientHandler
-	ImageHandler          *handler.ImageHandler
-	AnnotationHandler     *handler.AnnotationHandler
-	AnnotationTypeHandler *handler.AnnotationTypeHandler
-	AuthMiddleware        *middleware.AuthMiddleware
-	TimeoutMiddleware     *middleware.TimeoutMiddleware
-	TileProxyHandler      *handler.TileProxyHandler
-	Router                *router.Router
+	WorkspaceHandler       *handler.WorkspaceHandler
+	PatientHandler         *handler.PatientHandler
+	ImageHandler           *handler.ImageHandler
+	ImageProcessingHandler *handler.ImageProcessingHandler
+	AnnotationHandler      *handler.AnnotationHandler
+	AnnotationTypeHandler  *handler.AnnotationTypeHandler
+	AuthMiddleware         *middleware.AuthMiddleware
+	TimeoutMiddleware      *middleware.TimeoutMiddleware
+	ImageAccessMiddleware  *middleware.ImageAccessMiddleware
+	TileProxyHandler       *handler.TileProxyHandler
+	IIIFHandler            *handler.IIIFHandler
+	OverlayHandler         *handler.OverlayHandler
+	ImageRegionHandler     *handler.ImageRegionHandler
+	WebhookHandler         *handler.WebhookHandler
+	EventStreamHandler     *handler.EventStreamHandler
+	EventReplayHandler     *handler.EventReplayHandler
+	Router                 *router.Router
 }
 
 func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Container, error) {
@@ -125,6 +160,11 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Contain
 		return nil, fmt.Errorf("failed to initialize workers: %w", err)
 	}
 
+	// The outbox relay warms the tile server's caches
+	if err := c.initProxies(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize proxies: %w", err)
+	}
+
 	if err := c.initEventHandlers(ctx); err != nil {
 		return nil, fmt.Errorf("failed to initialize event handlers: %w", err)
 	}
@@ -133,10 +173,6 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Contain
 		return nil, fmt.Errorf("failed to initialize subscribers: %w", err)
 	}
 
-	if err := c.initProxies(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize proxies: %w", err)
-	}
-
 	if err := c.initHTTPLayer(ctx); err != nil {
 		return nil, fmt.Errorf("failed to initialize HTTP layer: %w", err)
 	}
@@ -186,6 +222,12 @@ func (c *Container) initRepositories(ctx context.Context) error {
 	c.ContentRepo = uowFactory.GetContentRepo()
 	c.AnnotationRepo = uowFactory.GetAnnotationRepo()
 	c.AnnotationTypeRepo = uowFactory.GetAnnotationTypeRepo()
+	c.WebhookRepo = uowFactory.GetWebhookRepo()
+	c.DeliveryRepo = uowFactory.GetWebhookDeliveryRepo()
+	c.ProcessingJobRepo = uowFactory.GetProcessingJobRepo()
+	c.QueueRepo = uowFactory.GetProcessingQueueRepo()
+	// Not transactional: events are logged outside of any unit of work
+	c.EventLogRepo = firestorerepo.NewEventLogRepositoryImpl(c.FirestoreClient, "event_log")
 	c.Logger.Info("Repositories initialized")
 	return nil
 }
@@ -205,17 +247,22 @@ func (c *Container) initUseCases(ctx context.Context) error {
 	c.ImageUseCase = appusecase.NewImageUseCase(c.ImageRepo, c.UOW, c.OriginStorage, c.ProcessedStorage)
 	c.AnnotationUseCase = appusecase.NewAnnotationUseCase(c.AnnotationRepo, c.UOW)
 	c.AnnotationTypeUseCase = appusecase.NewAnnotationTypeUseCase(c.AnnotationTypeRepo, c.UOW)
+	c.WebhookUseCase = appusecase.NewWebhookUseCase(c.UOW)
 	c.Logger.Info("Use cases initialized")
 	return nil
 }
 
 func (c *Container) initQueries(ctx context.Context) error {
-	c.WorkspaceQuery = appquery.NewWorkspaceQuery(c.WorkspaceRepo)
-	c.PatientQuery = appquery.NewPatientQuery(c.PatientRepo)
-	c.ImageQuery = appquery.NewImageQuery(c.ImageRepo)
+	c.WorkspaceQuery = appquery.NewWorkspaceQuery(c.WorkspaceRepo, c.UOW)
+	c.PatientQuery = appquery.NewPatientQuery(c.PatientRepo, c.UOW)
+	c.ImageQuery = appquery.NewImageQuery(c.ImageRepo, c.UOW)
 	c.ContentQuery = appquery.NewContentQuery(c.ContentRepo)
-	c.AnnotationQuery = appquery.NewAnnotationQuery(c.AnnotationRepo)
-	c.AnnotationTypeQuery = appquery.NewAnnotationTypeQuery(c.AnnotationTypeRepo)
+	c.AnnotationQuery = appquery.NewAnnotationQuery(c.AnnotationRepo, c.UOW)
+	c.AnnotationTypeQuery = appquery.NewAnnotationTypeQuery(c.AnnotationTypeRepo, c.UOW)
+	c.WebhookQuery = appquery.NewWebhookQuery(c.WebhookRepo, c.DeliveryRepo, c.WorkspaceRepo)
+	c.EventStreamQuery = appquery.NewEventStreamQuery(c.UOW.GetOutboxRepo(), c.Config.Stream.PollInterval)
+	c.EventLogQuery = appquery.NewEventLogQuery(c.EventLogRepo)
+	c.ProcessingQuery = appquery.NewProcessingQuery(c.ProcessingJobRepo)
 	c.Logger.Info("Queries initialized")
 	return nil
 }
@@ -229,12 +276,39 @@ func (c *Container) initEventInfrastructure(ctx context.Context) error {
 		domainevent.ImageProcessCompleteEventType: c.Config.PubSub.ImageProcessingResult.Topic.Name,
 	}
 
+	// Entity lifecycle events: one topic per entity type
+	entityTopics := map[vobj.EntityType]string{
+		vobj.EntityTypeWorkspace:      c.Config.PubSub.EntityEvents.Workspace.Name,
+		vobj.EntityTypePatient:        c.Config.PubSub.EntityEvents.Patient.Name,
+		vobj.EntityTypeImage:          c.Config.PubSub.EntityEvents.Image.Name,
+		vobj.EntityTypeAnnotation:     c.Config.PubSub.EntityEvents.Annotation.Name,
+		vobj.EntityTypeAnnotationType: c.Config.PubSub.EntityEvents.AnnotationType.Name,
+	}
+	for entityType, topic := range entityTopics {
+		for _, action := range []domainevent.EntityAction{
+			domainevent.EntityCreated,
+			domainevent.EntityUpdated,
+			domainevent.EntityDeleted,
+			domainevent.EntityTransferred,
+			domainevent.EntityProcessed,
+		} {
+			topicMapping[domainevent.EntityEventType(entityType, action)] = topic
+		}
+	}
+
+	// Every published and consumed event is recorded in the event log for replay
+	c.EventRecorder = apphandler.NewEventRecorder(
+		c.EventLogRepo,
+		pubsub.NewEventSerializer(),
+		c.Logger.WithGroup("event_log"),
+	)
+
 	// Create main event publisher
-	publisher, err := pubsub.NewPubSubPublisher(ctx, c.Config.GCP.ProjectID, topicMapping)
+	publisher, err := pubsub.NewPubSubPublisher(ctx, c.Config.GCP.ProjectID, topicMapping, c.Config.Events)
 	if err != nil {
 		return fmt.Errorf("failed to create event publisher: %w", err)
 	}
-	c.EventPublisher = publisher
+	c.EventPublisher = apphandler.NewRecordingPublisher(publisher, c.EventRecorder)
 
 	// Create subscribers
 	uploadSub, err := pubsub.NewPubSubSubscriber(
@@ -248,7 +322,7 @@ func (c *Container) initEventInfrastructure(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create upload subscriber: %w", err)
 	}
-	c.UploadSubscriber = uploadSub
+	c.UploadSubscriber = apphandler.NewRecordingSubscriber(uploadSub, c.EventRecorder, c.Config.PubSub.UploadStatus.Name)
 
 	processSub, err := pubsub.NewPubSubSubscriber(
 		ctx,
@@ -261,7 +335,7 @@ func (c *Container) initEventInfrastructure(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create process subscriber: %w", err)
 	}
-	c.ProcessSubscriber = processSub
+	c.ProcessSubscriber = apphandler.NewRecordingSubscriber(processSub, c.EventRecorder, c.Config.PubSub.ImageProcessingRequest.Subscription.Name)
 
 	completeSub, err := pubsub.NewPubSubSubscriber(
 		ctx,
@@ -274,19 +348,53 @@ func (c *Container) initEventInfrastructure(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create complete subscriber: %w", err)
 	}
-	c.CompleteSubscriber = completeSub
+	c.CompleteSubscriber = apphandler.NewRecordingSubscriber(completeSub, c.EventRecorder, c.Config.PubSub.ImageProcessingResult.Subscription.Name)
 
 	c.Logger.Info("Event infrastructure initialized")
 	return nil
 }
 
 func (c *Container) initWorkers(ctx context.Context) error {
-	worker, err := worker.NewCloudRunWorker(ctx, c.Config.Worker, c.Config.GCP, c.Logger)
-	if err != nil {
-		return fmt.Errorf("failed to create Cloud Run worker: %w", err)
+	// Decides per origin format what the workers produce
+	c.ProcessingPipelines = vobj.DefaultPipelineRegistry()
+
+	switch c.Config.Worker.Type {
+	case "local":
+		localWorker, err := worker.NewLocalWorker(
+			c.Config.Worker.Local,
+			c.Config.GCP,
+			c.EventPublisher,
+			pubsub.NewEventSerializer(),
+			c.Logger.WithGroup("local_worker"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create local worker: %w", err)
+		}
+		c.ImageProcessingWorker = localWorker
+	case "kubernetes":
+		clientset, err := worker.NewKubernetesClientset(c.Config.Worker.K8s.Kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+		k8sWorker, err := worker.NewKubernetesWorker(
+			clientset,
+			c.Config.Worker.K8s,
+			c.Config.GCP,
+			c.EventPublisher,
+			c.Logger.WithGroup("kubernetes_worker"),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes worker: %w", err)
+		}
+		c.ImageProcessingWorker = k8sWorker
+	default:
+		cloudRunWorker, err := worker.NewCloudRunWorker(ctx, c.Config.Worker, c.Config.GCP, c.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to create Cloud Run worker: %w", err)
+		}
+		c.ImageProcessingWorker = cloudRunWorker
 	}
-	c.ImageProcessingWorker = worker
-	c.Logger.Info("Workers initialized")
+	c.Logger.Info("Workers initialized", "type", c.Config.Worker.Type)
 	return nil
 }
 
@@ -296,26 +404,139 @@ func (c *Container) initEventHandlers(ctx context.Context) error {
 		c.UploadSubscriber,
 		c.UOW,
 		c.EventPublisher,
+		c.ProcessingPipelines,
 		c.Logger.WithGroup("upload_handler"),
 	)
 
+	// Starts queued images on the worker, taking turns between workspaces
+	c.ProcessingScheduler = apphandler.NewProcessingScheduler(
+		c.UOW,
+		c.QueueRepo,
+		c.ProcessingJobRepo,
+		c.ImageProcessingWorker,
+		c.Config.Queue.Concurrency,
+		c.Config.Queue.PollInterval,
+		c.Logger.WithGroup("processing_scheduler"),
+	)
+
 	// Image Process Handler
 	c.ImageProcessHandler = apphandler.NewImageProcessHandler(
 		c.ProcessSubscriber,
-		c.ImageProcessingWorker,
+		c.ProcessingScheduler,
 		c.ImageRepo,
 		c.Logger.WithGroup("image_process_handler"),
 	)
 
+	// Fails images whose execution died without reporting a result
+	c.ProcessingJobTracker = apphandler.NewProcessingJobTracker(
+		c.UOW,
+		c.ProcessingJobRepo,
+		c.ImageProcessingWorker,
+		c.Config.Worker.Type,
+		c.Config.JobTracker.PollInterval,
+		c.Config.JobTracker.BatchSize,
+		c.Config.JobTracker.NotFoundGrace,
+		c.Logger.WithGroup("processing_job_tracker"),
+	)
+
+	// Retries failed images and fails the ones stuck in processing
+	processRetry := c.Config.Retry.ImageProcess
+	c.ProcessingWatchdog = apphandler.NewProcessingWatchdog(
+		c.UOW,
+		c.ProcessingJobRepo,
+		c.ImageProcessingWorker,
+		c.Config.Worker.Type,
+		apphandler.RetryPolicy{
+			MaxAttempts: processRetry.MaxAttempts,
+			BaseBackoff: time.Duration(processRetry.BaseBackoffMs) * time.Millisecond,
+			MaxBackoff:  time.Duration(processRetry.MaxBackoffMs) * time.Millisecond,
+			Multiplier:  processRetry.BackoffMultiplier,
+		},
+		c.Config.Watchdog.ProcessingDeadline,
+		c.Config.Watchdog.PollInterval,
+		c.Config.Watchdog.BatchSize,
+		c.Logger.WithGroup("processing_watchdog"),
+	)
+
 	// Image Process Complete Handler
 	c.ImageProcessCompleteHandler = apphandler.NewImageProcessCompleteHandler(
 		c.CompleteSubscriber,
 		c.EventPublisher,
-		c.ImageRepo,
-		c.ContentRepo,
+		c.UOW,
 		c.Logger.WithGroup("image_process_complete_handler"),
 	)
 
+	// Webhooks: deliveries are queued from relayed events, then sent by the worker
+	c.WebhookDispatcher = apphandler.NewWebhookDispatcher(
+		c.WebhookRepo,
+		c.DeliveryRepo,
+		c.eventMarshaler(),
+		c.Logger.WithGroup("webhook_dispatcher"),
+	)
+
+	retry := c.Config.Retry.WebhookDelivery
+	c.WebhookDeliveryWorker = apphandler.NewWebhookDeliveryWorker(
+		c.WebhookRepo,
+		c.DeliveryRepo,
+		apphandler.NewWebhookHTTPClient(c.Config.Webhook.Timeout),
+		apphandler.RetryPolicy{
+			MaxAttempts: retry.MaxAttempts,
+			BaseBackoff: time.Duration(retry.BaseBackoffMs) * time.Millisecond,
+			MaxBackoff:  time.Duration(retry.MaxBackoffMs) * time.Millisecond,
+			Multiplier:  retry.BackoffMultiplier,
+		},
+		c.Config.Webhook.PollInterval,
+		c.Config.Webhook.BatchSize,
+		c.Logger.WithGroup("webhook_delivery_worker"),
+	)
+
+	c.TileCacheWarmer = apphandler.NewTileCacheWarmer(
+		c.Prefetcher,
+		c.Logger.WithGroup("tile_cache_warmer"),
+	)
+
+	c.OverlayInvalidator = apphandler.NewOverlayInvalidator(c.OverlayRenderer)
+
+	// Outbox Relay
+	outboxRetry := c.Config.Retry.Outbox
+	c.OutboxRelay = apphandler.NewOutboxRelay(
+		c.UOW.GetOutboxRepo(),
+		c.EventPublisher,
+		apphandler.RetryPolicy{
+			MaxAttempts: outboxRetry.MaxAttempts,
+			BaseBackoff: time.Duration(outboxRetry.BaseBackoffMs) * time.Millisecond,
+			MaxBackoff:  time.Duration(outboxRetry.MaxBackoffMs) * time.Millisecond,
+			Multiplier:  outboxRetry.BackoffMultiplier,
+		},
+		c.Config.Outbox.PollInterval,
+		c.Config.Outbox.BatchSize,
+		c.Logger.WithGroup("outbox_relay"),
+		c.WebhookDispatcher,
+		c.TileCacheWarmer,
+		c.OverlayInvalidator,
+	)
+
+	// Replay runs logged events through the same handlers as the subscribers
+	c.EventReplayUseCase = appusecase.NewEventReplayUseCase(
+		c.EventLogRepo,
+		pubsub.NewEventSerializer(),
+		map[domainevent.EventType]portevent.EventHandler{
+			domainevent.NewFileExistEventType:         c.NewFileHandler,
+			domainevent.ImageProcessReqEventType:      c.ImageProcessHandler,
+			domainevent.ImageProcessCompleteEventType: c.ImageProcessCompleteHandler,
+		},
+	)
+
+	// Needs the worker to cancel executions it started
+	c.ImageProcessingUseCase = appusecase.NewImageProcessingUseCase(
+		c.UOW,
+		c.ProcessingJobRepo,
+		c.ImageProcessingWorker,
+		c.Config.Worker.Type,
+		c.ProcessingPipelines,
+		c.TileServer,
+	)
+
 	c.Logger.Info("Event handlers initialized")
 	return nil
 }
@@ -348,6 +569,54 @@ func (c *Container) initSubscribers(ctx context.Context) error {
 		}
 	}()
 
+	// Start Outbox Relay
+	go func() {
+		c.Logger.Info("Starting outbox relay")
+		if err := c.OutboxRelay.Start(ctx); err != nil && err != context.Canceled {
+			c.Logger.Error("Outbox relay error", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Start Webhook Delivery Worker
+	go func() {
+		c.Logger.Info("Starting webhook delivery worker")
+		if err := c.WebhookDeliveryWorker.Start(ctx); err != nil && err != context.Canceled {
+			c.Logger.Error("Webhook delivery worker error", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Start Processing Job Tracker
+	go func() {
+		c.Logger.Info("Starting processing job tracker")
+		if err := c.ProcessingJobTracker.Start(ctx); err != nil && err != context.Canceled {
+			c.Logger.Error("Processing job tracker error", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Start Processing Scheduler
+	go func() {
+		c.Logger.Info("Starting processing scheduler")
+		if err := c.ProcessingScheduler.Start(ctx); err != nil && err != context.Canceled {
+			c.Logger.Error("Processing scheduler error", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Start Processing Watchdog
+	go func() {
+		c.Logger.Info("Starting processing watchdog")
+		if err := c.ProcessingWatchdog.Start(ctx); err != nil && err != context.Canceled {
+			c.Logger.Error("Processing watchdog error", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Start Tile Prefetcher
+	go func() {
+		c.Logger.Info("Starting tile prefetcher")
+		if err := c.Prefetcher.Start(ctx); err != nil && err != context.Canceled {
+			c.Logger.Error("Tile prefetcher error", slog.String("error", err.Error()))
+		}
+	}()
+
 	c.Logger.Info("All subscribers started")
 	return nil
 }
@@ -356,13 +625,71 @@ func (c *Container) initProxies(ctx context.Context) error {
 	// Initialize KeyBuilder for TileServer
 	keyBuilder := cache.NewKeyBuilder("tile_server")
 
+	// Tile payload cache: memory, optionally in front of local disk
+	var tiles cache.ByteCache
+	if tileConfig := c.Config.Tile; tileConfig.CacheMaxBytes > 0 {
+		tiles = inmemorycache.NewLRUByteCache(int64(tileConfig.CacheMaxBytes), int64(tileConfig.CacheMaxImageBytes))
+		if tileConfig.CacheDiskDir != "" {
+			disk, err := inmemorycache.NewDiskByteCache(
+				tileConfig.CacheDiskDir,
+				int64(tileConfig.CacheDiskMaxBytes),
+				int64(tileConfig.CacheMaxImageBytes),
+				c.Logger,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to initialize tile disk cache: %w", err)
+			}
+			tiles = inmemorycache.NewTieredByteCache(tiles, disk)
+		}
+	}
+
+	var blankColor color.Color
+	if c.Config.Tile.BlankTileColor != "" {
+		parsed, err := proxy.ParseHexColor(c.Config.Tile.BlankTileColor)
+		if err != nil {
+			return fmt.Errorf("failed to parse blank tile color: %w", err)
+		}
+		blankColor = parsed
+	}
+
 	// Initialize TileServer
 	c.TileServer = proxy.NewTileServer(
 		c.Cache,
 		keyBuilder,
 		c.ContentRepo,
 		c.ImageRepo,
+		c.WorkspaceRepo,
 		c.ProcessedStorage,
+		tiles,
+		blankColor,
+		c.Config.Tile.MaxRegionPixels,
+	)
+
+	c.Prefetcher = proxy.NewPrefetcher(
+		c.TileServer,
+		c.Config.Tile.PrefetchWorkers,
+		c.Config.Tile.PrefetchQueueSize,
+		c.Config.Tile.WarmMaxTiles,
+		c.Logger.WithGroup("tile_prefetcher"),
+	)
+
+	c.OverlayRenderer = proxy.NewOverlayRenderer(
+		c.TileServer,
+		c.Cache,
+		cache.NewKeyBuilder("overlay"),
+		c.AnnotationRepo,
+		c.AnnotationTypeRepo,
+		c.Config.Tile.OverlayCacheTTL,
+	)
+
+	c.ImageAccess = proxy.NewImageAccess(
+		c.Cache,
+		cache.NewKeyBuilder("image_access"),
+		c.ImageRepo,
+		c.WorkspaceRepo,
+		c.Config.Tile.AccessCacheTTL,
+		[]byte(c.Config.Tile.URLSigningKey),
+		c.Config.Tile.SignedURLTTL,
 	)
 
 	c.Logger.Info("Proxies initialized")
@@ -409,13 +736,67 @@ func (c *Container) initHTTPLayer(ctx context.Context) error {
 		30*time.Second,
 		c.Logger,
 	)
+	c.ImageAccessMiddleware = middleware.NewImageAccessMiddleware(
+		c.ImageAccess,
+		c.Logger,
+	)
 
 	// Tile Proxy Handler
 	c.TileProxyHandler = handler.NewTileProxyHandler(
+		c.TileServer,
+		c.ImageAccess,
+		c.Prefetcher,
+		c.Logger,
+	)
+
+	// IIIF Handler
+	c.IIIFHandler = handler.NewIIIFHandler(
 		c.TileServer,
 		c.Logger,
 	)
 
+	// Annotation Overlay Handler
+	c.OverlayHandler = handler.NewOverlayHandler(
+		c.OverlayRenderer,
+		c.Logger,
+	)
+
+	// Image Region Handler
+	c.ImageRegionHandler = handler.NewImageRegionHandler(
+		c.TileServer,
+		c.Logger,
+	)
+
+	// Webhook Handler
+	c.WebhookHandler = handler.NewWebhookHandler(
+		c.WebhookQuery,
+		c.WebhookUseCase,
+		c.Logger,
+	)
+
+	// Event Stream Handler
+	c.EventStreamHandler = handler.NewEventStreamHandler(
+		c.WorkspaceQuery,
+		c.EventStreamQuery,
+		c.eventMarshaler(),
+		c.Config.Stream.KeepAlive,
+		c.Logger,
+	)
+
+	// Image Processing Handler
+	c.ImageProcessingHandler = handler.NewImageProcessingHandler(
+		c.ImageProcessingUseCase,
+		c.ProcessingQuery,
+		c.Logger,
+	)
+
+	// Admin Event Replay Handler
+	c.EventReplayHandler = handler.NewEventReplayHandler(
+		c.EventLogQuery,
+		c.EventReplayUseCase,
+		c.Logger,
+	)
+
 	// Router
 	routerConfig := &router.RouterConfig{
 		Logger:         c.Logger,
@@ -427,17 +808,35 @@ func (c *Container) initHTTPLayer(ctx context.Context) error {
 		c.WorkspaceHandler,
 		c.PatientHandler,
 		c.ImageHandler,
+		c.ImageProcessingHandler,
 		c.AnnotationHandler,
 		c.AnnotationTypeHandler,
 		c.TileProxyHandler,
+		c.IIIFHandler,
+		c.OverlayHandler,
+		c.ImageRegionHandler,
+		c.WebhookHandler,
+		c.EventStreamHandler,
+		c.EventReplayHandler,
 		c.AuthMiddleware,
 		c.TimeoutMiddleware,
+		c.ImageAccessMiddleware,
 	)
 
 	c.Logger.Info("HTTP layer initialized")
 	return nil
 }
 
+// eventMarshaler renders events as CloudEvents JSON for webhooks and SSE.
+func (c *Container) eventMarshaler() portevent.EventMarshaler {
+	return pubsub.NewMessageCodec(
+		pubsub.NewEventSerializer(),
+		pubsub.EncodingStructured,
+		c.Config.Events.Source,
+		c.Config.Events.DataSchemaBaseURL,
+	)
+}
+
 func (c *Container) Close() error {
 	c.Logger.Info("Closing container resources...")
 
@@ -465,6 +864,50 @@ func (c *Container) Close() error {
 		}
 	}
 
+	if c.OutboxRelay != nil {
+		if err := c.OutboxRelay.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("outbox relay stop: %w", err))
+		}
+	}
+
+	if c.WebhookDeliveryWorker != nil {
+		if err := c.WebhookDeliveryWorker.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("webhook delivery worker stop: %w", err))
+		}
+	}
+
+	if c.ProcessingJobTracker != nil {
+		if err := c.ProcessingJobTracker.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("processing job tracker stop: %w", err))
+		}
+	}
+
+	if c.ProcessingWatchdog != nil {
+		if err := c.ProcessingWatchdog.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("processing watchdog stop: %w", err))
+		}
+	}
+
+	if c.ProcessingScheduler != nil {
+		if err := c.ProcessingScheduler.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("processing scheduler stop: %w", err))
+		}
+	}
+
+	if c.Prefetcher != nil {
+		if err := c.Prefetcher.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("tile prefetcher stop: %w", err))
+		}
+	}
+
+	// Stop job tracking; local jobs are killed and report their failure
+	// before the clients close
+	if stopper, ok := c.ImageProcessingWorker.(interface{ Stop() error }); ok {
+		if err := stopper.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("image processing worker stop: %w", err))
+		}
+	}
+
 	// Close other resources
 	if c.FirestoreClient != nil {
 		if err := c.FirestoreClient.Close(); err != nil {